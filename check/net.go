@@ -0,0 +1,98 @@
+package check
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Addr is the type of a check function for an IP address. It takes a
+// netip.Addr parameter and returns an error or nil if the check passes
+type Addr func(a netip.Addr) error
+
+// Prefix is the type of a check function for an IP prefix (a CIDR
+// block). It takes a netip.Prefix parameter and returns an error or nil if
+// the check passes
+type Prefix func(p netip.Prefix) error
+
+// URL is the type of a check function for a URL. It takes a pointer to a
+// url.URL parameter and returns an error or nil if the check passes
+type URL func(u *url.URL) error
+
+// AddrIsLoopback returns a function that will check that the address is a
+// loopback address
+func AddrIsLoopback() Addr {
+	return func(a netip.Addr) error {
+		if a.IsLoopback() {
+			return nil
+		}
+		return fmt.Errorf("the address (%s) must be a loopback address", a)
+	}
+}
+
+// AddrInPrefix returns a function that will check that the address lies
+// within the prefix
+func AddrInPrefix(p netip.Prefix) Addr {
+	if !p.IsValid() {
+		panic("Impossible check passed to AddrInPrefix: the prefix is invalid")
+	}
+
+	return func(a netip.Addr) error {
+		if p.Contains(a.Unmap()) {
+			return nil
+		}
+		return fmt.Errorf("the address (%s) must be in %s", a, p)
+	}
+}
+
+// PrefixWithin returns a function that will check that the prefix lies
+// entirely within the outer prefix
+func PrefixWithin(outer netip.Prefix) Prefix {
+	if !outer.IsValid() {
+		panic("Impossible check passed to PrefixWithin:" +
+			" the outer prefix is invalid")
+	}
+
+	return func(p netip.Prefix) error {
+		if p.Bits() >= outer.Bits() && outer.Contains(p.Addr()) {
+			return nil
+		}
+		return fmt.Errorf("the prefix (%s) must be within %s", p, outer)
+	}
+}
+
+// URLSchemeIs returns a function that will check that the scheme of the
+// URL is one of the given schemes. Schemes are compared ignoring case.
+func URLSchemeIs(schemes ...string) URL {
+	if len(schemes) == 0 {
+		panic("Impossible check passed to URLSchemeIs: no schemes are given")
+	}
+
+	return func(u *url.URL) error {
+		for _, s := range schemes {
+			if strings.EqualFold(u.Scheme, s) {
+				return nil
+			}
+		}
+		if len(schemes) == 1 {
+			return fmt.Errorf("the URL scheme (%s) must be %s",
+				u.Scheme, schemes[0])
+		}
+		return fmt.Errorf("the URL scheme (%s) must be one of: %s",
+			u.Scheme, strings.Join(schemes, ", "))
+	}
+}
+
+// URLHostMatches returns a function that checks that the host name of the
+// URL (without any port) matches the supplied regexp
+func URLHostMatches(re *regexp.Regexp, reDesc string) URL {
+	return func(u *url.URL) error {
+		if !re.MatchString(u.Hostname()) {
+			return fmt.Errorf("the URL host (%s) does not match the pattern: %s",
+				u.Hostname(), reDesc)
+		}
+		return nil
+	}
+}
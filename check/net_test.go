@@ -0,0 +1,217 @@
+package check_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/testhelper"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+// checkResult reports any problem with the error returned by a check
+func checkResult(t *testing.T, testID string, err error, errExpected bool, errMustContain []string) {
+	t.Helper()
+	if err != nil {
+		if !errExpected {
+			t.Log(testID)
+			t.Errorf("\t: there was an unexpected err: %s\n", err)
+		} else {
+			testhelper.ShouldContain(t, testID, "error", err.Error(),
+				errMustContain)
+		}
+	} else if errExpected {
+		t.Log(testID)
+		t.Errorf("\t: an error was expected but none was returned\n")
+	}
+}
+
+func TestAddr(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkFunc      check.Addr
+		addr           string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:      "IsLoopback: 127.0.0.1",
+			checkFunc: check.AddrIsLoopback(),
+			addr:      "127.0.0.1",
+		},
+		{
+			name:      "IsLoopback: ::1",
+			checkFunc: check.AddrIsLoopback(),
+			addr:      "::1",
+		},
+		{
+			name:           "IsLoopback: 192.0.2.1",
+			checkFunc:      check.AddrIsLoopback(),
+			addr:           "192.0.2.1",
+			errExpected:    true,
+			errMustContain: []string{"must be a loopback address"},
+		},
+		{
+			name:      "InPrefix: 10.1.2.3 in 10.0.0.0/8",
+			checkFunc: check.AddrInPrefix(netip.MustParsePrefix("10.0.0.0/8")),
+			addr:      "10.1.2.3",
+		},
+		{
+			name:      "InPrefix: IPv4-mapped 10.1.2.3 in 10.0.0.0/8",
+			checkFunc: check.AddrInPrefix(netip.MustParsePrefix("10.0.0.0/8")),
+			addr:      "::ffff:10.1.2.3",
+		},
+		{
+			name:           "InPrefix: 11.1.2.3 not in 10.0.0.0/8",
+			checkFunc:      check.AddrInPrefix(netip.MustParsePrefix("10.0.0.0/8")),
+			addr:           "11.1.2.3",
+			errExpected:    true,
+			errMustContain: []string{"must be in 10.0.0.0/8"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		checkResult(t, testID,
+			tc.checkFunc(netip.MustParseAddr(tc.addr)),
+			tc.errExpected, tc.errMustContain)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	outer := netip.MustParsePrefix("10.0.0.0/8")
+	testCases := []struct {
+		name           string
+		prefix         string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:   "same",
+			prefix: "10.0.0.0/8",
+		},
+		{
+			name:   "narrower",
+			prefix: "10.20.0.0/16",
+		},
+		{
+			name:           "wider",
+			prefix:         "10.0.0.0/7",
+			errExpected:    true,
+			errMustContain: []string{"must be within 10.0.0.0/8"},
+		},
+		{
+			name:           "outside",
+			prefix:         "11.0.0.0/16",
+			errExpected:    true,
+			errMustContain: []string{"must be within 10.0.0.0/8"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		checkResult(t, testID,
+			check.PrefixWithin(outer)(netip.MustParsePrefix(tc.prefix)),
+			tc.errExpected, tc.errMustContain)
+	}
+}
+
+func TestURL(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkFunc      check.URL
+		url            string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:      "SchemeIs: https",
+			checkFunc: check.URLSchemeIs("http", "https"),
+			url:       "HTTPS://example.com",
+		},
+		{
+			name:           "SchemeIs: ftp",
+			checkFunc:      check.URLSchemeIs("http", "https"),
+			url:            "ftp://example.com",
+			errExpected:    true,
+			errMustContain: []string{"(ftp) must be one of: http, https"},
+		},
+		{
+			name:           "SchemeIs: only one scheme",
+			checkFunc:      check.URLSchemeIs("https"),
+			url:            "http://example.com",
+			errExpected:    true,
+			errMustContain: []string{"(http) must be https"},
+		},
+		{
+			name: "HostMatches: good",
+			checkFunc: check.URLHostMatches(
+				regexp.MustCompile(`\.example\.com$`), "an example.com host"),
+			url: "https://api.example.com:8443/v1",
+		},
+		{
+			name: "HostMatches: bad",
+			checkFunc: check.URLHostMatches(
+				regexp.MustCompile(`\.example\.com$`), "an example.com host"),
+			url:         "https://example.org/v1",
+			errExpected: true,
+			errMustContain: []string{
+				"the URL host (example.org)",
+				"an example.com host",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		u, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatal(testID, ": bad URL: ", err)
+		}
+		checkResult(t, testID, tc.checkFunc(u),
+			tc.errExpected, tc.errMustContain)
+	}
+}
+
+func TestNetCheckPanic(t *testing.T) {
+	testCases := []struct {
+		name             string
+		f                func()
+		panicExpected    bool
+		panicMustContain []string
+	}{
+		{
+			name:             "AddrInPrefix: invalid prefix",
+			f:                func() { check.AddrInPrefix(netip.Prefix{}) },
+			panicExpected:    true,
+			panicMustContain: []string{"AddrInPrefix: the prefix is invalid"},
+		},
+		{
+			name:          "PrefixWithin: invalid prefix",
+			f:             func() { check.PrefixWithin(netip.Prefix{}) },
+			panicExpected: true,
+			panicMustContain: []string{
+				"PrefixWithin: the outer prefix is invalid",
+			},
+		},
+		{
+			name:             "URLSchemeIs: no schemes",
+			f:                func() { check.URLSchemeIs() },
+			panicExpected:    true,
+			panicMustContain: []string{"URLSchemeIs: no schemes are given"},
+		},
+		{
+			name: "URLSchemeIs: ok",
+			f:    func() { check.URLSchemeIs("https") },
+		},
+	}
+
+	for i, tc := range testCases {
+		testName := fmt.Sprintf("%d: %s", i, tc.name)
+		panicked, panicVal := panicSafeTestTimeCheck(t, tc.f)
+		testhelper.PanicCheckString(t, testName,
+			panicked, tc.panicExpected,
+			panicVal, tc.panicMustContain)
+	}
+}
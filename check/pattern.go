@@ -0,0 +1,106 @@
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+)
+
+// StringMatcher is the interface satisfied by compiled patterns such as a
+// *regexp.Regexp or a psetter.Glob
+type StringMatcher interface {
+	MatchString(s string) bool
+	String() string
+}
+
+// Pattern is the type of a check function for a compiled pattern. It takes
+// the pattern as a parameter and returns an error or nil if the check
+// passes
+type Pattern func(p StringMatcher) error
+
+// PatternNotMatchEmpty returns a function that will check that the pattern
+// does not match the empty string. This can be used to catch patterns
+// which would match everything
+func PatternNotMatchEmpty() Pattern {
+	return func(p StringMatcher) error {
+		if p.MatchString("") {
+			return fmt.Errorf("the pattern '%s' must not match the empty string",
+				p)
+		}
+		return nil
+	}
+}
+
+// PatternMatches returns a function that will check that the pattern
+// matches the example string
+func PatternMatches(example string) Pattern {
+	return func(p StringMatcher) error {
+		if !p.MatchString(example) {
+			return fmt.Errorf("the pattern '%s' must match '%s'", p, example)
+		}
+		return nil
+	}
+}
+
+// PatternNotMatches returns a function that will check that the pattern
+// does not match the example string
+func PatternNotMatches(example string) Pattern {
+	return func(p StringMatcher) error {
+		if p.MatchString(example) {
+			return fmt.Errorf("the pattern '%s' must not match '%s'",
+				p, example)
+		}
+		return nil
+	}
+}
+
+// PatternIsAnchored returns a function that will check that a regular
+// expression is anchored at both ends, so that it must match the whole of
+// the string, for instance "^abc$". Any other sort of pattern, such as a
+// glob, which always matches the whole string, passes the check
+func PatternIsAnchored() Pattern {
+	return func(p StringMatcher) error {
+		re, ok := p.(*regexp.Regexp)
+		if !ok {
+			return nil
+		}
+		sre, err := syntax.Parse(re.String(), syntax.Perl)
+		if err != nil {
+			return fmt.Errorf("the pattern '%s' cannot be parsed: %s", re, err)
+		}
+		if !isAnchored(sre, 0, syntax.OpBeginText) {
+			return fmt.Errorf("the pattern '%s' must start with '^'", re)
+		}
+		if !isAnchored(sre, -1, syntax.OpEndText) {
+			return fmt.Errorf("the pattern '%s' must end with '$'", re)
+		}
+		return nil
+	}
+}
+
+// isAnchored returns true if every way through the regular expression
+// starts (if end is 0) or finishes (if end is -1) with the anchor op
+func isAnchored(re *syntax.Regexp, end int, anchor syntax.Op) bool {
+	switch re.Op {
+	case anchor:
+		return true
+	case syntax.OpCapture:
+		return isAnchored(re.Sub[0], end, anchor)
+	case syntax.OpConcat:
+		if len(re.Sub) == 0 {
+			return false
+		}
+		if end == 0 {
+			return isAnchored(re.Sub[0], end, anchor)
+		}
+		return isAnchored(re.Sub[len(re.Sub)-1], end, anchor)
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !isAnchored(sub, end, anchor) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
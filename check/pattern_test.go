@@ -0,0 +1,107 @@
+package check_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// prefixMatcher is a StringMatcher which is not a regular expression
+type prefixMatcher string
+
+func (pm prefixMatcher) MatchString(s string) bool {
+	return strings.HasPrefix(s, string(pm))
+}
+
+func (pm prefixMatcher) String() string { return string(pm) }
+
+func TestPattern(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkFunc      check.Pattern
+		p              check.StringMatcher
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:      "NotMatchEmpty: a+",
+			checkFunc: check.PatternNotMatchEmpty(),
+			p:         regexp.MustCompile("a+"),
+		},
+		{
+			name:           "NotMatchEmpty: a*",
+			checkFunc:      check.PatternNotMatchEmpty(),
+			p:              regexp.MustCompile("a*"),
+			errExpected:    true,
+			errMustContain: []string{"'a*' must not match the empty string"},
+		},
+		{
+			name:      "Matches: a+ matches xay",
+			checkFunc: check.PatternMatches("xay"),
+			p:         regexp.MustCompile("a+"),
+		},
+		{
+			name:           "Matches: a+ does not match xy",
+			checkFunc:      check.PatternMatches("xy"),
+			p:              regexp.MustCompile("a+"),
+			errExpected:    true,
+			errMustContain: []string{"'a+' must match 'xy'"},
+		},
+		{
+			name:           "NotMatches: prefix matcher",
+			checkFunc:      check.PatternNotMatches("abc"),
+			p:              prefixMatcher("ab"),
+			errExpected:    true,
+			errMustContain: []string{"'ab' must not match 'abc'"},
+		},
+		{
+			name:      "IsAnchored: ^a+$",
+			checkFunc: check.PatternIsAnchored(),
+			p:         regexp.MustCompile("^a+$"),
+		},
+		{
+			name:      "IsAnchored: ^(a|b)$",
+			checkFunc: check.PatternIsAnchored(),
+			p:         regexp.MustCompile("^(a|b)$"),
+		},
+		{
+			name:      "IsAnchored: ^a$|^b$",
+			checkFunc: check.PatternIsAnchored(),
+			p:         regexp.MustCompile("^a$|^b$"),
+		},
+		{
+			name:           "IsAnchored: ^a$|b$",
+			checkFunc:      check.PatternIsAnchored(),
+			p:              regexp.MustCompile("^a$|b$"),
+			errExpected:    true,
+			errMustContain: []string{"must start with '^'"},
+		},
+		{
+			name:           "IsAnchored: ^a",
+			checkFunc:      check.PatternIsAnchored(),
+			p:              regexp.MustCompile("^a"),
+			errExpected:    true,
+			errMustContain: []string{"'^a' must end with '$'"},
+		},
+		{
+			name:           "IsAnchored: (?m)^a$",
+			checkFunc:      check.PatternIsAnchored(),
+			p:              regexp.MustCompile("(?m)^a$"),
+			errExpected:    true,
+			errMustContain: []string{"must start with '^'"},
+		},
+		{
+			name:      "IsAnchored: not a regexp",
+			checkFunc: check.PatternIsAnchored(),
+			p:         prefixMatcher("a"),
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		err := tc.checkFunc(tc.p)
+		checkResult(t, testID, err, tc.errExpected, tc.errMustContain)
+	}
+}
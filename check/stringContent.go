@@ -0,0 +1,137 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringHasPrefix returns a function that will check that the string
+// starts with the prefix
+func StringHasPrefix(prefix string) String {
+	return func(s string) error {
+		if strings.HasPrefix(s, prefix) {
+			return nil
+		}
+		return fmt.Errorf("the value (%q) must start with %q", s, prefix)
+	}
+}
+
+// StringHasSuffix returns a function that will check that the string ends
+// with the suffix
+func StringHasSuffix(suffix string) String {
+	return func(s string) error {
+		if strings.HasSuffix(s, suffix) {
+			return nil
+		}
+		return fmt.Errorf("the value (%q) must end with %q", s, suffix)
+	}
+}
+
+// StringContains returns a function that will check that the string
+// contains the substring
+func StringContains(substr string) String {
+	return func(s string) error {
+		if strings.Contains(s, substr) {
+			return nil
+		}
+		return fmt.Errorf("the value (%q) must contain %q", s, substr)
+	}
+}
+
+// StringIsOneOf returns a function that will check that the string is one
+// of the given values. If you want the value to be set from a list of
+// allowed values you should consider using a psetter.EnumSetter instead
+func StringIsOneOf(vals ...string) String {
+	if len(vals) == 0 {
+		panic("Impossible checks passed to StringIsOneOf:" +
+			" no values have been given")
+	}
+
+	return func(s string) error {
+		for _, v := range vals {
+			if s == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("the value (%q) must be one of: %s",
+			s, strings.Join(vals, ", "))
+	}
+}
+
+// StringNot returns a function that will check that the value does not
+// pass the check func. The desc should describe what the check func
+// checks; it is used to report the error, for instance, a desc of "start
+// with 'x'" gives an error of: the value ("xyz") must not start with 'x'
+func StringNot(chkFunc String, desc string) String {
+	return func(s string) error {
+		if chkFunc(s) != nil {
+			return nil
+		}
+		return fmt.Errorf("the value (%q) must not %s", s, desc)
+	}
+}
+
+// StringAllCharsIn returns a function that will check that every character
+// in the string is in the class. The class func should return true if the
+// rune is in the class and the desc should describe the class. The error
+// reports the first character not in the class
+func StringAllCharsIn(class func(r rune) bool, desc string) String {
+	return func(s string) error {
+		for i, r := range s {
+			if !class(r) {
+				return fmt.Errorf(
+					"the value (%q) must only contain %s characters"+
+						" - %q at byte %d is not",
+					s, desc, r, i)
+			}
+		}
+		return nil
+	}
+}
+
+// StringIsASCII returns a function that will check that the string only
+// contains ASCII characters
+func StringIsASCII() String {
+	return StringAllCharsIn(
+		func(r rune) bool { return r <= unicode.MaxASCII }, "ASCII")
+}
+
+// StringIsPrintable returns a function that will check that the string
+// only contains printable characters, as defined by unicode.IsPrint
+func StringIsPrintable() String {
+	return StringAllCharsIn(unicode.IsPrint, "printable")
+}
+
+// StringHasIdentChars returns a function that will check that the string
+// only contains characters which may appear in an identifier: letters,
+// digits and underscores. Note that this does not check that the string
+// is a valid identifier, see StringIsGoIdentifier
+func StringHasIdentChars() String {
+	return StringAllCharsIn(
+		func(r rune) bool {
+			return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+		},
+		"identifier")
+}
+
+// StringIsValidUTF8 returns a function that will check that the string is
+// made up entirely of valid UTF-8 encoded runes
+func StringIsValidUTF8() String {
+	return func(s string) error {
+		if utf8.ValidString(s) {
+			return nil
+		}
+		for i := 0; i < len(s); {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				return fmt.Errorf(
+					"the value (%q) must be valid UTF-8"+
+						" - the byte at %d is not", s, i)
+			}
+			i += size
+		}
+		return fmt.Errorf("the value (%q) must be valid UTF-8", s)
+	}
+}
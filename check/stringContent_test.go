@@ -0,0 +1,169 @@
+package check_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+	"unicode"
+)
+
+func TestStringContent(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkFunc      check.String
+		val            string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:      "HasPrefix: abc has prefix ab",
+			checkFunc: check.StringHasPrefix("ab"),
+			val:       "abc",
+		},
+		{
+			name:           "HasPrefix: abc has no prefix b",
+			checkFunc:      check.StringHasPrefix("b"),
+			val:            "abc",
+			errExpected:    true,
+			errMustContain: []string{`the value ("abc") must start with "b"`},
+		},
+		{
+			name:      "HasSuffix: abc has suffix bc",
+			checkFunc: check.StringHasSuffix("bc"),
+			val:       "abc",
+		},
+		{
+			name:           "HasSuffix: abc has no suffix b",
+			checkFunc:      check.StringHasSuffix("b"),
+			val:            "abc",
+			errExpected:    true,
+			errMustContain: []string{`must end with "b"`},
+		},
+		{
+			name:      "Contains: abc contains b",
+			checkFunc: check.StringContains("b"),
+			val:       "abc",
+		},
+		{
+			name:           "Contains: abc does not contain x",
+			checkFunc:      check.StringContains("x"),
+			val:            "abc",
+			errExpected:    true,
+			errMustContain: []string{`must contain "x"`},
+		},
+		{
+			name:      "IsOneOf: b is one of a, b",
+			checkFunc: check.StringIsOneOf("a", "b"),
+			val:       "b",
+		},
+		{
+			name:           "IsOneOf: c is not one of a, b",
+			checkFunc:      check.StringIsOneOf("a", "b"),
+			val:            "c",
+			errExpected:    true,
+			errMustContain: []string{`the value ("c") must be one of: a, b`},
+		},
+		{
+			name:      "Not: abc does not start with x",
+			checkFunc: check.StringNot(check.StringHasPrefix("x"), "start with 'x'"),
+			val:       "abc",
+		},
+		{
+			name:           "Not: xyz starts with x",
+			checkFunc:      check.StringNot(check.StringHasPrefix("x"), "start with 'x'"),
+			val:            "xyz",
+			errExpected:    true,
+			errMustContain: []string{`the value ("xyz") must not start with 'x'`},
+		},
+		{
+			name:      "AllCharsIn: digits",
+			checkFunc: check.StringAllCharsIn(unicode.IsDigit, "digit"),
+			val:       "0123",
+		},
+		{
+			name:        "AllCharsIn: not all digits",
+			checkFunc:   check.StringAllCharsIn(unicode.IsDigit, "digit"),
+			val:         "01a3",
+			errExpected: true,
+			errMustContain: []string{
+				"must only contain digit characters",
+				"'a' at byte 2 is not",
+			},
+		},
+		{
+			name:      "IsASCII: ascii",
+			checkFunc: check.StringIsASCII(),
+			val:       "hello, world\n",
+		},
+		{
+			name:           "IsASCII: not ascii",
+			checkFunc:      check.StringIsASCII(),
+			val:            "héllo",
+			errExpected:    true,
+			errMustContain: []string{"must only contain ASCII", "'é' at byte 1"},
+		},
+		{
+			name:      "IsPrintable: printable",
+			checkFunc: check.StringIsPrintable(),
+			val:       "héllo, world",
+		},
+		{
+			name:           "IsPrintable: not printable",
+			checkFunc:      check.StringIsPrintable(),
+			val:            "a\tb",
+			errExpected:    true,
+			errMustContain: []string{`'\t' at byte 1 is not`},
+		},
+		{
+			name:      "HasIdentChars: ok",
+			checkFunc: check.StringHasIdentChars(),
+			val:       "_x9é",
+		},
+		{
+			name:           "HasIdentChars: bad",
+			checkFunc:      check.StringHasIdentChars(),
+			val:            "x-y",
+			errExpected:    true,
+			errMustContain: []string{"must only contain identifier characters"},
+		},
+		{
+			name:      "IsValidUTF8: ok",
+			checkFunc: check.StringIsValidUTF8(),
+			val:       "héllo",
+		},
+		{
+			name:           "IsValidUTF8: bad",
+			checkFunc:      check.StringIsValidUTF8(),
+			val:            "ab\xffc",
+			errExpected:    true,
+			errMustContain: []string{"must be valid UTF-8", "the byte at 2"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		err := tc.checkFunc(tc.val)
+		checkResult(t, testID, err, tc.errExpected, tc.errMustContain)
+	}
+}
+
+func TestStringIsOneOfPanic(t *testing.T) {
+	panicked, panicVal := panicSafeStringIsOneOf()
+	testhelper.PanicCheckString(t, "StringIsOneOf: no values",
+		panicked, true,
+		panicVal, []string{"Impossible checks passed to StringIsOneOf"})
+}
+
+// panicSafeStringIsOneOf calls StringIsOneOf with no values, returning true
+// if it panicked and the panic value
+func panicSafeStringIsOneOf() (panicked bool, panicVal interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			panicVal = r
+		}
+	}()
+	check.StringIsOneOf()
+	return false, nil
+}
@@ -0,0 +1,131 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// maxHostnameLen and maxHostnameLabelLen are the limits from RFC 1123
+const (
+	maxHostnameLen      = 253
+	maxHostnameLabelLen = 63
+)
+
+var (
+	hostnameLabelRE = regexp.MustCompile(
+		`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	semverRE = regexp.MustCompile(
+		`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+			`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)` +
+			`(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+			`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	uuidRE = regexp.MustCompile(
+		`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+			`[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// StringIsEmail returns a function that will check that the string is a
+// valid email address, as parsed by net/mail, without a display name or
+// angle brackets, for instance "user@example.com"
+func StringIsEmail() String {
+	return func(s string) error {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return fmt.Errorf("the value (%q) must be a valid email address"+
+				": %s", s, err)
+		}
+		if addr.Address != s {
+			return fmt.Errorf("the value (%q) must be a valid email address"+
+				" - it should be just the address: %q", s, addr.Address)
+		}
+		return nil
+	}
+}
+
+// StringIsHostname returns a function that will check that the string is a
+// valid hostname as described in RFC 1123. It must be no longer than 253
+// characters and each dot-separated label must be from 1 to 63 letters,
+// digits or hyphens, not starting or ending with a hyphen. A single
+// trailing dot is allowed
+func StringIsHostname() String {
+	return func(s string) error {
+		name := strings.TrimSuffix(s, ".")
+		if name == "" {
+			return fmt.Errorf("the value (%q) must be a valid hostname"+
+				" - it is empty", s)
+		}
+		if len(name) > maxHostnameLen {
+			return fmt.Errorf("the value (%q) must be a valid hostname"+
+				" - it is longer than %d characters", s, maxHostnameLen)
+		}
+		for _, label := range strings.Split(name, ".") {
+			if len(label) > maxHostnameLabelLen {
+				return fmt.Errorf("the value (%q) must be a valid hostname"+
+					" - the part %q is longer than %d characters",
+					s, label, maxHostnameLabelLen)
+			}
+			if !hostnameLabelRE.MatchString(label) {
+				return fmt.Errorf("the value (%q) must be a valid hostname"+
+					" - the part %q is invalid", s, label)
+			}
+		}
+		return nil
+	}
+}
+
+// StringIsSemver returns a function that will check that the string is a
+// valid semantic version as described at https://semver.org, for instance
+// "1.2.3" or "1.0.0-rc.1+build.5". Note that a leading 'v' is not allowed
+func StringIsSemver() String {
+	return func(s string) error {
+		if semverRE.MatchString(s) {
+			return nil
+		}
+		return fmt.Errorf("the value (%q) must be a valid semantic version"+
+			" (such as 1.2.3)", s)
+	}
+}
+
+// StringIsUUID returns a function that will check that the string is a
+// UUID in the standard form of 32 hexadecimal digits in groups of 8-4-4-4-12
+// separated by hyphens. Upper or lower case digits are allowed
+func StringIsUUID() String {
+	return func(s string) error {
+		if uuidRE.MatchString(s) {
+			return nil
+		}
+		return fmt.Errorf("the value (%q) must be a valid UUID", s)
+	}
+}
+
+// StringIsJSON returns a function that will check that the string is valid
+// JSON
+func StringIsJSON() String {
+	return func(s string) error {
+		if json.Valid([]byte(s)) {
+			return nil
+		}
+		var v interface{}
+		err := json.Unmarshal([]byte(s), &v)
+		return fmt.Errorf("the value (%q) must be valid JSON: %s", s, err)
+	}
+}
+
+// StringIsGoIdentifier returns a function that will check that the string
+// is a valid Go identifier which is not a keyword
+func StringIsGoIdentifier() String {
+	return func(s string) error {
+		if token.IsIdentifier(s) {
+			return nil
+		}
+		if token.IsKeyword(s) {
+			return fmt.Errorf("the value (%q) must be a valid Go identifier"+
+				" - it is a keyword", s)
+		}
+		return fmt.Errorf("the value (%q) must be a valid Go identifier", s)
+	}
+}
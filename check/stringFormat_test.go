@@ -0,0 +1,149 @@
+package check_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"strings"
+	"testing"
+)
+
+func TestStringFormat(t *testing.T) {
+	testCases := []struct {
+		name           string
+		checkFunc      check.String
+		val            string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:      "IsEmail: ok",
+			checkFunc: check.StringIsEmail(),
+			val:       "user.name@example.com",
+		},
+		{
+			name:           "IsEmail: no @",
+			checkFunc:      check.StringIsEmail(),
+			val:            "user.example.com",
+			errExpected:    true,
+			errMustContain: []string{"must be a valid email address"},
+		},
+		{
+			name:           "IsEmail: display name",
+			checkFunc:      check.StringIsEmail(),
+			val:            "User <user@example.com>",
+			errExpected:    true,
+			errMustContain: []string{`just the address: "user@example.com"`},
+		},
+		{
+			name:      "IsHostname: ok",
+			checkFunc: check.StringIsHostname(),
+			val:       "a-1.example.com.",
+		},
+		{
+			name:      "IsHostname: leading digit",
+			checkFunc: check.StringIsHostname(),
+			val:       "3com.com",
+		},
+		{
+			name:           "IsHostname: leading hyphen",
+			checkFunc:      check.StringIsHostname(),
+			val:            "-a.example.com",
+			errExpected:    true,
+			errMustContain: []string{`the part "-a" is invalid`},
+		},
+		{
+			name:           "IsHostname: empty label",
+			checkFunc:      check.StringIsHostname(),
+			val:            "a..com",
+			errExpected:    true,
+			errMustContain: []string{`the part "" is invalid`},
+		},
+		{
+			name:           "IsHostname: long label",
+			checkFunc:      check.StringIsHostname(),
+			val:            strings.Repeat("a", 64) + ".com",
+			errExpected:    true,
+			errMustContain: []string{"is longer than 63 characters"},
+		},
+		{
+			name:           "IsHostname: too long",
+			checkFunc:      check.StringIsHostname(),
+			val:            strings.Repeat("abc.", 64) + "com",
+			errExpected:    true,
+			errMustContain: []string{"it is longer than 253 characters"},
+		},
+		{
+			name:      "IsSemver: 1.2.3",
+			checkFunc: check.StringIsSemver(),
+			val:       "1.2.3",
+		},
+		{
+			name:      "IsSemver: pre-release and build",
+			checkFunc: check.StringIsSemver(),
+			val:       "1.0.0-rc.1+build.5",
+		},
+		{
+			name:           "IsSemver: leading v",
+			checkFunc:      check.StringIsSemver(),
+			val:            "v1.2.3",
+			errExpected:    true,
+			errMustContain: []string{"must be a valid semantic version"},
+		},
+		{
+			name:           "IsSemver: leading zero",
+			checkFunc:      check.StringIsSemver(),
+			val:            "1.02.3",
+			errExpected:    true,
+			errMustContain: []string{"must be a valid semantic version"},
+		},
+		{
+			name:      "IsUUID: ok",
+			checkFunc: check.StringIsUUID(),
+			val:       "123e4567-E89B-12d3-a456-426614174000",
+		},
+		{
+			name:           "IsUUID: bad group",
+			checkFunc:      check.StringIsUUID(),
+			val:            "123e4567-e89b-12d3-a456-42661417400",
+			errExpected:    true,
+			errMustContain: []string{"must be a valid UUID"},
+		},
+		{
+			name:      "IsJSON: ok",
+			checkFunc: check.StringIsJSON(),
+			val:       `{"a": [1, 2, null]}`,
+		},
+		{
+			name:           "IsJSON: bad",
+			checkFunc:      check.StringIsJSON(),
+			val:            `{"a": }`,
+			errExpected:    true,
+			errMustContain: []string{"must be valid JSON", "invalid character"},
+		},
+		{
+			name:      "IsGoIdentifier: ok",
+			checkFunc: check.StringIsGoIdentifier(),
+			val:       "_x9",
+		},
+		{
+			name:           "IsGoIdentifier: keyword",
+			checkFunc:      check.StringIsGoIdentifier(),
+			val:            "func",
+			errExpected:    true,
+			errMustContain: []string{"it is a keyword"},
+		},
+		{
+			name:           "IsGoIdentifier: leading digit",
+			checkFunc:      check.StringIsGoIdentifier(),
+			val:            "9x",
+			errExpected:    true,
+			errMustContain: []string{"must be a valid Go identifier"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		err := tc.checkFunc(tc.val)
+		checkResult(t, testID, err, tc.errExpected, tc.errMustContain)
+	}
+}
@@ -0,0 +1,101 @@
+package check
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeFmt is the layout used to show times in error messages
+const timeFmt = "2006-01-02 15:04:05 MST"
+
+// Time is the type of a check function which takes a time.Time parameter
+// and returns an error or nil if the check passes
+type Time func(t time.Time) error
+
+// TimeBefore returns a function that will check that the value is
+// before the limit
+func TimeBefore(limit time.Time) Time {
+	return func(t time.Time) error {
+		if t.Before(limit) {
+			return nil
+		}
+		return fmt.Errorf("the time (%s) must be before %s",
+			t.Format(timeFmt), limit.Format(timeFmt))
+	}
+}
+
+// TimeAfter returns a function that will check that the value is
+// after the limit
+func TimeAfter(limit time.Time) Time {
+	return func(t time.Time) error {
+		if t.After(limit) {
+			return nil
+		}
+		return fmt.Errorf("the time (%s) must be after %s",
+			t.Format(timeFmt), limit.Format(timeFmt))
+	}
+}
+
+// TimeBetween returns a function that will check that the value lies
+// between the earliest and latest limits (inclusive)
+func TimeBetween(earliest, latest time.Time) Time {
+	if !earliest.Before(latest) {
+		panic(fmt.Sprintf(
+			"Impossible checks passed to TimeBetween: "+
+				"the earliest time (%s) should be before the latest time (%s)",
+			earliest.Format(timeFmt), latest.Format(timeFmt)))
+	}
+
+	return func(t time.Time) error {
+		if t.Before(earliest) {
+			return fmt.Errorf(
+				"the time (%s) must be between %s and %s - too early",
+				t.Format(timeFmt),
+				earliest.Format(timeFmt), latest.Format(timeFmt))
+		}
+		if t.After(latest) {
+			return fmt.Errorf(
+				"the time (%s) must be between %s and %s - too late",
+				t.Format(timeFmt),
+				earliest.Format(timeFmt), latest.Format(timeFmt))
+		}
+		return nil
+	}
+}
+
+// TimeWithinDuration returns a function that will check that the value is
+// no further than the duration from the time at which the check is
+// made. The duration must not be negative.
+func TimeWithinDuration(d time.Duration) Time {
+	if d < 0 {
+		panic(fmt.Sprintf(
+			"Impossible check passed to TimeWithinDuration: "+
+				"the duration (%s) must not be negative", d))
+	}
+
+	return func(t time.Time) error {
+		diff := time.Since(t)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= d {
+			return nil
+		}
+		return fmt.Errorf("the time (%s) must be within %s of now",
+			t.Format(timeFmt), d)
+	}
+}
+
+// TimeIsWeekday returns a function that will check that the value falls
+// on a weekday (Monday to Friday) in its own location
+func TimeIsWeekday() Time {
+	return func(t time.Time) error {
+		switch t.Weekday() {
+		case time.Saturday, time.Sunday:
+			return fmt.Errorf("the time (%s) must be on a weekday"+
+				" but it is a %s",
+				t.Format(timeFmt), t.Weekday())
+		}
+		return nil
+	}
+}
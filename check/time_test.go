@@ -0,0 +1,197 @@
+package check_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	t1 := time.Date(2019, time.March, 14, 12, 0, 0, 0, time.UTC) // Thursday
+	t2 := t1.Add(time.Hour)
+	t3 := t2.Add(time.Hour)
+	saturday := time.Date(2019, time.March, 16, 12, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	testCases := []struct {
+		name           string
+		checkFunc      check.Time
+		t              time.Time
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			name:      "Before: t1 < t2",
+			checkFunc: check.TimeBefore(t2),
+			t:         t1,
+		},
+		{
+			name:           "Before: t2 !< t2",
+			checkFunc:      check.TimeBefore(t2),
+			t:              t2,
+			errExpected:    true,
+			errMustContain: []string{"must be before"},
+		},
+		{
+			name:      "After: t3 > t2",
+			checkFunc: check.TimeAfter(t2),
+			t:         t3,
+		},
+		{
+			name:           "After: t1 !> t2",
+			checkFunc:      check.TimeAfter(t2),
+			t:              t1,
+			errExpected:    true,
+			errMustContain: []string{"must be after"},
+		},
+		{
+			name:      "Between: t1 <= t2 <= t3",
+			checkFunc: check.TimeBetween(t1, t3),
+			t:         t2,
+		},
+		{
+			name:      "Between: t1 <= t1 <= t3",
+			checkFunc: check.TimeBetween(t1, t3),
+			t:         t1,
+		},
+		{
+			name:      "Between: t1 <= t3 <= t3",
+			checkFunc: check.TimeBetween(t1, t3),
+			t:         t3,
+		},
+		{
+			name:        "Between: t2 !<= t1 <= t3",
+			checkFunc:   check.TimeBetween(t2, t3),
+			t:           t1,
+			errExpected: true,
+			errMustContain: []string{
+				"the time",
+				"must be between",
+				" - too early",
+			},
+		},
+		{
+			name:        "Between: t1 <= t3 !<= t2",
+			checkFunc:   check.TimeBetween(t1, t2),
+			t:           t3,
+			errExpected: true,
+			errMustContain: []string{
+				"the time",
+				"must be between",
+				" - too late",
+			},
+		},
+		{
+			name:      "WithinDuration: an hour ago",
+			checkFunc: check.TimeWithinDuration(2 * time.Hour),
+			t:         now.Add(-time.Hour),
+		},
+		{
+			name:      "WithinDuration: in an hour",
+			checkFunc: check.TimeWithinDuration(2 * time.Hour),
+			t:         now.Add(time.Hour),
+		},
+		{
+			name:           "WithinDuration: three hours ago",
+			checkFunc:      check.TimeWithinDuration(2 * time.Hour),
+			t:              now.Add(-3 * time.Hour),
+			errExpected:    true,
+			errMustContain: []string{"must be within 2h0m0s of now"},
+		},
+		{
+			name:      "IsWeekday: Thursday",
+			checkFunc: check.TimeIsWeekday(),
+			t:         t1,
+		},
+		{
+			name:           "IsWeekday: Saturday",
+			checkFunc:      check.TimeIsWeekday(),
+			t:              saturday,
+			errExpected:    true,
+			errMustContain: []string{"must be on a weekday", "Saturday"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		err := tc.checkFunc(tc.t)
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: there was an unexpected err: %s\n", err)
+			} else {
+				testhelper.ShouldContain(t, testID, "error", err.Error(),
+					tc.errMustContain)
+			}
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned\n")
+		}
+	}
+}
+
+func panicSafeTestTimeCheck(t *testing.T, f func()) (panicked bool, panicVal interface{}) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			panicVal = r
+		}
+	}()
+	f()
+	return panicked, panicVal
+}
+
+func TestTimePanic(t *testing.T) {
+	t1 := time.Date(2019, time.March, 14, 12, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	testCases := []struct {
+		name             string
+		f                func()
+		panicExpected    bool
+		panicMustContain []string
+	}{
+		{
+			name: "Between: t1, t2",
+			f:    func() { check.TimeBetween(t1, t2) },
+		},
+		{
+			name:          "Between: t2, t1",
+			f:             func() { check.TimeBetween(t2, t1) },
+			panicExpected: true,
+			panicMustContain: []string{
+				"Impossible checks passed to TimeBetween: ",
+				"the earliest time",
+				"should be before the latest time",
+			},
+		},
+		{
+			name:          "Between: t1, t1",
+			f:             func() { check.TimeBetween(t1, t1) },
+			panicExpected: true,
+			panicMustContain: []string{
+				"Impossible checks passed to TimeBetween: ",
+			},
+		},
+		{
+			name:          "WithinDuration: negative",
+			f:             func() { check.TimeWithinDuration(-time.Second) },
+			panicExpected: true,
+			panicMustContain: []string{
+				"Impossible check passed to TimeWithinDuration: ",
+				"must not be negative",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		testName := fmt.Sprintf("%d: %s", i, tc.name)
+		panicked, panicVal := panicSafeTestTimeCheck(t, tc.f)
+		testhelper.PanicCheckString(t, testName,
+			panicked, tc.panicExpected,
+			panicVal, tc.panicMustContain)
+	}
+}
@@ -0,0 +1,191 @@
+package fileparser
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// fileContent records the result of reading and tokenizing a file. The
+// done channel is closed once the file has been read and until then none of
+// the other fields should be used. If err is not nil the file could not be
+// opened or read.
+type fileContent struct {
+	done chan struct{}
+
+	lines     []logicalLine
+	linesRead int
+	bytesRead int64
+	scanErr   error
+	err       error
+}
+
+// fileCache records the contents of the files read during a call to one of
+// the Parse methods so that each file is read only once however many times
+// it is included. If the FP has been set to read files concurrently then
+// the files named in include directives are read in advance. The files to
+// be read are added to a queue from which they are taken by a pool of
+// workers; there are never more workers than the concurrency set for the
+// FP. A worker finishes when the queue is empty and a new one is started
+// when a file is queued and there are fewer than the maximum number of
+// workers running.
+type fileCache struct {
+	fp *FP
+
+	mtx        sync.Mutex
+	files      map[string]*fileContent
+	queue      []string
+	workers    int
+	maxWorkers int // zero if the files are read sequentially
+
+	wg sync.WaitGroup
+}
+
+// newFileCache returns a fileCache for the FP
+func newFileCache(fp *FP) *fileCache {
+	fc := &fileCache{
+		fp:    fp,
+		files: make(map[string]*fileContent),
+	}
+	if fp.concurrency > 1 {
+		fc.maxWorkers = fp.concurrency
+	}
+	return fc
+}
+
+// get returns the contents of the named file, waiting for it to be read if
+// necessary
+func (fc *fileCache) get(name string) *fileContent {
+	c := fc.fetch(name)
+	<-c.done
+	return c
+}
+
+// fetch returns the cache entry for the named file, starting to read the
+// file if this has not already been done. When reading sequentially the
+// file is read before returning, otherwise it is added to the queue of
+// files to be read by the workers, starting a new worker if there are
+// fewer than the maximum number running.
+func (fc *fileCache) fetch(name string) *fileContent {
+	fc.mtx.Lock()
+	c, ok := fc.files[name]
+	if ok {
+		fc.mtx.Unlock()
+		return c
+	}
+	c = &fileContent{done: make(chan struct{})}
+	fc.files[name] = c
+
+	if fc.maxWorkers == 0 {
+		fc.mtx.Unlock()
+		fc.read(name, c)
+		return c
+	}
+
+	fc.queue = append(fc.queue, name)
+	if fc.workers < fc.maxWorkers {
+		fc.workers++
+		fc.wg.Add(1)
+		go fc.work()
+	}
+	fc.mtx.Unlock()
+	return c
+}
+
+// work reads the files in the queue, and fetches the files they include,
+// until the queue is empty
+func (fc *fileCache) work() {
+	defer fc.wg.Done()
+
+	for {
+		fc.mtx.Lock()
+		if len(fc.queue) == 0 {
+			fc.workers--
+			fc.mtx.Unlock()
+			return
+		}
+		name := fc.queue[0]
+		fc.queue = fc.queue[1:]
+		c := fc.files[name]
+		fc.mtx.Unlock()
+
+		fc.read(name, c)
+		fc.prefetchIncludes(name, c)
+	}
+}
+
+// read reads and tokenizes the named file, recording the results in the
+// fileContent and marking it as done
+func (fc *fileCache) read(name string, c *fileContent) {
+	defer close(c.done)
+
+	f, err := fc.fp.src.open(name)
+	if err != nil {
+		c.err = err
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.err = err
+		return
+	}
+	c.bytesRead = int64(len(data))
+	fc.fp.tokenize(bytes.NewReader(data), name, c)
+}
+
+// prefetch starts reading the named files if the files are being read
+// concurrently. Otherwise it does nothing and the files will be read when
+// they are needed.
+func (fc *fileCache) prefetch(names ...string) {
+	if fc.maxWorkers == 0 {
+		return
+	}
+	for _, n := range names {
+		if fixedName, err := fc.fp.src.fixName(n); err == nil {
+			fc.fetch(fixedName)
+		}
+	}
+}
+
+// prefetchIncludes starts reading the files named in the include directives
+// in the file contents. Only includes of single files are read in advance
+// and include directives in the inactive part of a conditional section are
+// ignored. Any errors are also ignored; they will be reported when the file
+// is parsed.
+func (fc *fileCache) prefetchIncludes(name string, c *fileContent) {
+	if fc.maxWorkers == 0 {
+		return
+	}
+	conds := make(condStack, 0)
+	for _, ll := range c.lines {
+		if ll.errMsg != "" {
+			continue
+		}
+		if isCond, _ := fc.fp.condDirective(ll.text, &ll.loc, &conds); isCond ||
+			!conds.active() {
+			continue
+		}
+		inclName, kind := fc.fp.inclDirective(ll.text)
+		if kind != inclFile && kind != inclOptional {
+			continue
+		}
+		inclName, err := fc.fp.unquote(inclName)
+		if err != nil || inclName == "" {
+			continue
+		}
+		inclName = fc.fp.src.inclName(inclName, name)
+		if isGlobPattern(inclName) {
+			continue
+		}
+		if fixedName, err := fc.fp.src.fixName(inclName); err == nil {
+			fc.fetch(fixedName)
+		}
+	}
+}
+
+// wait waits for any files being read to be finished
+func (fc *fileCache) wait() {
+	fc.wg.Wait()
+}
@@ -0,0 +1,186 @@
+package fileparser
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/location"
+	"os"
+	"path"
+	"strings"
+)
+
+// CondProg is the name of the condition test which compares the value with
+// the program name
+//
+// CondHost is the name of the condition test which matches the value, as a
+// pattern (see path.Match), against the host name
+//
+// CondEnvPrefix is the prefix of the condition test which compares the value
+// with an environment variable. The rest of the test name after the prefix
+// is the name of the environment variable
+const (
+	CondProg      = "prog"
+	CondHost      = "host"
+	CondEnvPrefix = "env:"
+)
+
+// condBlock records the state of a conditional section of a file
+type condBlock struct {
+	loc          location.L
+	parentActive bool
+	condVal      bool
+	inElse       bool
+}
+
+// active returns true if the lines in the current branch of the block
+// should be used
+func (cb condBlock) active() bool {
+	return cb.parentActive && cb.condVal != cb.inElse
+}
+
+// condStack records the nested conditional sections in a file
+type condStack []condBlock
+
+// active returns true if the current line should be used. This is the case
+// if there are no enclosing conditional sections or if the innermost one is
+// active
+func (cs condStack) active() bool {
+	if len(cs) == 0 {
+		return true
+	}
+	return cs[len(cs)-1].active()
+}
+
+// hasKeyWord returns the text following the keyword with any surrounding
+// whitespace removed and a bool indicating whether the line starts with the
+// keyword. A line is only taken to start with the keyword if the keyword is
+// non-empty and it is followed either by the end of the line or by white
+// space
+func hasKeyWord(line, kw string) (string, bool) {
+	if kw == "" || !strings.HasPrefix(line, kw) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(line, kw)
+	if rest == "" {
+		return "", true
+	}
+	if rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// condDirective checks whether the line is one of the conditional
+// directives and if so it updates the condStack accordingly. It returns true
+// if the line was a conditional directive and false otherwise. Any error
+// found with the directive is also returned. Note that the directives must
+// be processed even in the inactive part of a conditional section so that
+// nested sections are matched correctly.
+func (fp *FP) condDirective(line string, l *location.L, cs *condStack) (bool, error) {
+	loc := *l
+	loc.SetContent(line)
+
+	if cond, ok := hasKeyWord(line, fp.ifKeyWord); ok {
+		cb := condBlock{
+			loc:          loc,
+			parentActive: cs.active(),
+		}
+		*cs = append(*cs, cb)
+		if !cb.parentActive {
+			return true, nil
+		}
+
+		// if the condition is bad the whole section is skipped
+		top := &(*cs)[len(*cs)-1]
+		if cond == "" {
+			top.parentActive = false
+			return true, loc.Errorf("Missing %s condition", fp.ifKeyWord)
+		}
+		val, err := fp.evalCond(cond)
+		if err != nil {
+			top.parentActive = false
+			return true, loc.Errorf("Bad %s condition: %s",
+				fp.ifKeyWord, err.Error())
+		}
+		top.condVal = val
+		return true, nil
+	}
+
+	if extra, ok := hasKeyWord(line, fp.elseKeyWord); ok {
+		if len(*cs) == 0 {
+			return true, loc.Errorf("%s without a matching %s",
+				fp.elseKeyWord, fp.ifKeyWord)
+		}
+		cb := &(*cs)[len(*cs)-1]
+		if cb.inElse {
+			return true, loc.Errorf("Repeated %s for the %s at %s",
+				fp.elseKeyWord, fp.ifKeyWord, cb.loc)
+		}
+		cb.inElse = true
+		if extra != "" {
+			return true, loc.Errorf("Unexpected text after %s: '%s'",
+				fp.elseKeyWord, extra)
+		}
+		return true, nil
+	}
+
+	if extra, ok := hasKeyWord(line, fp.endifKeyWord); ok {
+		if len(*cs) == 0 {
+			return true, loc.Errorf("%s without a matching %s",
+				fp.endifKeyWord, fp.ifKeyWord)
+		}
+		*cs = (*cs)[:len(*cs)-1]
+		if extra != "" {
+			return true, loc.Errorf("Unexpected text after %s: '%s'",
+				fp.endifKeyWord, extra)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// evalCond evaluates the condition given in a conditional directive. The
+// condition should be of the form test=value where the test is one of the
+// condition tests given above
+func (fp *FP) evalCond(cond string) (bool, error) {
+	parts := strings.SplitN(cond, "=", 2)
+	if len(parts) != 2 {
+		return false, fmt.Errorf("'%s' should be of the form test=value", cond)
+	}
+	test := strings.TrimSpace(parts[0])
+	val, err := fp.unquote(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case test == CondProg:
+		return val == fp.progName, nil
+	case test == CondHost:
+		host, err := os.Hostname()
+		if err != nil {
+			return false, err
+		}
+		return path.Match(val, host)
+	case strings.HasPrefix(test, CondEnvPrefix):
+		envName := strings.TrimPrefix(test, CondEnvPrefix)
+		if envName == "" {
+			return false,
+				fmt.Errorf("the environment variable name is missing")
+		}
+		return os.Getenv(envName) == val, nil
+	}
+	return false, fmt.Errorf("unknown test: '%s'", test)
+}
+
+// unclosedCondErrs returns an error for each conditional section that has
+// not been closed by the end of the file
+func (fp FP) unclosedCondErrs(cs condStack) []error {
+	errors := make([]error, 0, len(cs))
+	for _, cb := range cs {
+		errors = append(errors,
+			cb.loc.Errorf("%s without a matching %s",
+				fp.ifKeyWord, fp.endifKeyWord))
+	}
+	return errors
+}
@@ -0,0 +1,201 @@
+package fileparser
+
+import (
+	"errors"
+	"github.com/nickwells/golem/filecheck"
+	"github.com/nickwells/golem/location"
+	"io/fs"
+	"strings"
+)
+
+// inclKind records the type of an include directive
+type inclKind int
+
+// inclNone indicates that the line is not an include directive
+//
+// inclFile indicates a plain include directive. The name may be a glob
+// pattern in which case all the matching files are included
+//
+// inclOptional indicates an include directive where the file need not exist
+//
+// inclDir indicates an include directive naming a directory, all the files
+// in the directory are included
+const (
+	inclNone inclKind = iota
+	inclFile
+	inclOptional
+	inclDir
+)
+
+// DefaultInclDirChecks returns the checks applied by default to the
+// entries of an included directory or to the files matching an include
+// pattern. Directories and hidden files (those with names starting with a
+// '.') are not included.
+func DefaultInclDirChecks() []filecheck.InfoChecker {
+	return []filecheck.InfoChecker{
+		filecheck.ICNot(filecheck.ICIsDir),
+		filecheck.ICNot(filecheck.ICNameHasPrefix(".")),
+	}
+}
+
+// SetInclOptKeyWord changes the optional include keyword from the default
+// value. If the file named after the keyword does not exist it is silently
+// skipped. Setting the keyword to the empty string will turn off optional
+// includes
+func (fp *FP) SetInclOptKeyWord(incl string) {
+	fp.inclOptKeyWord = incl
+}
+
+// SetInclDirKeyWord changes the directory include keyword from the default
+// value. The files in the directory named after the keyword are included in
+// order of their names. Setting the keyword to the empty string will turn
+// off directory includes
+func (fp *FP) SetInclDirKeyWord(incl string) {
+	fp.inclDirKeyWord = incl
+}
+
+// SetInclDirChecks changes the checks applied to the entries of an included
+// directory and to the files matching an include pattern from the default
+// value (see DefaultInclDirChecks). The checks are passed the FileInfo of the
+// entry, following any symbolic links. Only those entries for which all the
+// checks return true are included. Calling this with no checks will mean
+// that every entry is included.
+func (fp *FP) SetInclDirChecks(checks ...filecheck.InfoChecker) {
+	fp.inclDirChecks = checks
+}
+
+// inclDirective returns the name following the include keyword and the kind
+// of include directive. If the line is not an include directive the kind is
+// inclNone. The more specific keywords are checked first so that they are
+// not mistaken for a plain include directive with a strange file name.
+func (fp *FP) inclDirective(line string) (string, inclKind) {
+	if name, ok := hasKeyWord(line, fp.inclDirKeyWord); ok {
+		return name, inclDir
+	}
+	if name, ok := hasKeyWord(line, fp.inclOptKeyWord); ok {
+		return name, inclOptional
+	}
+	if name, ok := fp.isAnInclLine(line); ok {
+		return name, inclFile
+	}
+	return "", inclNone
+}
+
+// isGlobPattern returns true if the name contains any of the characters
+// which are special in a pattern (see filepath.Match)
+func isGlobPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// parseIncl parses the files given by the include directive. The location
+// is that of the include directive and is used to report any errors
+func (fp *FP) parseIncl(name string, kind inclKind, currentFileName string,
+	loc location.L, inclChain location.LocChain) []error {
+	name = fp.src.inclName(name, currentFileName)
+
+	if kind == inclDir {
+		return fp.parseInclDir(name, loc, inclChain)
+	}
+	if isGlobPattern(name) {
+		return fp.parseInclGlob(name, kind, loc, inclChain)
+	}
+	if kind == inclOptional {
+		if fixedName, err := fp.src.fixName(name); err == nil {
+			_, err = fp.src.stat(fixedName)
+			if errors.Is(err, fs.ErrNotExist) {
+				return []error{}
+			}
+		}
+	}
+	return fp.parseFile(name, inclChain)
+}
+
+// parseInclDir parses each of the entries in the directory which pass the
+// include directory checks in order of their names
+func (fp *FP) parseInclDir(dirName string,
+	loc location.L, inclChain location.LocChain) []error {
+	var errs = make([]error, 0)
+
+	fixedDirName, err := fp.src.fixName(dirName)
+	if err != nil {
+		return append(errs,
+			loc.Errorf("Couldn't expand: '%s' : %s", dirName, err.Error()))
+	}
+	names, err := fp.src.readDir(fixedDirName)
+	if err != nil {
+		return append(errs,
+			loc.Errorf("Couldn't read the include directory: %s", err.Error()))
+	}
+
+	entryNames := make([]string, 0, len(names))
+	for _, n := range names {
+		entryName := fp.src.join(fixedDirName, n)
+		fi, err := fp.src.stat(entryName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if fp.inclDirCheck(fi) {
+			entryNames = append(entryNames, entryName)
+		}
+	}
+
+	fp.cache.prefetch(entryNames...)
+	for _, n := range entryNames {
+		errs = append(errs, fp.parseFile(n, inclChain)...)
+	}
+	return errs
+}
+
+// parseInclGlob parses each of the files matching the pattern which pass
+// the include directory checks in order of their names. It is an error if
+// no files match the pattern unless this is an optional include
+func (fp *FP) parseInclGlob(pattern string, kind inclKind,
+	loc location.L, inclChain location.LocChain) []error {
+	var errs = make([]error, 0)
+
+	fixedPattern, err := fp.src.fixName(pattern)
+	if err != nil {
+		return append(errs,
+			loc.Errorf("Couldn't expand: '%s' : %s", pattern, err.Error()))
+	}
+	names, err := fp.src.glob(fixedPattern)
+	if err != nil {
+		return append(errs,
+			loc.Errorf("Bad include pattern: '%s' : %s",
+				pattern, err.Error()))
+	}
+
+	matchNames := make([]string, 0, len(names))
+	for _, n := range names {
+		fi, err := fp.src.stat(n)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if fp.inclDirCheck(fi) {
+			matchNames = append(matchNames, n)
+		}
+	}
+
+	fp.cache.prefetch(matchNames...)
+	for _, n := range matchNames {
+		errs = append(errs, fp.parseFile(n, inclChain)...)
+	}
+
+	if len(matchNames) == 0 && kind != inclOptional {
+		errs = append(errs,
+			loc.Errorf("No files match the include pattern: '%s'", pattern))
+	}
+	return errs
+}
+
+// inclDirCheck returns true if all the include directory checks pass
+func (fp FP) inclDirCheck(fi fs.FileInfo) bool {
+	for _, ic := range fp.inclDirChecks {
+		if !ic(fi) {
+			return false
+		}
+	}
+	return true
+}
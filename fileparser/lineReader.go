@@ -0,0 +1,143 @@
+package fileparser
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nickwells/golem/location"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// contChar is the character which, at the end of a line, shows that the
+// line is continued on the next line
+const contChar = `\`
+
+// logicalLine records a line assembled from one or more lines of a file. If
+// the errMsg is not empty then there was a problem assembling the line, the
+// text should not be used and the error should be reported at the location
+type logicalLine struct {
+	text   string
+	loc    location.L
+	errMsg string
+}
+
+// lineReader reads logical lines from a file, joining continued lines and
+// heredoc blocks together. It only reads the configuration of the FP and
+// so several lineReaders can be used at the same time
+type lineReader struct {
+	fp        *FP
+	scanner   *bufio.Scanner
+	loc       *location.L
+	linesRead int
+}
+
+// readLine reads the next line from the file, updating the location and
+// the count of lines read. It returns false if there are no more lines
+func (lr *lineReader) readLine() (string, bool) {
+	if !lr.scanner.Scan() {
+		return "", false
+	}
+	lr.linesRead++
+	lr.loc.Incr()
+	return lr.scanner.Text(), true
+}
+
+// next returns the next logical line and true or false if there are no more
+// lines. The text of the logical line has any comments and surrounding white
+// space removed. The location of the logical line is that of the first line
+// read and it is used to report any errors.
+//
+// If line continuation is on, a line ending with a backslash is joined to
+// the next line after removing the backslash and any leading white space
+// from the next line. If the backslash is inside a quoted string the next
+// line is joined unchanged.
+//
+// If there is a heredoc introducer, a line ending with it followed by a
+// tag is joined to all the following lines up to a line consisting only of
+// the tag. These lines are taken unchanged; they are not stripped of
+// comments or white space. The heredoc introducer and tag are replaced with
+// the lines, joined by newlines, as a double-quoted string.
+func (lr *lineReader) next() (logicalLine, bool) {
+	var ll logicalLine
+
+	line, ok := lr.readLine()
+	if !ok {
+		return ll, false
+	}
+	ll.loc = *lr.loc
+	errLoc := ll.loc
+	errLoc.SetContent(line)
+	badLine := func(msg string) (logicalLine, bool) {
+		return logicalLine{loc: errLoc, errMsg: msg}, true
+	}
+
+	var sb strings.Builder
+	text, quote := lr.fp.splitComment(line, 0)
+	for {
+		if quote == 0 {
+			text = strings.TrimRight(text, " \t")
+		}
+		if !lr.fp.continuation || !strings.HasSuffix(text, contChar) {
+			sb.WriteString(text)
+			break
+		}
+		sb.WriteString(strings.TrimSuffix(text, contChar))
+
+		nextLine, ok := lr.readLine()
+		if !ok {
+			return badLine("The last line is continued but the file has ended")
+		}
+		wasInQuote := quote != 0
+		text, quote = lr.fp.splitComment(nextLine, quote)
+		if !wasInQuote {
+			text = strings.TrimLeft(text, " \t")
+		}
+	}
+	if quote != 0 {
+		return badLine("Unterminated quoted string")
+	}
+	ll.text = strings.TrimSpace(sb.String())
+
+	if tag, prefix, ok := lr.fp.heredocTag(ll.text); ok {
+		body := make([]string, 0)
+		for {
+			bodyLine, ok := lr.readLine()
+			if !ok {
+				return badLine(
+					fmt.Sprintf("The heredoc terminator (%s) is missing", tag))
+			}
+			if strings.TrimSpace(bodyLine) == tag {
+				break
+			}
+			body = append(body, bodyLine)
+		}
+		ll.text = prefix + strconv.Quote(strings.Join(body, "\n"))
+	}
+
+	return ll, true
+}
+
+// tokenize reads all the logical lines from the Reader and records them in
+// the fileContent. The name is that of the file being read. Blank lines are
+// discarded.
+func (fp *FP) tokenize(r io.Reader, name string, c *fileContent) {
+	lr := lineReader{
+		fp:      fp,
+		scanner: bufio.NewScanner(r),
+		loc:     location.New(name),
+	}
+
+	for {
+		ll, ok := lr.next()
+		if !ok {
+			break
+		}
+		if ll.text == "" && ll.errMsg == "" {
+			continue // ignore blank lines
+		}
+		c.lines = append(c.lines, ll)
+	}
+	c.linesRead = lr.linesRead
+	c.scanErr = lr.scanner.Err()
+}
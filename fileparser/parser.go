@@ -1,22 +1,54 @@
 package fileparser
 
 import (
-	"bufio"
 	"fmt"
+	"github.com/nickwells/golem/filecheck"
 	"github.com/nickwells/golem/location"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // DefaultInclKeyword is the value which introduces the name of a file to be
-// read and substituted into the current file
+// read and substituted into the current file. The name may be a pattern (see
+// filepath.Match) in which case all the matching files are read in order of
+// their names
+//
+// DefaultInclOptKeyWord is the value which introduces the name of a file to
+// be read and substituted into the current file if it exists
+//
+// DefaultInclDirKeyWord is the value which introduces the name of a
+// directory whose files are to be read, in order of their names, and
+// substituted into the current file
 //
 // DefaultCommentIntro is the default comment introducer - everything from
 // this to the end of the line is ignored
+//
+// DefaultHeredocIntro is the suggested heredoc introducer (see
+// SetHeredocIntro). When it is followed by a tag at the end of a line, the
+// following lines up to a line containing just the tag are taken as a
+// single value
+//
+// DefaultIfKeyWord is the value which introduces a conditional section of
+// the file. It is followed by a condition and the lines up to the matching
+// else or endif keyword are only used if the condition is true
+//
+// DefaultElseKeyWord is the value which starts the part of a conditional
+// section that is only used if the condition is false
+//
+// DefaultEndifKeyWord is the value which ends a conditional section
 const (
-	DefaultInclKeyWord  string = "#include"
-	DefaultCommentIntro        = "//"
+	DefaultInclKeyWord    string = "#include"
+	DefaultInclOptKeyWord        = "#include-optional"
+	DefaultInclDirKeyWord        = "#include-dir"
+	DefaultCommentIntro          = "//"
+	DefaultHeredocIntro          = "<<"
+	DefaultIfKeyWord             = "#if"
+	DefaultElseKeyWord           = "#else"
+	DefaultEndifKeyWord          = "#endif"
 )
 
 // FP records the configuration of the file parser
@@ -24,51 +56,151 @@ type FP struct {
 	fileType   string
 	lineParser LineParser
 
-	cmtIntro    string
-	inclKeyWord string
+	cmtIntro       string
+	quoting        bool
+	continuation   bool
+	heredocIntro   string
+	inclKeyWord    string
+	inclOptKeyWord string
+	inclDirKeyWord string
+	inclDirChecks  []filecheck.InfoChecker
+
+	ifKeyWord    string
+	elseKeyWord  string
+	endifKeyWord string
+	progName     string
 
+	concurrency int
+
+	src   source
+	cache *fileCache
 	stats Stats
 }
 
 // Stats returns the latest statistics for the FP
 func (fp FP) Stats() Stats { return fp.stats }
 
-// New initialises a file parser with the default comment characters and
-// include keyword and the passed LineParser. The desc is used in error
-// messages to identify the type of file being parsed
+// New initialises a file parser with the default comment characters,
+// include and conditional keywords, include directory checks and the passed
+// LineParser. The desc is
+// used in error messages to identify the type of file being parsed. The
+// program name used when evaluating conditions is set to the base name of
+// the running program.
 func New(desc string, lp LineParser) *FP {
 	return &FP{
-		fileType:    desc,
-		lineParser:  lp,
-		cmtIntro:    DefaultCommentIntro,
-		inclKeyWord: DefaultInclKeyWord}
+		fileType:       desc,
+		lineParser:     lp,
+		cmtIntro:       DefaultCommentIntro,
+		inclKeyWord:    DefaultInclKeyWord,
+		inclOptKeyWord: DefaultInclOptKeyWord,
+		inclDirKeyWord: DefaultInclDirKeyWord,
+		inclDirChecks:  DefaultInclDirChecks(),
+		ifKeyWord:      DefaultIfKeyWord,
+		elseKeyWord:    DefaultElseKeyWord,
+		endifKeyWord:   DefaultEndifKeyWord,
+		progName:       filepath.Base(os.Args[0])}
 }
 
 // SetCommentIntro changes the comment introducer from the default value. A
 // comment is taken to run from the start of the comment introducer to the
 // end of the line. Setting the comment introducer to the empty string will
 // mean that comments are ignored, though whitespace will still be trimmed.
+// See SetQuoting for a change to where a comment can start.
 func (fp *FP) SetCommentIntro(cmtIntro string) {
 	fp.cmtIntro = cmtIntro
 }
 
+// SetQuoting turns quoted strings on or off; they are off by default. When
+// they are on a comment introducer inside a quoted string does not start a
+// comment and nor does one which is not at the start of the line or
+// preceded by white space. Note that this means that a comment introducer
+// which immediately follows other text, as in "key=val//comment", is kept
+// as part of the line. Quotes are also removed from the names given in
+// include directives and from the values in conditions (see Unquote) and a
+// quoted string which is not closed by the end of the line is an error.
+func (fp *FP) SetQuoting(on bool) {
+	fp.quoting = on
+}
+
+// SetContinuation turns line continuation on or off; it is off by
+// default. When it is on a line ending with a backslash is continued on the
+// next line.
+func (fp *FP) SetContinuation(on bool) {
+	fp.continuation = on
+}
+
+// SetHeredocIntro sets the heredoc introducer. By default it is the empty
+// string which turns off the heredoc mechanism; DefaultHeredocIntro is the
+// suggested value.
+func (fp *FP) SetHeredocIntro(intro string) {
+	fp.heredocIntro = intro
+}
+
 // SetInclKeyWord changes the include keyword from the default value. Setting
-// the include keyword to the empty string will turn off the include file
-// mechanism
+// the include keyword to the empty string will turn off the plain include
+// file mechanism; the optional and directory include keywords are not
+// affected
 func (fp *FP) SetInclKeyWord(incl string) {
 	fp.inclKeyWord = incl
 }
 
-// stripComments will remove any comments. That is the text from the start of
-// a comment as given by the comment intro to the end of the line. It also
-// removes any white space from the beginning or end of the line
-func (fp FP) stripComment(s string) string {
-	if fp.cmtIntro == "" {
-		return strings.TrimSpace(s)
-	}
+// SetIfKeyWord changes the keyword introducing a conditional section from
+// the default value. Setting the keyword to the empty string will turn off
+// the conditional section mechanism
+//
+// The keyword should be followed by a condition of the form test=value where
+// test is one of:
+//
+//	prog     - the value is compared with the program name
+//	host     - the value is a pattern matched against the host name
+//	env:NAME - the value is compared with the environment variable NAME
+func (fp *FP) SetIfKeyWord(kw string) {
+	fp.ifKeyWord = kw
+}
+
+// SetElseKeyWord changes the keyword which starts the alternative part of a
+// conditional section from the default value.
+func (fp *FP) SetElseKeyWord(kw string) {
+	fp.elseKeyWord = kw
+}
+
+// SetEndifKeyWord changes the keyword which ends a conditional section from
+// the default value.
+func (fp *FP) SetEndifKeyWord(kw string) {
+	fp.endifKeyWord = kw
+}
+
+// SetConcurrency sets the maximum number of files which will be read at the
+// same time. If this is greater than one then included files are read and
+// split into lines in advance by up to this many goroutines. Files included
+// in the inactive part of a conditional section are not read in advance.
+// The lines are still passed to the LineParser in the same order as when
+// the files are read one at a time (the default) and so the LineParser need
+// not be safe for concurrent use. Note that when reading files concurrently
+// any fs.FS passed to ParseFS or ParseReader must be safe for concurrent
+// use.
+//
+// Whether or not files are read concurrently each file is only read once
+// during a call to one of the Parse methods however many times it is
+// included.
+func (fp *FP) SetConcurrency(n int) {
+	fp.concurrency = n
+}
+
+// SetProgName changes the program name which is compared with the value in
+// a prog=NAME condition
+func (fp *FP) SetProgName(name string) {
+	fp.progName = name
+}
 
-	parts := strings.SplitN(s, fp.cmtIntro, 2)
-	return strings.TrimSpace(parts[0])
+// stripComment will remove any comments. That is the text from the start of
+// a comment as given by the comment intro to the end of the line. It also
+// removes any white space from the beginning or end of the line. If quoting
+// is on a comment intro inside a quoted string or which does not follow
+// white space is not taken as the start of a comment (see splitComment).
+func (fp *FP) stripComment(s string) string {
+	text, _ := fp.splitComment(s, 0)
+	return strings.TrimSpace(text)
 }
 
 // isAnInclLine returns the include file name and a bool indicating whether
@@ -78,7 +210,7 @@ func (fp FP) stripComment(s string) string {
 // non-empty and the line passed has the keyword as a prefix. If it is an
 // include line then the include file name is everything after the keyword
 // with any surrounding whitespace stripped off.
-func (fp FP) isAnInclLine(line string) (inclFileName string, hasIncl bool) {
+func (fp *FP) isAnInclLine(line string) (inclFileName string, hasIncl bool) {
 	inclFileName = ""
 	hasIncl = fp.inclKeyWord != "" && strings.HasPrefix(line, fp.inclKeyWord)
 
@@ -95,11 +227,68 @@ func (fp FP) isAnInclLine(line string) (inclFileName string, hasIncl bool) {
 // remaining text. It is the responsibility of the LineParser to perform any
 // operations resulting from the parsed lines. Any errors detected will be
 // returned; note that more than one error is possible
+//
+// If they have been turned on (see SetQuoting, SetContinuation and
+// SetHeredocIntro) a value may be given in a quoted string, in which case
+// comment intros within it are ignored, a line ending with a backslash is
+// continued on the next line and a heredoc block can be used to give a
+// value over several lines. The LineParser is passed the logical line
+// formed by joining such lines together and the location of the first
+// line. A LineParser can use Unquote to get the value from a quoted string.
+//
+// Lines in the inactive part of a conditional section are skipped and
+// include directives in them are not followed. Each conditional section
+// must end in the same file that it starts in.
+//
+// The filename and the names given in include directives are operating
+// system pathnames which may start with a '~' (see FixFileName). The files
+// are read through an fs.FS (see os.DirFS) as for ParseFS.
 func (fp *FP) Parse(filename string) []error {
+	return fp.parse(newOSSource(), func(inclChain location.LocChain) []error {
+		return fp.parseFile(filename, inclChain)
+	})
+}
+
+// ParseFS behaves as Parse but reads the named file from the file
+// system. The name and the names given in include directives are
+// slash-separated and the included files are also read from the file
+// system. A relative include file name is taken relative to the directory
+// of the including file and an absolute name is taken relative to the root
+// of the file system.
+func (fp *FP) ParseFS(fsys fs.FS, name string) []error {
+	return fp.parse(fsSource{fsys: fsys}, func(inclChain location.LocChain) []error {
+		return fp.parseFile(name, inclChain)
+	})
+}
+
+// ParseReader behaves as Parse but reads the lines to be parsed from the
+// Reader. The name is used to report the location of errors and as the name
+// of the including file when resolving include directives. Any included
+// files are read from the file system as for ParseFS. If the file system is
+// nil then any attempt to include a file will be reported as an error.
+func (fp *FP) ParseReader(r io.Reader, name string, fsys fs.FS) []error {
+	return fp.parse(fsSource{fsys: fsys}, func(inclChain location.LocChain) []error {
+		return fp.parseReader(r, name, inclChain)
+	})
+}
+
+// parse resets the statistics, sets the source of the files and creates a
+// new file cache before calling the parser function. It waits for any files
+// being read in advance to be finished before returning and records the
+// time taken in the statistics.
+func (fp *FP) parse(src source, parser func(location.LocChain) []error) []error {
+	start := time.Now()
 	fp.stats = Stats{} // reset the stats each time we parse
+	fp.src = src
+	fp.cache = newFileCache(fp)
+
 	var errors = make([]error, 0)
-	inclChain := location.NewChain()
-	return append(errors, fp.parseFile(filename, inclChain)...)
+	errors = append(errors, parser(location.NewChain())...)
+
+	fp.cache.wait()
+	fp.cache = nil
+	fp.stats.duration = time.Since(start)
+	return errors
 }
 
 // fixIncludeFileName returns the include file name with the directory of the
@@ -121,18 +310,16 @@ func (fp *FP) noteStr(inclChain location.LocChain) string {
 	return note
 }
 
-// parseFile
+// parseFile opens the named file, checking for include loops, and parses it
 func (fp *FP) parseFile(filename string, inclChain location.LocChain) []error {
 	var errors = make([]error, 0)
 
-	fixedFileName, err := FixFileName(filename)
+	fixedFileName, err := fp.src.fixName(filename)
 	if err != nil {
 		return append(errors,
 			fmt.Errorf("%s: Couldn't expand: '%s' : %s",
 				fp.noteStr(inclChain), filename, err.Error()))
 	}
-	loc := location.New(fixedFileName)
-	loc.SetNote(fp.noteStr(inclChain))
 
 	loopFound, loopMsg := inclChain.HasLoop(fixedFileName)
 	if loopFound {
@@ -141,37 +328,79 @@ func (fp *FP) parseFile(filename string, inclChain location.LocChain) []error {
 				fixedFileName, loopMsg))
 	}
 
-	fd, err := os.Open(fixedFileName)
-	if err != nil {
-		return append(errors, err)
+	c := fp.cache.get(fixedFileName)
+	if c.err != nil {
+		return append(errors, c.err)
 	}
-	defer fd.Close()
+
+	return append(errors, fp.parseContent(c, fixedFileName, inclChain)...)
+}
+
+// parseReader reads lines from the Reader and parses them. The name is that
+// of the file being read
+func (fp *FP) parseReader(r io.Reader, filename string, inclChain location.LocChain) []error {
+	var errors = make([]error, 0)
+
+	c := &fileContent{}
+	cr := &countingReader{r: r}
+	fp.tokenize(cr, filename, c)
+	c.bytesRead = cr.count
+	fp.cache.prefetchIncludes(filename, c)
+
+	return append(errors, fp.parseContent(c, filename, inclChain)...)
+}
+
+// parseContent parses the lines of the file. The name is that of the file
+// from which the lines were read
+func (fp *FP) parseContent(c *fileContent, filename string, inclChain location.LocChain) []error {
+	var errors = make([]error, 0)
+
+	note := fp.noteStr(inclChain)
 
 	fp.stats.filesVisited++
-	scanner := bufio.NewScanner(fd)
+	fp.stats.linesRead += c.linesRead
+	fp.stats.bytesRead += c.bytesRead
+	conds := make(condStack, 0)
 
-	for scanner.Scan() {
-		fp.stats.linesRead++
-		originalLine := scanner.Text()
-		loc.Incr()
+	for _, ll := range c.lines {
+		loc := &ll.loc
+		loc.SetNote(note)
+		if ll.errMsg != "" {
+			errors = append(errors, loc.Error(ll.errMsg))
+			continue
+		}
+		line := ll.text
 
-		line := fp.stripComment(originalLine)
-		if line == "" {
-			continue // ignore blank lines
+		isCond, err := fp.condDirective(line, loc, &conds)
+		if err != nil {
+			errors = append(errors, err)
+		}
+		if isCond {
+			continue
+		}
+		if !conds.active() {
+			fp.stats.linesSkipped++
+			continue
 		}
 
-		inclFileName, hasIncl := fp.isAnInclLine(line)
-		if hasIncl {
-			if inclFileName == "" {
-				loc.SetContent(originalLine)
-				errors = append(errors, loc.Errorf("Missing include file name"))
+		inclName, kind := fp.inclDirective(line)
+		if kind != inclNone {
+			inclLoc := *loc
+			inclLoc.SetContent(line)
+			if inclName == "" {
+				errors = append(errors,
+					inclLoc.Errorf("Missing include file name"))
+				continue
+			}
+			if inclName, err = fp.unquote(inclName); err != nil {
+				errors = append(errors,
+					inclLoc.Errorf("Bad include file name: %s", err.Error()))
 				continue
 			}
-
-			inclFileName = fixIncludeFileName(inclFileName, filename)
 
 			errors = append(errors,
-				fp.parseFile(inclFileName, append(inclChain, *loc))...)
+				fp.parseIncl(inclName, kind, filename, inclLoc,
+					append(inclChain, *loc))...)
 			continue
 		}
 
@@ -181,9 +410,23 @@ func (fp *FP) parseFile(filename string, inclChain location.LocChain) []error {
 		}
 	}
 
-	if err = scanner.Err(); err != nil {
-		errors = append(errors, err)
+	if c.scanErr != nil {
+		errors = append(errors, c.scanErr)
 	}
+	errors = append(errors, fp.unclosedCondErrs(conds)...)
 
 	return errors
 }
+
+// countingReader is a Reader which counts the bytes read
+type countingReader struct {
+	r     io.Reader
+	count int64
+}
+
+// Read reads from the underlying Reader and counts the bytes read
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.count += int64(n)
+	return n, err
+}
@@ -64,6 +64,9 @@ func TestStripComment(t *testing.T) {
 		{" // test", ""},
 		{"   ", ""},
 		{"abc ", "abc"},
+		{"key=val//comment", "key=val"},
+		{`abc = "x // y" // test`, `abc = "x`},
+		{`abc = 'x`, `abc = 'x`},
 	}
 
 	for _, ct := range testCases1 {
@@ -76,6 +79,29 @@ func TestStripComment(t *testing.T) {
 		}
 	}
 
+	fpQuoting := New("intro", np)
+	fpQuoting.SetQuoting(true)
+	testCasesQuoting := []commentTest{
+		{"abc // test", "abc"},
+		{"url = http://example.com", "url = http://example.com"},
+		{`abc = "x // y" // test`, `abc = "x // y"`},
+		{`abc = 'x // y' // test`, `abc = 'x // y'`},
+		{`abc = "x \" // y" // test`, `abc = "x \" // y"`},
+		{"it's // test", "it's"},
+		{"key=val//comment", "key=val//comment"},
+		{"key=val //comment", "key=val"},
+	}
+
+	for _, ct := range testCasesQuoting {
+		strippedLine := fpQuoting.stripComment(ct.line)
+
+		if strippedLine != ct.expectedLine {
+			t.Error("stripComment(", ct.line, ") with quoting failed\n",
+				"expected the stripped line to be: '", ct.expectedLine, "'\n",
+				"got: '", strippedLine, "'")
+		}
+	}
+
 	fpNull.SetCommentIntro("#")
 	testCases2 := []commentTest{
 		{"abc // test", "abc // test"},
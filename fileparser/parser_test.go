@@ -3,9 +3,18 @@ package fileparser_test
 import (
 	"bufio"
 	"bytes"
+	"fmt"
+	"github.com/nickwells/golem/filecheck"
 	"github.com/nickwells/golem/fileparser"
 	"github.com/nickwells/golem/location"
+	"github.com/nickwells/golem/testhelper"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 )
 
 func TestEchoParser(t *testing.T) {
@@ -87,9 +96,595 @@ func TestStats(t *testing.T) {
 	if lp := s.LinesParsed(); lp != 0 {
 		t.Error("an empty Stats structure should have linesParsed: 0, has: ", lp)
 	}
-	expectedStr := "files:   0 lines read:     0 parsed:     0"
+	if ls := s.LinesSkipped(); ls != 0 {
+		t.Error("an empty Stats structure should have linesSkipped: 0, has: ", ls)
+	}
+	if br := s.BytesRead(); br != 0 {
+		t.Error("an empty Stats structure should have bytesRead: 0, has: ", br)
+	}
+	if d := s.Duration(); d != 0 {
+		t.Error("an empty Stats structure should have duration: 0, has: ", d)
+	}
+	expectedStr := "files:   0 lines read:     0 parsed:     0 skipped:     0" +
+		" bytes:        0 time: 0s"
 	if s := s.String(); s != expectedStr {
 		t.Error("an empty Stats structure should have a String representation of: ",
 			expectedStr, " has: ", s)
 	}
 }
+
+// lineCollector is a LineParser which records the lines it is passed and,
+// optionally, the location index of each line
+type lineCollector struct {
+	lines *[]string
+	idxs  *[]int64
+}
+
+// ParseLine for the lineCollector appends the line to the slice of lines
+func (lc lineCollector) ParseLine(line string, loc *location.L) error {
+	*lc.lines = append(*lc.lines, line)
+	if lc.idxs != nil {
+		*lc.idxs = append(*lc.idxs, loc.Idx())
+	}
+	return nil
+}
+
+func TestParseConditional(t *testing.T) {
+	const envName = "GOLEM_FP_TEST"
+
+	testCases := []struct {
+		testName           string
+		filename           string
+		envVal             string
+		expectedErrCount   int
+		expectedFileCount  int
+		expectedSkipCount  int
+		expectedParsedVals []string
+	}{
+		{
+			testName:           "sections, env not set",
+			filename:           "./testdata/CondSections",
+			expectedSkipCount:  3,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{"a", "b", "f", "g"},
+		},
+		{
+			testName:           "sections, env set",
+			filename:           "./testdata/CondSections",
+			envVal:             "yes",
+			expectedSkipCount:  3,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{"a", "b", "d", "e"},
+		},
+		{
+			testName:           "skipped include",
+			filename:           "./testdata/CondSkippedInclude",
+			expectedSkipCount:  1,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "else and endif with no if",
+			filename:           "./testdata/CondNoIf",
+			expectedErrCount:   2,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{"a", "b"},
+		},
+		{
+			testName:           "unclosed section",
+			filename:           "./testdata/CondUnclosed",
+			expectedErrCount:   1,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{"a"},
+		},
+		{
+			testName:           "bad test",
+			filename:           "./testdata/CondBadTest",
+			expectedErrCount:   1,
+			expectedSkipCount:  2,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "repeated else",
+			filename:           "./testdata/CondRepeatedElse",
+			expectedErrCount:   1,
+			expectedSkipCount:  2,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{"a"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		t.Setenv(envName, tc.envVal)
+
+		lines := []string{}
+		fp := fileparser.New("intro", lineCollector{lines: &lines})
+		fp.SetProgName("myprog")
+
+		errs := fp.Parse(tc.filename)
+		if len(errs) != tc.expectedErrCount {
+			t.Log(testID)
+			t.Logf("\t: errors: %v", errs)
+			t.Errorf("\t: expected %d errors, got %d\n",
+				tc.expectedErrCount, len(errs))
+		}
+		if fv := fp.Stats().FilesVisited(); fv != tc.expectedFileCount {
+			t.Log(testID)
+			t.Errorf("\t: expected %d files visited, got %d\n",
+				tc.expectedFileCount, fv)
+		}
+		if ls := fp.Stats().LinesSkipped(); ls != tc.expectedSkipCount {
+			t.Log(testID)
+			t.Errorf("\t: expected %d lines skipped, got %d\n",
+				tc.expectedSkipCount, ls)
+		}
+		if testhelper.StringSliceDiff(lines, tc.expectedParsedVals) {
+			t.Log(testID)
+			t.Logf("\t: expected: %v\n", tc.expectedParsedVals)
+			t.Logf("\t:      got: %v\n", lines)
+			t.Errorf("\t: unexpected lines parsed\n")
+		}
+	}
+}
+
+func TestParseIncludes(t *testing.T) {
+	testCases := []struct {
+		testName           string
+		filename           string
+		checks             []filecheck.InfoChecker
+		expectedErrCount   int
+		expectedFileCount  int
+		expectedParsedVals []string
+	}{
+		{
+			testName:           "include dir",
+			filename:           "./testdata/InclDir",
+			expectedFileCount:  4,
+			expectedParsedVals: []string{"a1", "a2", "b", "readme"},
+		},
+		{
+			testName: "include dir, with checks",
+			filename: "./testdata/InclDir",
+			checks: []filecheck.InfoChecker{
+				filecheck.ICNameHasSuffix(".cfg"),
+				filecheck.ICIsRegularFile,
+			},
+			expectedFileCount:  4,
+			expectedParsedVals: []string{"hidden", "a1", "a2", "b"},
+		},
+		{
+			testName:           "include dir, nonexistent",
+			filename:           "./testdata/InclDirNonexistent",
+			expectedErrCount:   1,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "include dir, loop",
+			filename:           "./testdata/InclDirLoop",
+			expectedErrCount:   1,
+			expectedFileCount:  2,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "include glob",
+			filename:           "./testdata/InclGlob",
+			expectedFileCount:  3,
+			expectedParsedVals: []string{"a1", "a2", "b"},
+		},
+		{
+			testName:           "include glob, no match",
+			filename:           "./testdata/InclGlobNoMatch",
+			expectedErrCount:   1,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "include optional",
+			filename:           "./testdata/InclOptional",
+			expectedFileCount:  2,
+			expectedParsedVals: []string{"b"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+
+		lines := []string{}
+		fp := fileparser.New("intro", lineCollector{lines: &lines})
+		if tc.checks != nil {
+			fp.SetInclDirChecks(tc.checks...)
+		}
+
+		errs := fp.Parse(tc.filename)
+		if len(errs) != tc.expectedErrCount {
+			t.Log(testID)
+			t.Logf("\t: errors: %v", errs)
+			t.Errorf("\t: expected %d errors, got %d\n",
+				tc.expectedErrCount, len(errs))
+		}
+		if fv := fp.Stats().FilesVisited(); fv != tc.expectedFileCount {
+			t.Log(testID)
+			t.Errorf("\t: expected %d files visited, got %d\n",
+				tc.expectedFileCount, fv)
+		}
+		if testhelper.StringSliceDiff(lines, tc.expectedParsedVals) {
+			t.Log(testID)
+			t.Logf("\t: expected: %v\n", tc.expectedParsedVals)
+			t.Logf("\t:      got: %v\n", lines)
+			t.Errorf("\t: unexpected lines parsed\n")
+		}
+	}
+}
+
+func TestParseMultiLine(t *testing.T) {
+	testCases := []struct {
+		testName           string
+		filename           string
+		expectedErrCount   int
+		expectedFileCount  int
+		expectedLineCount  int
+		expectedParsedVals []string
+		expectedIdxs       []int64
+		dfltSyntax         bool
+	}{
+		{
+			testName:          "default syntax - no quotes, continuations or heredocs",
+			filename:          "./testdata/BaselineStyle",
+			dfltSyntax:        true,
+			expectedFileCount: 1,
+			expectedLineCount: 7,
+			expectedParsedVals: []string{
+				`dir = C:\tmp\`,
+				"next=1",
+				"k=v",
+				`q = "unterminated`,
+				"s = it's 'odd",
+				"h = <<EOF",
+				"EOF",
+			},
+			expectedIdxs: []int64{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			testName:          "quotes, continuations and heredocs",
+			filename:          "./testdata/MultiLine",
+			expectedFileCount: 1,
+			expectedLineCount: 14,
+			expectedParsedVals: []string{
+				"url = http://example.com/path",
+				`q1 = "a // not a comment"`,
+				`q2 = '  spaced  '`,
+				"it's fine",
+				"cont = one two three",
+				`qcont = "abc   def"`,
+				`doc = "  line 1 // not a comment\n#include not a directive"`,
+				"last",
+			},
+			expectedIdxs: []int64{1, 2, 3, 4, 5, 8, 10, 14},
+		},
+		{
+			testName:           "bad quotes and heredocs",
+			filename:           "./testdata/MultiLineBad",
+			expectedErrCount:   2,
+			expectedFileCount:  1,
+			expectedLineCount:  3,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "bad continuation",
+			filename:           "./testdata/MultiLineBadCont",
+			expectedErrCount:   1,
+			expectedFileCount:  1,
+			expectedLineCount:  1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "quoted include file names",
+			filename:           "./testdata/QuotedInclude",
+			expectedFileCount:  2,
+			expectedLineCount:  2,
+			expectedParsedVals: []string{},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+
+		lines := []string{}
+		idxs := []int64{}
+		fp := fileparser.New("intro", lineCollector{lines: &lines, idxs: &idxs})
+		if !tc.dfltSyntax {
+			fp.SetQuoting(true)
+			fp.SetContinuation(true)
+			fp.SetHeredocIntro(fileparser.DefaultHeredocIntro)
+		}
+
+		errs := fp.Parse(tc.filename)
+		if len(errs) != tc.expectedErrCount {
+			t.Log(testID)
+			t.Logf("\t: errors: %v", errs)
+			t.Errorf("\t: expected %d errors, got %d\n",
+				tc.expectedErrCount, len(errs))
+		}
+		if fv := fp.Stats().FilesVisited(); fv != tc.expectedFileCount {
+			t.Log(testID)
+			t.Errorf("\t: expected %d files visited, got %d\n",
+				tc.expectedFileCount, fv)
+		}
+		if lr := fp.Stats().LinesRead(); lr != tc.expectedLineCount {
+			t.Log(testID)
+			t.Errorf("\t: expected %d lines read, got %d\n",
+				tc.expectedLineCount, lr)
+		}
+		if testhelper.StringSliceDiff(lines, tc.expectedParsedVals) {
+			t.Log(testID)
+			t.Logf("\t: expected: %q\n", tc.expectedParsedVals)
+			t.Logf("\t:      got: %q\n", lines)
+			t.Errorf("\t: unexpected lines parsed\n")
+		}
+		if tc.expectedIdxs != nil && fmt.Sprint(idxs) != fmt.Sprint(tc.expectedIdxs) {
+			t.Log(testID)
+			t.Logf("\t: expected: %v\n", tc.expectedIdxs)
+			t.Logf("\t:      got: %v\n", idxs)
+			t.Errorf("\t: unexpected line locations\n")
+		}
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	testCases := []struct {
+		testName  string
+		val       string
+		expectErr bool
+		expVal    string
+	}{
+		{testName: "empty", val: "", expVal: ""},
+		{testName: "not quoted", val: `a "b"`, expVal: `a "b"`},
+		{testName: "double quoted", val: `" a\tb "`, expVal: " a\tb "},
+		{testName: "double quoted, bad", val: `"a`, expectErr: true},
+		{testName: "single quoted", val: `' a\tb '`, expVal: ` a\tb `},
+		{testName: "single quoted, bad", val: `'a`, expectErr: true},
+		{testName: "single quoted, embedded", val: `'a'b'`, expectErr: true},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		v, err := fileparser.Unquote(tc.val)
+		if tc.expectErr {
+			if err == nil {
+				t.Log(testID)
+				t.Errorf("\t: an error was expected but none was seen\n")
+			}
+			continue
+		}
+		if err != nil {
+			t.Log(testID)
+			t.Errorf("\t: unexpected error: %s\n", err)
+		} else if v != tc.expVal {
+			t.Log(testID)
+			t.Errorf("\t: expected: %q, got: %q\n", tc.expVal, v)
+		}
+	}
+}
+
+func TestParseFS(t *testing.T) {
+	mapFS := fstest.MapFS{
+		"main.cfg": &fstest.MapFile{
+			Data: []byte("a\n" +
+				"#include sub/x.cfg\n" +
+				"#include-dir conf\n" +
+				"#include /top.cfg\n"),
+		},
+		"sub/x.cfg":  &fstest.MapFile{Data: []byte("x\n#include ../top.cfg\n")},
+		"top.cfg":    &fstest.MapFile{Data: []byte("top\n")},
+		"conf/1.cfg": &fstest.MapFile{Data: []byte("c1\n")},
+		"conf/2.cfg": &fstest.MapFile{Data: []byte("c2\n")},
+		"loop.cfg":   &fstest.MapFile{Data: []byte("#include ./loop.cfg\n")},
+	}
+
+	testCases := []struct {
+		testName           string
+		fsys               fs.FS
+		filename           string
+		r                  io.Reader
+		expectedErrCount   int
+		expectedFileCount  int
+		expectedParsedVals []string
+	}{
+		{
+			testName:           "in-memory",
+			fsys:               mapFS,
+			filename:           "main.cfg",
+			expectedFileCount:  6,
+			expectedParsedVals: []string{"a", "x", "top", "c1", "c2", "top"},
+		},
+		{
+			testName:           "in-memory, loop",
+			fsys:               mapFS,
+			filename:           "loop.cfg",
+			expectedErrCount:   1,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "in-memory, bad name",
+			fsys:               mapFS,
+			filename:           "../main.cfg",
+			expectedErrCount:   1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "in-memory, nonexistent",
+			fsys:               mapFS,
+			filename:           "nonesuch.cfg",
+			expectedErrCount:   1,
+			expectedParsedVals: []string{},
+		},
+		{
+			testName:           "directory",
+			fsys:               os.DirFS("testdata"),
+			filename:           "InclGlob",
+			expectedFileCount:  3,
+			expectedParsedVals: []string{"a1", "a2", "b"},
+		},
+		{
+			testName:           "reader",
+			fsys:               mapFS,
+			filename:           "reader",
+			r:                  strings.NewReader("r\n#include conf/1.cfg\n"),
+			expectedFileCount:  2,
+			expectedParsedVals: []string{"r", "c1"},
+		},
+		{
+			testName:           "reader, no file system",
+			filename:           "reader",
+			r:                  strings.NewReader("r\n#include conf/1.cfg\n"),
+			expectedErrCount:   1,
+			expectedFileCount:  1,
+			expectedParsedVals: []string{"r"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+
+		lines := []string{}
+		fp := fileparser.New("intro", lineCollector{lines: &lines})
+
+		var errs []error
+		if tc.r != nil {
+			errs = fp.ParseReader(tc.r, tc.filename, tc.fsys)
+		} else {
+			errs = fp.ParseFS(tc.fsys, tc.filename)
+		}
+		if len(errs) != tc.expectedErrCount {
+			t.Log(testID)
+			t.Logf("\t: errors: %v", errs)
+			t.Errorf("\t: expected %d errors, got %d\n",
+				tc.expectedErrCount, len(errs))
+		}
+		if fv := fp.Stats().FilesVisited(); fv != tc.expectedFileCount {
+			t.Log(testID)
+			t.Errorf("\t: expected %d files visited, got %d\n",
+				tc.expectedFileCount, fv)
+		}
+		if testhelper.StringSliceDiff(lines, tc.expectedParsedVals) {
+			t.Log(testID)
+			t.Logf("\t: expected: %v\n", tc.expectedParsedVals)
+			t.Logf("\t:      got: %v\n", lines)
+			t.Errorf("\t: unexpected lines parsed\n")
+		}
+	}
+}
+
+// countingFS is an fs.FS which counts the number of times each file is
+// opened
+type countingFS struct {
+	fs.FS
+
+	mtx    sync.Mutex
+	counts map[string]int
+}
+
+// Open counts the opening of the file and opens it
+func (cfs *countingFS) Open(name string) (fs.File, error) {
+	cfs.mtx.Lock()
+	cfs.counts[name]++
+	cfs.mtx.Unlock()
+	return cfs.FS.Open(name)
+}
+
+// Stat returns the FileInfo for the file without counting it as opened
+func (cfs *countingFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(cfs.FS, name)
+}
+
+// ReadDir reads the directory without counting it as opened
+func (cfs *countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(cfs.FS, name)
+}
+
+func TestParseConcurrent(t *testing.T) {
+	const fileCount = 50
+	mapFS := fstest.MapFS{
+		"shared.cfg": &fstest.MapFile{Data: []byte("shared\n")},
+		"conf/late.cfg": &fstest.MapFile{
+			Data: []byte("#if env:GOLEM_FP_TEST=yes\n" +
+				"#include ../shared.cfg\n" +
+				"#endif\n" +
+				"late\n"),
+		},
+	}
+	var mainCfg strings.Builder
+	expLines := []string{}
+	for i := 0; i < fileCount; i++ {
+		name := fmt.Sprintf("inc%02d.cfg", i)
+		mainCfg.WriteString("#include " + name + "\n")
+		mapFS[name] = &fstest.MapFile{
+			Data: []byte(fmt.Sprintf("val%02d\n#include shared.cfg\n", i)),
+		}
+		expLines = append(expLines, fmt.Sprintf("val%02d", i), "shared")
+	}
+	mainCfg.WriteString("#if env:GOLEM_FP_TEST=yes\n" +
+		"#include inactive.cfg\n" +
+		"#endif\n")
+	mapFS["inactive.cfg"] = &fstest.MapFile{Data: []byte("inactive\n")}
+	mainCfg.WriteString("#include-dir conf\nlast\n")
+	expLines = append(expLines, "late", "last")
+	mapFS["main.cfg"] = &fstest.MapFile{Data: []byte(mainCfg.String())}
+
+	var expStats fileparser.Stats
+	for _, concurrency := range []int{1, 2, 8} {
+		testID := fmt.Sprintf("concurrency: %d", concurrency)
+		cfs := &countingFS{FS: mapFS, counts: map[string]int{}}
+
+		lines := []string{}
+		fp := fileparser.New("intro", lineCollector{lines: &lines})
+		fp.SetConcurrency(concurrency)
+
+		errs := fp.ParseFS(cfs, "main.cfg")
+		if len(errs) != 0 {
+			t.Log(testID)
+			t.Errorf("\t: unexpected errors: %v\n", errs)
+		}
+		if testhelper.StringSliceDiff(lines, expLines) {
+			t.Log(testID)
+			t.Logf("\t: expected: %v\n", expLines)
+			t.Logf("\t:      got: %v\n", lines)
+			t.Errorf("\t: unexpected lines parsed\n")
+		}
+		if cfs.counts["inactive.cfg"] != 0 {
+			t.Log(testID)
+			t.Errorf("\t: a file included in an inactive section was read\n")
+		}
+		for name, count := range cfs.counts {
+			if count != 1 {
+				t.Log(testID)
+				t.Errorf("\t: %s was opened %d times\n", name, count)
+			}
+		}
+
+		stats := fp.Stats()
+		if stats.FilesVisited() != 2*fileCount+2 {
+			t.Log(testID)
+			t.Errorf("\t: expected %d files visited, got %d\n",
+				2*fileCount+2, stats.FilesVisited())
+		}
+		if stats.BytesRead() == 0 {
+			t.Log(testID)
+			t.Errorf("\t: no bytes were read\n")
+		}
+		if concurrency == 1 {
+			expStats = stats
+		} else if stats.LinesRead() != expStats.LinesRead() ||
+			stats.BytesRead() != expStats.BytesRead() ||
+			stats.LinesSkipped() != expStats.LinesSkipped() {
+			t.Log(testID)
+			t.Logf("\t: expected: %s\n", expStats)
+			t.Logf("\t:      got: %s\n", stats)
+			t.Errorf("\t: the stats differ from those when reading sequentially\n")
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package fileparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unquote returns the value with any surrounding quotes removed. If the
+// value starts with a double quote it must be a valid Go double-quoted
+// string and any escape sequences are interpreted (see strconv.Unquote). If
+// it starts with a single quote it must end with a single quote and the
+// text in between, which may not contain a single quote, is returned
+// unchanged. Otherwise the value is returned unchanged.
+//
+// A LineParser can use this to get the value given in a quoted string or in
+// a heredoc block (which is passed to the LineParser as a double-quoted
+// string).
+func Unquote(s string) (string, error) {
+	if s == "" {
+		return s, nil
+	}
+
+	switch s[0] {
+	case '"':
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("bad double-quoted string: %s", s)
+		}
+		return v, nil
+	case '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' ||
+			strings.ContainsRune(s[1:len(s)-1], '\'') {
+			return "", fmt.Errorf("bad single-quoted string: %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return s, nil
+}
+
+// unquote returns the value with any surrounding quotes removed (see
+// Unquote) if quoting is on. Otherwise the value is returned unchanged.
+func (fp *FP) unquote(s string) (string, error) {
+	if !fp.quoting {
+		return s, nil
+	}
+	return Unquote(s)
+}
+
+// isSpace returns true if the character is a space or a tab
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// startsToken returns true if the character is one which can precede the
+// start of a quoted string. This is so that an apostrophe in the middle of
+// a word is not taken as the start of a quoted string
+func startsToken(c byte) bool {
+	return isSpace(c) || c == '=' || c == ','
+}
+
+// splitComment returns the part of the line before any comment and the
+// quote character of any quoted string which is still open at the end of
+// the line (or zero if there is none). The quote parameter gives the quote
+// character of any quoted string which is open at the start of the line (as
+// when a quoted string is continued from the previous line).
+//
+// If quoting is off the line is split at the first comment introducer and
+// there is never an open quoted string. Otherwise a comment introducer
+// inside a quoted string does not start a comment and nor does one which is
+// not at the start of the line or preceded by white space. This means that
+// values such as URLs are not truncated.
+func (fp *FP) splitComment(s string, quote byte) (string, byte) {
+	if !fp.quoting {
+		if fp.cmtIntro == "" {
+			return s, 0
+		}
+		return strings.SplitN(s, fp.cmtIntro, 2)[0], 0
+	}
+
+	prev := byte(' ') // the start of the line is treated as white space
+	if quote != 0 {
+		prev = 0
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quote = 0
+			}
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && startsToken(prev):
+			quote = c
+		case fp.cmtIntro != "" && isSpace(prev) &&
+			strings.HasPrefix(s[i:], fp.cmtIntro):
+			return s[:i], 0
+		}
+		prev = c
+	}
+	return s, quote
+}
+
+// heredocTag checks whether the text ends with the heredoc introducer
+// followed by a tag. It returns the tag, the text before the heredoc
+// introducer and true if it does. The tag must consist only of letters,
+// digits and underscores and the heredoc introducer must be at the start of
+// the text or preceded by a character which can precede the start of a
+// quoted string
+func (fp *FP) heredocTag(text string) (string, string, bool) {
+	if fp.heredocIntro == "" {
+		return "", "", false
+	}
+	i := strings.LastIndex(text, fp.heredocIntro)
+	if i < 0 {
+		return "", "", false
+	}
+	if i > 0 && !startsToken(text[i-1]) {
+		return "", "", false
+	}
+
+	tag := text[i+len(fp.heredocIntro):]
+	if tag == "" {
+		return "", "", false
+	}
+	for _, r := range tag {
+		if !(r == '_' ||
+			(r >= 'a' && r <= 'z') ||
+			(r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9')) {
+			return "", "", false
+		}
+	}
+	return tag, text[:i], true
+}
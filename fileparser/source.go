@@ -0,0 +1,235 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// errNoFS is returned when an attempt is made to read a file but there is
+// no file system to read it from
+var errNoFS = errors.New("there is no file system to read from")
+
+// source gives access to the files being parsed. The names it takes and
+// returns are those used in include directives, error messages and loop
+// detection.
+type source interface {
+	// fixName converts the name into the standard form
+	fixName(name string) (string, error)
+	// inclName returns the name of the included file, resolved relative
+	// to the current file
+	inclName(inclName, currentName string) string
+	// join returns the name of the directory entry
+	join(dirName, entryName string) string
+
+	open(name string) (fs.File, error)
+	stat(name string) (fs.FileInfo, error)
+	// readDir returns the names of the directory entries in order
+	readDir(name string) ([]string, error)
+	// glob returns the names of the files matching the pattern in order
+	glob(pattern string) ([]string, error)
+}
+
+// fsSource is a source which reads files from an fs.FS. The names are
+// slash-separated and relative to the root of the file system as described
+// in the fs package. An include directive giving an absolute name is taken
+// to be relative to the root of the file system.
+type fsSource struct {
+	fsys fs.FS
+}
+
+// fixName cleans the name and checks that it is valid for use with an
+// fs.FS
+func (s fsSource) fixName(name string) (string, error) {
+	fixedName := path.Clean(name)
+	if !fs.ValidPath(fixedName) {
+		return fixedName, fmt.Errorf("invalid file system name: '%s'", name)
+	}
+	return fixedName, nil
+}
+
+// inclName returns the include file name with the directory of the current
+// file prepended if it is not an absolute name
+func (s fsSource) inclName(inclName, currentName string) string {
+	if path.IsAbs(inclName) {
+		return strings.TrimLeft(path.Clean(inclName), "/")
+	}
+	return path.Join(path.Dir(currentName), inclName)
+}
+
+// join returns the name of the directory entry
+func (s fsSource) join(dirName, entryName string) string {
+	return path.Join(dirName, entryName)
+}
+
+// open opens the named file
+func (s fsSource) open(name string) (fs.File, error) {
+	if s.fsys == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errNoFS}
+	}
+	return s.fsys.Open(name)
+}
+
+// stat returns the FileInfo for the named file
+func (s fsSource) stat(name string) (fs.FileInfo, error) {
+	if s.fsys == nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: errNoFS}
+	}
+	return fs.Stat(s.fsys, name)
+}
+
+// readDir returns the names of the entries in the named directory
+func (s fsSource) readDir(name string) ([]string, error) {
+	if s.fsys == nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNoFS}
+	}
+	entries, err := fs.ReadDir(s.fsys, name)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names, err
+}
+
+// glob returns the names of the files matching the pattern
+func (s fsSource) glob(pattern string) ([]string, error) {
+	if s.fsys == nil {
+		return nil, errNoFS
+	}
+	names, err := fs.Glob(s.fsys, pattern)
+	sort.Strings(names)
+	return names, err
+}
+
+// osSource is a source which reads files from the operating system's file
+// system through an fs.FS rooted at the root directory. The names are
+// operating system pathnames which are converted into names in the fs.FS
+// before use.
+type osSource struct {
+	fsSource
+}
+
+// newOSSource returns an osSource
+func newOSSource() osSource {
+	return osSource{fsSource: fsSource{fsys: os.DirFS("/")}}
+}
+
+// fsName converts the pathname into the name of the file in the fs.FS
+func fsName(name string) (string, error) {
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	absName = strings.TrimLeft(filepath.ToSlash(absName), "/")
+	if absName == "" {
+		return ".", nil
+	}
+	return absName, nil
+}
+
+// fixPathErr sets the path in the error, if it is an fs.PathError, to the
+// pathname rather than the name in the fs.FS
+func fixPathErr(err error, name string) error {
+	var pErr *fs.PathError
+	if errors.As(err, &pErr) {
+		pErr.Path = name
+	}
+	return err
+}
+
+// fixName cleans the name and expands any leading '~' (see FixFileName)
+func (s osSource) fixName(name string) (string, error) {
+	return FixFileName(name)
+}
+
+// inclName returns the include file name with the directory of the current
+// file prepended if it is not an absolute pathname
+func (s osSource) inclName(inclName, currentName string) string {
+	return fixIncludeFileName(inclName, currentName)
+}
+
+// join returns the pathname of the directory entry
+func (s osSource) join(dirName, entryName string) string {
+	return filepath.Join(dirName, entryName)
+}
+
+// open opens the named file
+func (s osSource) open(name string) (fs.File, error) {
+	n, err := fsName(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.fsSource.open(n)
+	return f, fixPathErr(err, name)
+}
+
+// stat returns the FileInfo for the named file
+func (s osSource) stat(name string) (fs.FileInfo, error) {
+	n, err := fsName(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := s.fsSource.stat(n)
+	return fi, fixPathErr(err, name)
+}
+
+// readDir returns the names of the entries in the named directory
+func (s osSource) readDir(name string) ([]string, error) {
+	n, err := fsName(name)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.fsSource.readDir(n)
+	return names, fixPathErr(err, name)
+}
+
+// escapeMeta returns the name with any characters which are special in a
+// pattern escaped so that they only match themselves
+func escapeMeta(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		if strings.ContainsRune(`*?[\`, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// glob returns the pathnames of the files matching the pattern. If the
+// pattern is relative the pathnames are relative to the current directory
+func (s osSource) glob(pattern string) ([]string, error) {
+	var dir string
+	fsPattern := filepath.ToSlash(pattern)
+	if !filepath.IsAbs(pattern) {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+		fsDir, err := fsName(dir)
+		if err != nil {
+			return nil, err
+		}
+		fsPattern = path.Join(escapeMeta(fsDir), fsPattern)
+	}
+	fsPattern = strings.TrimLeft(fsPattern, "/")
+
+	matches, err := s.fsSource.glob(fsPattern)
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		n := filepath.FromSlash("/" + m)
+		if dir != "" {
+			if relName, err := filepath.Rel(dir, n); err == nil {
+				n = relName
+			}
+		}
+		names = append(names, n)
+	}
+	return names, err
+}
@@ -1,18 +1,27 @@
 package fileparser
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Stats records various details of the operation of the FileParser
 type Stats struct {
 	filesVisited int
 	linesRead    int
 	linesParsed  int
+	linesSkipped int
+	bytesRead    int64
+	duration     time.Duration
 }
 
 // String reports the contents of a Stats object
 func (s Stats) String() string {
-	return fmt.Sprintf("files: %3d lines read: %5d parsed: %5d",
-		s.filesVisited, s.linesRead, s.linesParsed)
+	return fmt.Sprintf(
+		"files: %3d lines read: %5d parsed: %5d skipped: %5d"+
+			" bytes: %8d time: %s",
+		s.filesVisited, s.linesRead, s.linesParsed, s.linesSkipped,
+		s.bytesRead, s.duration)
 }
 
 // FilesVisited returns the number of files visited by the FileParser during
@@ -26,3 +35,16 @@ func (s Stats) LinesRead() int { return s.linesRead }
 // LinesParsed returns the number of lines parsed by the FileParser (the number
 // of lines for which ParseLine was called) during the last call to Parse
 func (s Stats) LinesParsed() int { return s.linesParsed }
+
+// LinesSkipped returns the number of lines skipped by the FileParser because
+// they were in the inactive part of a conditional section during the last
+// call to Parse
+func (s Stats) LinesSkipped() int { return s.linesSkipped }
+
+// BytesRead returns the number of bytes in the files parsed by the
+// FileParser during the last call to Parse. As for the lines read, a file
+// which is included more than once is counted each time it is included.
+func (s Stats) BytesRead() int64 { return s.bytesRead }
+
+// Duration returns the time taken by the last call to Parse
+func (s Stats) Duration() time.Duration { return s.duration }
@@ -116,6 +116,10 @@ type ActionFunc func(source string, loc location.L, p *ByName, paramValues []str
 // parameters have been set
 type FinalCheckFunc func() error
 
+// CleanupFunc is the type of a function to be called to release any
+// resources held once the parameter values are no longer needed
+type CleanupFunc func() error
+
 // =============================================
 
 // OptFunc is the type of a option func used to set various flags etc on a
@@ -144,6 +148,7 @@ func (ps *ParamSet) Add(name string,
 	}
 
 	setter.CheckSetter(name)
+	ps.addSetterCleanup(setter)
 
 	ppCount := len(ps.byPos)
 	if ppCount > 0 &&
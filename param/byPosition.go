@@ -58,6 +58,7 @@ func (ps *ParamSet) AddByPos(name string,
 	}
 
 	setter.CheckSetter(name)
+	ps.addSetterCleanup(setter)
 
 	checkTerminalFlags(ps)
 
@@ -0,0 +1,59 @@
+package param_test
+
+import (
+	"errors"
+	"github.com/nickwells/golem/param/paramset"
+	"github.com/nickwells/golem/param/psetter"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanup(t *testing.T) {
+	var out io.WriteCloser
+	var calls []string
+
+	ps, err := paramset.NewNoHelpNoExitNoErrRpt()
+	if err != nil {
+		t.Fatal("couldn't construct the ParamSet: ", err)
+	}
+	ps.AddCleanup(func() error {
+		calls = append(calls, "first")
+		return errors.New("first error")
+	})
+	ps.Add("out", psetter.WriterSetter{Value: &out}, "the output file")
+	ps.AddCleanup(func() error {
+		calls = append(calls, "last")
+		return errors.New("last error")
+	})
+
+	pathname := filepath.Join(t.TempDir(), "out")
+	if errs := ps.Parse([]string{"-out", pathname}); len(errs) != 0 {
+		t.Fatal("unexpected errors: ", errs)
+	}
+	if _, err := io.WriteString(out, "written"); err != nil {
+		t.Fatal("unexpected error writing: ", err)
+	}
+
+	err = ps.Cleanup()
+	if err == nil || err.Error() != "last error" {
+		t.Errorf("Cleanup should return the first error seen, got: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "last" || calls[1] != "first" {
+		t.Errorf("the cleanups should be called in reverse order, got: %v",
+			calls)
+	}
+	if out != nil {
+		t.Errorf("the WriterSetter Cleanup should have been called")
+	}
+	if content, err := os.ReadFile(pathname); err != nil {
+		t.Error("unexpected error reading: ", err)
+	} else if string(content) != "written" {
+		t.Errorf("the file should hold %q, not %q", "written", content)
+	}
+
+	if err := ps.Cleanup(); err != nil || len(calls) != 2 {
+		t.Errorf("a second Cleanup should do nothing")
+	}
+}
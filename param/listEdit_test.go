@@ -0,0 +1,77 @@
+package param_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/filecheck"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/paramset"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+)
+
+func TestListEdit(t *testing.T) {
+	var tags []string
+	var nums []int64
+	var name string
+
+	ps, err := paramset.NewNoHelpNoExitNoErrRpt()
+	if err != nil {
+		t.Fatal("couldn't construct the ParamSet: ", err)
+	}
+	pTags := ps.Add("tags",
+		psetter.StrListSetter{
+			Value:    &tags,
+			ListEdit: psetter.ListEdit{EditPrefixes: true},
+		},
+		"tags")
+	pNums := ps.Add("nums",
+		psetter.Int64ListSetter{
+			Value:    &nums,
+			ListEdit: psetter.ListEdit{Append: true, EditPrefixes: true},
+		},
+		"numbers")
+	ps.Add("name", psetter.StringSetter{Value: &name}, "a name")
+	ps.SetConfigFile("./testdata/config.listEdit", filecheck.MustExist)
+
+	errs := ps.Parse([]string{
+		"-tags=a,b",
+		"-tags+=c",
+		"-tags-=a",
+		"-tags+", "d",
+		"-nums", "1,2",
+		"-nums", "3",
+		"-nums-=2",
+		"-name+=x",
+	})
+
+	if len(errs) != 1 {
+		t.Errorf("there should be 1 error but there were %d: %v",
+			len(errs), errs)
+	} else if _, ok := errs["name+"]; !ok {
+		t.Errorf("the error should be for 'name+' (the StringSetter"+
+			" does not edit lists) but got: %v", errs)
+	}
+
+	expTags := []string{"b", "c", "d"}
+	if testhelper.StringSliceDiff(tags, expTags) {
+		t.Errorf("tags should be %v but are %v", expTags, tags)
+	}
+	if fmt.Sprint(nums) != "[5 1 3]" {
+		t.Errorf("nums should be [5 1 3] but are %v", nums)
+	}
+
+	whereSet := map[string]struct {
+		p      *param.ByName
+		expLen int
+	}{
+		"tags": {p: pTags, expLen: 5},
+		"nums": {p: pNums, expLen: 4},
+	}
+	for name, ws := range whereSet {
+		if got := len(ws.p.WhereSet()); got != ws.expLen {
+			t.Errorf("%s should have been set in %d places but was set in %d: %v",
+				name, ws.expLen, got, ws.p.WhereSet())
+		}
+	}
+}
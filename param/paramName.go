@@ -13,7 +13,7 @@ var nameCheckRE *regexp.Regexp
 // =============================================
 
 func init() {
-	nameCheckRE = regexp.MustCompile("^[a-zA-Z][-a-zA-Z0-9]*$")
+	nameCheckRE = regexp.MustCompile(`^\pL[-\pL\p{Mn}\p{Nd}]*$`)
 }
 
 // nameCheck returns an error if the name is invalid or if it has already
@@ -49,6 +49,7 @@ type ParamSet struct {
 	unusedParams    map[string][]string
 	errors          ErrMap
 	finalChecks     []FinalCheckFunc
+	cleanups        []CleanupFunc
 	envPrefixes     []string
 	configFiles     []ConfigFileDetails
 	groupCfgFiles   map[string][]ConfigFileDetails
@@ -62,6 +63,7 @@ type ParamSet struct {
 	helper Helper
 
 	exitOnParamSetupErr bool
+	extCfgSyntax        bool
 }
 
 // ParamSetOptFunc is the type of a function that can be passed to NewSet
@@ -94,6 +96,18 @@ func DontExitOnParamSetupErr(ps *ParamSet) error {
 	return nil
 }
 
+// ExtendedConfigSyntax turns on the extended syntax for config files. This
+// allows a value to be given in double or single quotes, which are removed
+// (see fileparser.Unquote), a line ending with a backslash to be continued
+// on the next line and a value to be given as a heredoc block. By default
+// config files are read as they always have been and the values are used as
+// given, so that existing config files with values containing quotes or
+// backslashes are not changed.
+func ExtendedConfigSyntax(ps *ParamSet) error {
+	ps.extCfgSyntax = true
+	return nil
+}
+
 // SetErrWriter returns a ParamSetOptFunc which can be passed to NewSet. It
 // sets the Writer to which error messages are written
 func SetErrWriter(w io.Writer) ParamSetOptFunc {
@@ -260,6 +274,47 @@ func cleanParamParts(p *ByName, paramParts []string) []string {
 	return paramParts
 }
 
+// listEditOps are the operators which may follow the name of a parameter
+// whose Setter is a ListEditor
+const listEditOps = "+-"
+
+// findParam returns the parameter with the given name. If there is no such
+// parameter but the name is that of a parameter whose Setter is a
+// ListEditor which accepts edits followed by one of the listEditOps then
+// that parameter is returned together with the operator. It returns nil if
+// no parameter is found.
+func (ps *ParamSet) findParam(name string) (p *ByName, editOp string) {
+	if p, ok := ps.nameToParam[name]; ok {
+		return p, ""
+	}
+
+	if name == "" || !strings.ContainsAny(name[len(name)-1:], listEditOps) {
+		return nil, ""
+	}
+	p, ok := ps.nameToParam[strings.TrimSpace(name[:len(name)-1])]
+	if !ok {
+		return nil, ""
+	}
+	if le, ok := p.setter.(ListEditor); !ok || !le.EditsList() {
+		return nil, ""
+	}
+	return p, name[len(name)-1:]
+}
+
+// addEditOp moves the edit operator into the value so that the value is
+// passed to the Setter as, for instance, "+=value"
+func addEditOp(editOp string, paramParts []string) []string {
+	if editOp == "" {
+		return paramParts
+	}
+
+	val := ""
+	if len(paramParts) > 1 {
+		val = paramParts[1]
+	}
+	return []string{paramParts[0], editOp + "=" + val}
+}
+
 // recordUnexpectedParam records that the named parameter is not a parameter
 // of this program and if a close match is found it will suggest that
 // alternative in the error message
@@ -276,7 +331,8 @@ func (ps *ParamSet) recordUnexpectedParam(paramName string, loc *location.L) {
 
 func (ps *ParamSet) setNonCommandLineValue(paramParts []string, source string, loc *location.L) bool {
 	paramName := paramParts[0]
-	p, exists := ps.nameToParam[paramName]
+	p, editOp := ps.findParam(paramName)
+	exists := p != nil
 
 	if !exists {
 		ps.markAsUnused(paramName, loc)
@@ -288,7 +344,7 @@ func (ps *ParamSet) setNonCommandLineValue(paramParts []string, source string, l
 		return false
 	}
 
-	paramParts = cleanParamParts(p, paramParts)
+	paramParts = addEditOp(editOp, cleanParamParts(p, paramParts))
 
 	p.processParam(source, loc, paramParts)
 	return true
@@ -304,7 +360,8 @@ const (
 func (ps *ParamSet) setValueFromGroupFile(paramParts []string, loc *location.L, gName string) {
 	//XXX - needs to be changed
 	paramName := paramParts[0]
-	p, exists := ps.nameToParam[paramName]
+	p, editOp := ps.findParam(paramName)
+	exists := p != nil
 
 	if !exists {
 		ps.recordUnexpectedParam(paramName, loc)
@@ -321,7 +378,7 @@ func (ps *ParamSet) setValueFromGroupFile(paramParts []string, loc *location.L,
 		return
 	}
 
-	paramParts = cleanParamParts(p, paramParts)
+	paramParts = addEditOp(editOp, cleanParamParts(p, paramParts))
 
 	p.processParam("group-specific parameter configuration file",
 		loc, paramParts)
@@ -330,7 +387,8 @@ func (ps *ParamSet) setValueFromGroupFile(paramParts []string, loc *location.L,
 
 func (ps *ParamSet) setValueFromFile(paramParts []string, loc *location.L, eRule existanceRule) {
 	paramName := paramParts[0]
-	p, exists := ps.nameToParam[paramName]
+	p, editOp := ps.findParam(paramName)
+	exists := p != nil
 
 	if !exists {
 		if eRule == paramMustExist {
@@ -346,7 +404,7 @@ func (ps *ParamSet) setValueFromFile(paramParts []string, loc *location.L, eRule
 		return
 	}
 
-	paramParts = cleanParamParts(p, paramParts)
+	paramParts = addEditOp(editOp, cleanParamParts(p, paramParts))
 
 	p.processParam("parameter configuration file", loc, paramParts)
 	return
@@ -385,6 +443,39 @@ func (ps *ParamSet) AddFinalCheck(fcf FinalCheckFunc) {
 	ps.finalChecks = append(ps.finalChecks, fcf)
 }
 
+// AddCleanup will add a function to the list of functions to be called by
+// Cleanup. This can be used to release resources acquired while setting
+// parameter values. Parameters whose Setter is a Cleaner have the Cleanup
+// method added automatically
+func (ps *ParamSet) AddCleanup(cf CleanupFunc) {
+	ps.cleanups = append(ps.cleanups, cf)
+}
+
+// addSetterCleanup adds the Cleanup method of the setter to the list of
+// cleanup functions if the setter is a Cleaner
+func (ps *ParamSet) addSetterCleanup(setter Setter) {
+	if c, ok := setter.(Cleaner); ok {
+		ps.AddCleanup(c.Cleanup)
+	}
+}
+
+// Cleanup calls the cleanup functions in the reverse of the order in which
+// they were added. All the functions are called even if some return an
+// error; the first error is returned. Each function is only called once so
+// calling Cleanup again does nothing. A program using setters which open
+// files should typically call this, deferred, after calling Parse
+func (ps *ParamSet) Cleanup() error {
+	var rval error
+	for i := len(ps.cleanups) - 1; i >= 0; i-- {
+		err := ps.cleanups[i]()
+		if err != nil && rval == nil {
+			rval = err
+		}
+	}
+	ps.cleanups = nil
+	return rval
+}
+
 // SetTerminalParam sets the value of the parameter that is used to terminate
 // the processing of parameters. This can be used to override the default
 // value which is set to DfltTerminalParam
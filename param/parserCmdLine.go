@@ -3,6 +3,7 @@ package param
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/nickwells/golem/location"
@@ -12,16 +13,41 @@ import (
 // Remainder returns any arguments that come after the terminal parameter
 func (ps *ParamSet) Remainder() []string { return ps.remainingParams }
 
+// maxSuggestions is the largest number of alternative parameter names that
+// will be suggested
+const maxSuggestions = 3
+
 // findClosestMatch finds parameters with the name which is the shortest
-// distance from the passed value and returns a string describing them
+// distance from the passed value and returns a string describing them. Any
+// parameters whose names have the same words as the passed value but in a
+// different order are given first.
 func (ps *ParamSet) findClosestMatch(badParam string) string {
 	paramNames := make([]string, 0, len(ps.nameToParam))
 	for p := range ps.nameToParam {
 		paramNames = append(paramNames, p)
 	}
+	sort.Strings(paramNames)
+
+	matches := make([]string, 0, maxSuggestions)
+	seen := make(map[string]bool)
+	lcBadParam := strdist.ForceToLower.Apply(badParam)
+	for _, p := range paramNames {
+		if strdist.TokenSortDistance(lcBadParam, strdist.ForceToLower.Apply(p),
+			strdist.DfltTokeniser) == 0 {
+			matches = append(matches, p)
+			seen[p] = true
+		}
+	}
 
-	matches := strdist.CaseBlindCosineFinder.FindNStrLike(
-		3, badParam, paramNames...)
+	for _, p := range strdist.CaseBlindCompositeFinder.FindStrLike(
+		badParam, paramNames...) {
+		if !seen[p] {
+			matches = append(matches, p)
+		}
+	}
+	if len(matches) > maxSuggestions {
+		matches = matches[:maxSuggestions]
+	}
 
 	return strings.Join(matches, " or ")
 }
@@ -98,7 +124,7 @@ func (ps *ParamSet) getParamsFromStringSlice(source string, params []string) {
 			continue
 		}
 
-		if p, ok := ps.nameToParam[trimmedParam]; ok {
+		if p, editOp := ps.findParam(trimmedParam); p != nil {
 			if p.setter.ValueReq() == Mandatory &&
 				len(paramParts) == 1 {
 				if i < (len(params) - 1) {
@@ -108,7 +134,7 @@ func (ps *ParamSet) getParamsFromStringSlice(source string, params []string) {
 					loc.SetContent(pStr + " " + params[i])
 				}
 			}
-			p.processParam(source, loc, paramParts)
+			p.processParam(source, loc, addEditOp(editOp, paramParts))
 		} else {
 			ps.recordUnexpectedParam(trimmedParam, loc)
 		}
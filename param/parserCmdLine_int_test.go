@@ -0,0 +1,78 @@
+package param
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindClosestMatch(t *testing.T) {
+	ps := &ParamSet{
+		nameToParam: map[string]*ByName{
+			"name":            nil,
+			"game":            nil,
+			"verbosity":       nil,
+			"params":          nil,
+			"value":           nil,
+			"v":               nil,
+			"dry-run":         nil,
+			"run-tests":       nil,
+			"show-all-params": nil,
+		},
+	}
+	testCases := []struct {
+		testName string
+		badParam string
+		expMatch string
+	}{
+		{
+			testName: "adjacent key",
+			badParam: "vakue",
+			expMatch: "value",
+		},
+		{
+			testName: "transposition",
+			badParam: "parmas",
+			expMatch: "params",
+		},
+		{
+			testName: "no close edit, common parts",
+			badParam: "verbose",
+			expMatch: "verbosity",
+		},
+		{
+			testName: "prefix",
+			badParam: "verb",
+			expMatch: "verbosity",
+		},
+		{
+			testName: "short name",
+			badParam: "vx",
+			expMatch: "v",
+		},
+		{
+			testName: "reordered words",
+			badParam: "run-dry",
+			expMatch: "dry-run",
+		},
+		{
+			testName: "reordered words, different case and separators",
+			badParam: "Params_Show_All",
+			expMatch: "show-all-params or params",
+		},
+		{
+			testName: "no match",
+			badParam: "xyzzy",
+			expMatch: "",
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		match := ps.findClosestMatch(tc.badParam)
+		if match != tc.expMatch {
+			t.Log(testID)
+			t.Errorf("\t: findClosestMatch(%q) expected: %q got: %q",
+				tc.badParam, tc.expMatch, match)
+		}
+	}
+}
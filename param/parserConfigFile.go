@@ -58,6 +58,17 @@ func splitParamName(pName string) (progName, paramName string) {
 	return
 }
 
+// configValue returns the value with any surrounding whitespace removed
+// and, if the ExtendedConfigSyntax option has been given, with any
+// surrounding quotes removed
+func (ps *ParamSet) configValue(val string) (string, error) {
+	val = strings.TrimSpace(val)
+	if !ps.extCfgSyntax {
+		return val, nil
+	}
+	return fileparser.Unquote(val)
+}
+
 // ParseLine processes the line.
 //
 // Firstly it splits the line into two parts around an equal sign, the two
@@ -65,7 +76,8 @@ func splitParamName(pName string) (progName, paramName string) {
 // checks that if the parameter specification has a program part then the
 // program name matches the current program name. Finally it attempts to set
 // the parameter value from the parameter name and the value string which has
-// been stripped of any surrounding whitespace
+// been stripped of any surrounding whitespace and, if the ParamSet was
+// created with the ExtendedConfigSyntax option, any surrounding quotes
 func (pflp paramLineParser) ParseLine(line string, loc *location.L) error {
 	paramParts := strings.SplitN(line, "=", 2)
 
@@ -83,7 +95,12 @@ func (pflp paramLineParser) ParseLine(line string, loc *location.L) error {
 	paramParts[0] = paramName
 
 	if len(paramParts) == 2 {
-		paramParts[1] = strings.TrimSpace(paramParts[1])
+		val, err := pflp.ps.configValue(paramParts[1])
+		if err != nil {
+			return loc.Errorf("Bad value for parameter %s: %s",
+				paramName, err.Error())
+		}
+		paramParts[1] = val
 	}
 
 	pflp.ps.setValueFromFile(paramParts, loc, eRule)
@@ -98,7 +115,8 @@ func (pflp paramLineParser) ParseLine(line string, loc *location.L) error {
 // checks that if the parameter specification has a program part then the
 // program name matches the current program name. Finally it attempts to set
 // the parameter value from the parameter name and the value string which has
-// been stripped of any surrounding whitespace
+// been stripped of any surrounding whitespace and, if the ParamSet was
+// created with the ExtendedConfigSyntax option, any surrounding quotes
 func (gpflp groupParamLineParser) ParseLine(line string, loc *location.L) error {
 	paramParts := strings.SplitN(line, "=", 2)
 
@@ -113,7 +131,12 @@ func (gpflp groupParamLineParser) ParseLine(line string, loc *location.L) error
 	paramParts[0] = paramName
 
 	if len(paramParts) == 2 {
-		paramParts[1] = strings.TrimSpace(paramParts[1])
+		val, err := gpflp.ps.configValue(paramParts[1])
+		if err != nil {
+			return loc.Errorf("Bad value for parameter %s: %s",
+				paramName, err.Error())
+		}
+		paramParts[1] = val
 	}
 
 	gpflp.ps.setValueFromGroupFile(paramParts, loc, gpflp.gName)
@@ -136,6 +159,19 @@ func (gpflp groupParamLineParser) ParseLine(line string, loc *location.L) error
 //     myParam  = 42
 //     myParam=42
 //
+// If the ParamSet is created with the ExtendedConfigSyntax option then a
+// value may be given in double or single quotes, which are removed, so that
+// it can have leading or trailing white space or contain the comment
+// introducer. Escape sequences in double-quoted values are interpreted as in
+// Go. A value can also be continued over several lines by ending each line
+// but the last with a backslash or given as a heredoc block:
+//
+//     myParam = "  spaced out  "
+//     myText = <<EOF
+//     first line
+//     second line
+//     EOF
+//
 // The parameter name can be preceded by a program name and a slash in which
 // case the parameter will only be applied when the config file is being
 // parsed by that program. The match is applied to the basename of the
@@ -164,7 +200,9 @@ func (gpflp groupParamLineParser) ParseLine(line string, loc *location.L) error
 // reported as an error.
 //
 // The config file supports the features of a file parsed by the
-// fileparser.FileParser such as comments and include files.
+// fileparser.FileParser such as comments, include files and conditional
+// sections. A conditional section such as one starting with '#if prog=NAME'
+// is compared against the same program name as described above.
 func (ps *ParamSet) SetConfigFile(fName string, c filecheck.Exists) {
 	if c == filecheck.MustNotExist {
 		panic(fmt.Sprintf("config file '%s': bad existence constraint.", fName))
@@ -266,6 +304,17 @@ func checkErrors(ps *ParamSet, errors []error, cf ConfigFileDetails) {
 	}
 }
 
+// setConfigSyntax turns on the extended config file syntax in the file
+// parser if the ExtendedConfigSyntax option has been given
+func (ps *ParamSet) setConfigSyntax(fp *fileparser.FP) {
+	if !ps.extCfgSyntax {
+		return
+	}
+	fp.SetQuoting(true)
+	fp.SetContinuation(true)
+	fp.SetHeredocIntro(fileparser.DefaultHeredocIntro)
+}
+
 // getParamsFromConfigFile will construct a line parser and then parse the
 // config files - the group-specific config files first and then the common
 // files.
@@ -277,6 +326,8 @@ func (ps *ParamSet) getParamsFromConfigFile() {
 			gName: gName,
 		}
 		fileParser := fileparser.New("group-specific parameter config file", lp)
+		fileParser.SetProgName(ps.progBaseName)
+		ps.setConfigSyntax(fileParser)
 		for _, cf := range cfs {
 			errors := fileParser.Parse(cf.Name)
 
@@ -286,6 +337,8 @@ func (ps *ParamSet) getParamsFromConfigFile() {
 
 	var lp = paramLineParser{ps: ps}
 	fileParser := fileparser.New("parameter config file", lp)
+	fileParser.SetProgName(ps.progBaseName)
+	ps.setConfigSyntax(fileParser)
 	for _, cf := range ps.configFiles {
 		errors := fileParser.Parse(cf.Name)
 
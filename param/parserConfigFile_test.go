@@ -63,6 +63,67 @@ func TestConfigFile(t *testing.T) {
 	}
 }
 
+func TestConfigFileQuoting(t *testing.T) {
+	testCases := []struct {
+		testName string
+		fileName string
+		psof     []param.ParamSetOptFunc
+		expVals  map[string]string
+	}{
+		{
+			testName: "literal values are unchanged",
+			fileName: "./testdata/config.literal",
+			expVals: map[string]string{
+				"re":    `"\d+"`,
+				"path":  `"C:\tmp"`,
+				"dir":   `C:\tmp\`,
+				"chars": `'a'`,
+				"text":  `it's "quoted" \n`,
+				"url":   "a",
+			},
+		},
+		{
+			testName: "quoted values with ExtendedConfigSyntax",
+			fileName: "./testdata/config.quoted",
+			psof:     []param.ParamSetOptFunc{param.ExtendedConfigSyntax},
+			expVals: map[string]string{
+				"example2": "5",
+				"re":       `\d+ // not a comment`,
+				"path":     `C:\tmp`,
+				"text":     "  line 1\nline 2",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+
+		ps, err := paramset.NewNoHelpNoExitNoErrRpt(tc.psof...)
+		if err != nil {
+			t.Fatal(testID, " : couldn't construct the ParamSet: ", err)
+		}
+		vals := map[string]*string{}
+		for _, name := range []string{"example2", "re", "path", "dir",
+			"chars", "text", "url"} {
+			vals[name] = new(string)
+			ps.Add(name, psetter.StringSetter{Value: vals[name]}, "desc")
+		}
+		ps.SetConfigFile(tc.fileName, filecheck.MustExist)
+
+		if errMap := ps.Parse([]string{}); len(errMap) != 0 {
+			t.Log(testID)
+			t.Errorf("\t: unexpected errors: %v\n", errMap)
+		}
+		for name, exp := range tc.expVals {
+			if *vals[name] != exp {
+				t.Log(testID)
+				t.Errorf("\t: %s: expected %q, got %q\n",
+					name, exp, *vals[name])
+			}
+		}
+	}
+}
+
 // CFAddParams1 will set the "example1" parameter in the ParamSet
 func CFAddParams1(ps *param.ParamSet) error {
 	ps.Add("example1",
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/strdist"
 )
 
 const stdIndent = "    "
@@ -17,6 +19,24 @@ const paramIndent = 8
 const descriptionIndent = 16
 const textIndent = 4
 
+// similarGroups returns a string describing those parameter groups with
+// names similar to the given name. It returns the empty string if there are
+// none
+func similarGroups(ps *param.ParamSet, name string) string {
+	groups := ps.Groups()
+	groupNames := make([]string, 0, len(groups))
+	for g := range groups {
+		groupNames = append(groupNames, g)
+	}
+
+	matches := strdist.CaseBlindCompositeFinder.FindNStrLike(
+		3, name, groupNames...)
+	if len(matches) == 0 {
+		return ""
+	}
+	return " Did you mean: " + strings.Join(matches, " or ") + " ?"
+}
+
 // badGroups checks that all the groups are in the ParamSet and reports the
 // error if not. It returns a count of the number of problems found
 func badGroups(ps *param.ParamSet, groups map[string]bool, name string) bool {
@@ -28,10 +48,12 @@ func badGroups(ps *param.ParamSet, groups map[string]bool, name string) bool {
 				formatPrefixedText(ps.ErrWriter(), prefix,
 					"group: '"+g+"' in the list of "+name+","+
 						" is not the name of a parameter group."+
-						" Please check the spelling.",
+						" Please check the spelling."+
+						similarGroups(ps, g),
 					0)
 			} else {
-				formatText(ps.ErrWriter(), "also: '"+g+"'",
+				formatText(ps.ErrWriter(), "also: '"+g+"'."+
+					similarGroups(ps, g),
 					len(prefix), len(prefix))
 			}
 			badGroups++
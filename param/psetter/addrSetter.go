@@ -0,0 +1,76 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"net/netip"
+)
+
+// AddrSetter allows you to specify a parameter that can be used to set a
+// netip.Addr value. You can also supply check functions that will validate
+// the Value.
+type AddrSetter struct {
+	Value  *netip.Addr
+	Checks []check.Addr
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s AddrSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s AddrSetter) Set(_ string) error {
+	return errors.New("no IP address given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to an IP address, if it cannot be parsed successfully
+// it returns an error. If there is a check and the check is violated it
+// returns an error. Only if the value is parsed successfully and the check
+// is not violated is the Value set.
+func (s AddrSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := netip.ParseAddr(paramVal)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as an IP address: %s",
+			paramVal, err)
+	}
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s AddrSetter) AllowedValues() string {
+	rval := "an IPv4 address in dotted decimal form (such as 192.0.2.1)" +
+		" or an IPv6 address (such as 2001:db8::1 or fe80::1%eth0)"
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s AddrSetter) CurrentValue() string {
+	return s.Value.String()
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s AddrSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": AddrSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -1,28 +1,46 @@
 package psetter
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nickwells/golem/strdist"
 )
 
+// maxAValsToList is the largest number of allowed values which will be
+// listed in full when an invalid value is given
+const maxAValsToList = 6
+
 // AValMap - this maps allowed values for an enumerated parameter to
 // explanatory text. It forms part of the usage documentation of the
 // program and will appear when the -help parameter is given by the
 // user.
 type AValMap map[string]string
 
+// names returns the allowed values in sorted order
+func (av AValMap) names() []string {
+	valNames := make([]string, 0, len(av))
+	for k := range av {
+		valNames = append(valNames, k)
+	}
+	sort.Strings(valNames)
+	return valNames
+}
+
 func allowedValues(av AValMap) string {
 	var avals string
-	var valNames = make([]string, 0, len(av))
 	var maxNameLen int
 
-	for k := range av {
-		valNames = append(valNames, k)
+	valNames := av.names()
+	for _, k := range valNames {
 		if len(k) > maxNameLen {
 			maxNameLen = len(k)
 		}
 	}
-	sort.Strings(valNames)
 	sep := ""
 	for _, v := range valNames {
 		avals += sep + fmt.Sprintf("%-*s: ", maxNameLen, v) + av[v]
@@ -30,3 +48,156 @@ func allowedValues(av AValMap) string {
 	}
 	return avals
 }
+
+// AValMatch records how a value given for an enumerated parameter is
+// matched against the allowed values. By default the value must be exactly
+// the same as one of the allowed values.
+type AValMatch struct {
+	// IgnoreCase, if set, allows the value to match an allowed value
+	// regardless of case
+	IgnoreCase bool
+	// AllowPrefix, if set, allows the value to be an abbreviation of an
+	// allowed value. The abbreviation must be a prefix of exactly one of
+	// the allowed values
+	AllowPrefix bool
+}
+
+// describe returns a description of any non-default matching rules. It
+// returns the empty string if there are none
+func (m AValMatch) describe() string {
+	switch {
+	case m.IgnoreCase && m.AllowPrefix:
+		return "\nCase is ignored and a value may be abbreviated" +
+			" to any unique prefix."
+	case m.IgnoreCase:
+		return "\nCase is ignored."
+	case m.AllowPrefix:
+		return "\nA value may be abbreviated to any unique prefix."
+	}
+	return ""
+}
+
+// matchAVal returns the allowed value which the value matches. It returns
+// a non-nil error if the value doesn't match any allowed value or if it
+// matches more than one.
+func (m AValMatch) matchAVal(val string, av AValMap) (string, error) {
+	return m.matchAValShowing(val, av, av, nil)
+}
+
+// matchAValShowing returns the allowed value which the value matches as
+// for matchAVal but the errors only list or suggest the values in the
+// shown map. If names is not nil it maps each allowed value to the name
+// it stands for (as an alias maps to its name); the name is returned
+// and values which stand for the same name count as a single match.
+func (m AValMatch) matchAValShowing(val string, av, shown AValMap,
+	names map[string]string) (string, error) {
+	nameOf := func(k string) string {
+		if names == nil {
+			return k
+		}
+		return names[k]
+	}
+
+	if _, ok := av[val]; ok {
+		return nameOf(val), nil
+	}
+
+	matches := map[string]bool{}
+	if m.IgnoreCase {
+		for k := range av {
+			if strings.EqualFold(k, val) {
+				matches[nameOf(k)] = true
+			}
+		}
+	}
+	if len(matches) == 0 && m.AllowPrefix && val != "" {
+		for k := range av {
+			if m.hasPrefix(k, val) {
+				matches[nameOf(k)] = true
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", badAValErr(val, shown)
+	case 1:
+		for name := range matches {
+			return name, nil
+		}
+	}
+
+	shownMatches := make([]string, 0, len(matches))
+	for name := range matches {
+		if _, ok := shown[name]; ok {
+			shownMatches = append(shownMatches, name)
+		}
+	}
+	msg := "ambiguous value: '" + val + "'"
+	if len(shownMatches) > 1 {
+		sort.Strings(shownMatches)
+		msg += ", it could be any of: " + strings.Join(shownMatches, ", ")
+	} else {
+		msg += ", it matches more than one allowed value"
+	}
+	return "", errors.New(msg)
+}
+
+// hasPrefix returns true if s starts with the prefix, ignoring case if the
+// AValMatch says so. Case is ignored by comparing the strings rune by rune
+// using Unicode simple case folding (as for strings.EqualFold) so that,
+// for instance, the long s ("ſ") matches "s" and the Kelvin sign matches
+// "k"
+func (m AValMatch) hasPrefix(s, prefix string) bool {
+	if !m.IgnoreCase {
+		return strings.HasPrefix(s, prefix)
+	}
+	for _, pr := range prefix {
+		if s == "" {
+			return false
+		}
+		sr, size := utf8.DecodeRuneInString(s)
+		if !runesEqualFold(sr, pr) {
+			return false
+		}
+		s = s[size:]
+	}
+	return true
+}
+
+// runesEqualFold returns true if the runes are equal under Unicode simple
+// case folding
+func runesEqualFold(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+// badAValErr returns an error reporting that the value is not allowed. The
+// error will suggest any allowed values similar to the given value and, if
+// there are only a few allowed values, it will list them all
+func badAValErr(val string, av AValMap) error {
+	msg := "invalid value: '" + val + "'"
+	sep := ". "
+	if alts := suggestAltVals(val, av); len(alts) > 0 {
+		msg += sep + "Did you mean: " + strings.Join(alts, " or ") + " ?"
+		sep = " "
+	}
+	if len(av) <= maxAValsToList {
+		msg += sep + "The allowed values are: " +
+			strings.Join(av.names(), ", ")
+	}
+	return errors.New(msg)
+}
+
+// suggestAltVals returns those allowed values which are similar to the given
+// value
+func suggestAltVals(val string, av AValMap) []string {
+	return strdist.CaseBlindCompositeFinder.FindNStrLike(3, val, av.names()...)
+}
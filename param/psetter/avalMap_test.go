@@ -1,6 +1,9 @@
 package psetter
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 func TestAValMap(t *testing.T) {
 	testCases := []struct {
@@ -41,3 +44,72 @@ name     : desc`,
 	}
 
 }
+
+func TestAValMatchHasPrefix(t *testing.T) {
+	testCases := []struct {
+		name   string
+		m      AValMatch
+		s      string
+		prefix string
+		exp    bool
+	}{
+		{name: "exact", s: "verbose", prefix: "verb", exp: true},
+		{name: "exact - case differs", s: "verbose", prefix: "VERB"},
+		{
+			name:   "ignore case",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "verbose",
+			prefix: "VERB",
+			exp:    true,
+		},
+		{
+			name:   "ignore case - prefix too long",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "verb",
+			prefix: "VERBOSE",
+		},
+		{
+			name:   "ignore case - multi-byte runes",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "größe",
+			prefix: "GRÖ",
+			exp:    true,
+		},
+		{
+			name:   "ignore case - prefix ends within a rune",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "ö",
+			prefix: "o",
+		},
+		{
+			name:   "ignore case - long s",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "ſize",
+			prefix: "SI",
+			exp:    true,
+		},
+		{
+			name:   "ignore case - Kelvin sign",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "kelvin",
+			prefix: "\u212a",
+			exp:    true,
+		},
+		{
+			name:   "ignore case - different byte lengths",
+			m:      AValMatch{IgnoreCase: true},
+			s:      "\u212aelvin",
+			prefix: "ke",
+			exp:    true,
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		if got := tc.m.hasPrefix(tc.s, tc.prefix); got != tc.exp {
+			t.Log(testID)
+			t.Errorf("\t: hasPrefix(%q, %q) should be %t",
+				tc.s, tc.prefix, tc.exp)
+		}
+	}
+}
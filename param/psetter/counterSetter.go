@@ -0,0 +1,84 @@
+package psetter
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/param"
+	"strconv"
+)
+
+// CounterSetter is used to count the number of times a parameter is
+// given. This is suitable for verbosity flags where, for instance,
+// "-v -v -v" gives a more verbose output than "-v". Each use of the
+// parameter without a value adds one to the Value. A value can be given to
+// set the count explicitly, for instance "-v=0" to turn verbosity off.
+//
+// If Max is greater than zero then the count will not go above Max; any
+// further uses of the parameter leave the Value at Max. An explicit value
+// greater than Max is an error.
+type CounterSetter struct {
+	Value *int64
+	Max   int64
+}
+
+// ValueReq returns param.Optional indicating that the parameter may have
+// but need not have a following value
+func (s CounterSetter) ValueReq() param.ValueReq { return param.Optional }
+
+// Set (called when there is no following value) adds one to the Value
+// unless it is already at the Max
+func (s CounterSetter) Set(_ string) error {
+	if s.Max > 0 && *s.Value >= s.Max {
+		*s.Value = s.Max
+		return nil
+	}
+	*s.Value++
+	return nil
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to a whole number which is not negative and is not
+// greater than the Max (if there is one). Only if the value is valid is the
+// Value set.
+func (s CounterSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := strconv.ParseInt(paramVal, 0, 0)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a count: %s",
+			paramVal, err)
+	}
+	if v < 0 {
+		return fmt.Errorf("the count (%d) must not be negative", v)
+	}
+	if s.Max > 0 && v > s.Max {
+		return fmt.Errorf("the count (%d) must not be greater than %d",
+			v, s.Max)
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s CounterSetter) AllowedValues() string {
+	rval := "none (which will add one to the count)" +
+		" or a whole number giving the count"
+	if s.Max > 0 {
+		rval += fmt.Sprintf(". The count may not be greater than %d", s.Max)
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s CounterSetter) CurrentValue() string {
+	return fmt.Sprintf("%v", *s.Value)
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or the Max is negative.
+func (s CounterSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": CounterSetter Check failed: the Value to be set is nil")
+	}
+	if s.Max < 0 {
+		panic(name + ": CounterSetter Check failed: the Max is negative")
+	}
+}
@@ -0,0 +1,191 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+)
+
+// setCall describes a call to a setter: if hasVal is false Set is called,
+// otherwise SetWithVal is called with the val
+type setCall struct {
+	hasVal bool
+	val    string
+}
+
+// applySetCalls makes the calls to the setter in order, returning the
+// first error
+func applySetCalls(s param.Setter, calls []setCall) error {
+	for _, c := range calls {
+		var err error
+		if c.hasVal {
+			err = s.SetWithVal("", c.val)
+		} else {
+			err = s.Set("")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// noVal is a call to Set with no following value
+var noVal = setCall{}
+
+// withVal returns a call to SetWithVal with the given value
+func withVal(v string) setCall {
+	return setCall{hasVal: true, val: v}
+}
+
+func TestCounterSetter(t *testing.T) {
+	testCases := []struct {
+		testName       string
+		max            int64
+		calls          []setCall
+		expVal         int64
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			testName: "counts",
+			calls:    []setCall{noVal, noVal, noVal},
+			expVal:   3,
+		},
+		{
+			testName: "stops at the max",
+			max:      2,
+			calls:    []setCall{noVal, noVal, noVal, noVal},
+			expVal:   2,
+		},
+		{
+			testName: "explicit value then count",
+			calls:    []setCall{withVal("5"), noVal},
+			expVal:   6,
+		},
+		{
+			testName: "explicit zero",
+			calls:    []setCall{noVal, withVal("0")},
+			expVal:   0,
+		},
+		{
+			testName:       "negative",
+			calls:          []setCall{withVal("-1")},
+			errExpected:    true,
+			errMustContain: []string{"must not be negative"},
+		},
+		{
+			testName:       "greater than the max",
+			max:            2,
+			calls:          []setCall{withVal("3")},
+			errExpected:    true,
+			errMustContain: []string{"must not be greater than 2"},
+		},
+		{
+			testName:       "not a number",
+			calls:          []setCall{withVal("lots")},
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'lots' as a count"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		var v int64
+		s := psetter.CounterSetter{Value: &v, Max: tc.max}
+		err := applySetCalls(s, tc.calls)
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: unexpected error: %s", err)
+				continue
+			}
+			testhelper.ShouldContain(t, testID, "error", err.Error(),
+				tc.errMustContain)
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+		} else if v != tc.expVal {
+			t.Log(testID)
+			t.Errorf("\t: the count should be %d but is %d", tc.expVal, v)
+		}
+	}
+}
+
+func TestOptInt64Setter(t *testing.T) {
+	var v int64
+	s := psetter.OptInt64Setter{
+		Value:  &v,
+		Dflt:   2,
+		Checks: []check.Int64{nil, check.Int64LT(10)},
+	}
+	if s.ValueReq() != param.Optional {
+		t.Errorf("the value should be optional")
+	}
+
+	if err := s.Set(""); err != nil {
+		t.Error("unexpected error: ", err)
+	} else if v != 2 {
+		t.Errorf("with no value the Value should be the default (2), not %d",
+			v)
+	}
+
+	if err := s.SetWithVal("", "0x7"); err != nil {
+		t.Error("unexpected error: ", err)
+	} else if v != 7 {
+		t.Errorf("the Value should be 7, not %d", v)
+	}
+
+	err := s.SetWithVal("", "10")
+	if err == nil {
+		t.Errorf("a value failing the checks should give an error")
+	} else if v != 7 {
+		t.Errorf("the Value should not change on error but is %d", v)
+	}
+
+	s.Dflt = 11
+	if err := s.Set(""); err == nil {
+		t.Errorf("the default should also be checked")
+	}
+}
+
+func TestTriBoolSetter(t *testing.T) {
+	var tb psetter.TriBool
+	s := psetter.TriBoolSetter{Value: &tb}
+
+	if tb.IsSet() || s.CurrentValue() != "unset" {
+		t.Errorf("the zero TriBool should be unset, not %q", s.CurrentValue())
+	}
+	if !tb.ValueOr(true) {
+		t.Errorf("an unset TriBool should give the default value")
+	}
+
+	if err := s.SetWithVal("", "false"); err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	if v, isSet := tb.Value(); v || !isSet {
+		t.Errorf("the value should be set to false, got: %v, %v", v, isSet)
+	}
+	if tb.ValueOr(true) {
+		t.Errorf("a TriBool set to false should not give the default value")
+	}
+
+	if err := s.Set(""); err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	if s.CurrentValue() != "true" {
+		t.Errorf("the value should be true, not %q", s.CurrentValue())
+	}
+
+	if err := s.SetWithVal("", "maybe"); err == nil {
+		t.Errorf("a bad value should give an error")
+	}
+
+	tb.Unset()
+	if tb.IsSet() {
+		t.Errorf("the value should be unset after Unset")
+	}
+}
@@ -0,0 +1,116 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"strings"
+	"time"
+)
+
+// DateRangeSep is the string separating the start and end of a date range
+const DateRangeSep = ".."
+
+// DateRangeSetter allows you to specify a parameter that can be used to set
+// a pair of time.Time values giving the Start and End of a range. The value
+// is given as "start..end" where each of start and end can be any value
+// accepted by a TimeSetter with the same Layouts, Loc and Now, for instance
+// "2019-03-01..today" or "now-2h..now". Note that a date without a time
+// gives the start of that day. The start must not be after the end.
+//
+// You can also supply check functions that will validate both the Start
+// and the End values.
+type DateRangeSetter struct {
+	Start   *time.Time
+	End     *time.Time
+	Layouts []string
+	Loc     *time.Location
+	Checks  []check.Time
+	// Now gives the current time used for relative times. If it is nil
+	// then time.Now is used
+	Now func() time.Time
+}
+
+// parser returns the timeParser for the setter
+func (s DateRangeSetter) parser() timeParser {
+	return newTimeParser(s.Layouts, s.Loc, s.Now)
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s DateRangeSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s DateRangeSetter) Set(_ string) error {
+	return errors.New("no date range given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) splits the value
+// into the start and end times and checks that each can be parsed to a
+// time, if either cannot be parsed successfully it returns an error. If the
+// start is after the end or if there is a check and the check is violated
+// by either time it returns an error. Only if both times are parsed
+// successfully and the checks are not violated are the Start and End set.
+func (s DateRangeSetter) SetWithVal(_ string, paramVal string) error {
+	parts := strings.SplitN(paramVal, DateRangeSep, 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("the date range (%s) must be of the form"+
+			" start%send", paramVal, DateRangeSep)
+	}
+
+	tp := s.parser()
+	start, err := tp.parse(parts[0])
+	if err != nil {
+		return fmt.Errorf("bad start of the date range: %s", err)
+	}
+	end, err := tp.parse(parts[1])
+	if err != nil {
+		return fmt.Errorf("bad end of the date range: %s", err)
+	}
+	if start.After(end) {
+		return fmt.Errorf("the start of the date range (%s)"+
+			" is after the end (%s)", parts[0], parts[1])
+	}
+
+	if err := checkTime(start, s.Checks); err != nil {
+		return fmt.Errorf("bad start of the date range: %s", err)
+	}
+	if err := checkTime(end, s.Checks); err != nil {
+		return fmt.Errorf("bad end of the date range: %s", err)
+	}
+
+	*s.Start = start
+	*s.End = end
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s DateRangeSetter) AllowedValues() string {
+	rval := "two times separated by '" + DateRangeSep + "'" +
+		", the first not after the second. Each time is " +
+		s.parser().describe()
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s DateRangeSetter) CurrentValue() string {
+	layout := s.parser().layouts[0]
+	return s.Start.Format(layout) + DateRangeSep + s.End.Format(layout)
+}
+
+// CheckSetter panics if the setter has not been properly created - if
+// either the Start or the End is nil
+func (s DateRangeSetter) CheckSetter(name string) {
+	if s.Start == nil {
+		panic(name +
+			": DateRangeSetter Check failed: the Start value to be set is nil")
+	}
+	if s.End == nil {
+		panic(name +
+			": DateRangeSetter Check failed: the End value to be set is nil")
+	}
+}
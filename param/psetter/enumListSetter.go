@@ -9,11 +9,19 @@ import (
 )
 
 // EnumListSetter sets the values in a slice of strings. The values must be in
-// the allowed values map
+// the allowed values map. The AValMatch can be set to allow the case of the
+// values to be ignored or to allow values to be abbreviated.
+//
+// The Append field of the embedded ListEdit controls whether the values
+// replace the list or are added to it. If its EditPrefixes field is set
+// the value may also be given an edit prefix to add values to the list or
+// to remove them from it - see ListEdit for details.
 type EnumListSetter struct {
 	Value       *[]string
 	AllowedVals AValMap // map[allowedValue] => description
+	AValMatch
 	StrListSeparator
+	ListEdit
 	Checks []check.StringSlice
 }
 
@@ -28,16 +36,29 @@ func (s EnumListSetter) Set(_ string) error {
 
 // SetWithVal (called when a value follows the parameter) splits the value
 // using the list separator. It then checks all the values for validity and
-// only if all the values are in the allowed values list does it add them
-// to the slice of strings pointed to by the Value. It returns a error for
-// the first invalid value or if a check is breached.
+// only if all the values match one of the allowed values does it edit
+// the slice of strings pointed to by the Value. It returns a error for the
+// first invalid value, if a value to be removed is not in the list or if a
+// check is breached by the resulting list.
 func (s EnumListSetter) SetWithVal(_ string, paramVal string) error {
-	sep := s.GetSeparator()
-	values := strings.Split(paramVal, sep)
-	for _, v := range values {
-		if _, ok := s.AllowedVals[v]; !ok {
-			return errors.New("invalid value: '" + v + "'")
+	op, paramVal, hasVals, err := s.editOp(paramVal)
+	if err != nil {
+		return err
+	}
+	values := []string{}
+	if hasVals {
+		values = strings.Split(paramVal, s.GetSeparator())
+	}
+	for i, v := range values {
+		av, err := s.matchAVal(v, s.AllowedVals)
+		if err != nil {
+			return err
 		}
+		values[i] = av
+	}
+	values, err = applyListEdit(op, *s.Value, values)
+	if err != nil {
+		return err
 	}
 
 	if len(s.Checks) != 0 {
@@ -61,7 +82,7 @@ func (s EnumListSetter) SetWithVal(_ string, paramVal string) error {
 func (s EnumListSetter) AllowedValues() string {
 	return "a list of string values separated by '" + s.GetSeparator() +
 		"'. The values must be from the following:\n" +
-		allowedValues(s.AllowedVals)
+		allowedValues(s.AllowedVals) + s.describe() + s.describeListEdit(s.EditPrefixes)
 }
 
 // CurrentValue returns the current setting of the parameter value
@@ -4,15 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"github.com/nickwells/golem/param"
+	"sort"
 	"strings"
 )
 
 // EnumMapSetter sets the entry in a map of strings. The values must be in
-// the allowed values map
+// the allowed values map. The AValMatch can be set to allow the case of the
+// values to be ignored or to allow values to be abbreviated.
+//
+// As for the MapSetter, if EditPrefixes is set the value may be given an
+// edit prefix to add keys, to remove them or to replace the map. The map
+// is edited in place.
 type EnumMapSetter struct {
 	Value       *map[string]bool
 	AllowedVals AValMap // map[allowedValue] => description
+	AValMatch
 	StrListSeparator
+	EditPrefixes bool
 }
 
 // ValueReq returns param.Mandatory indicating that some value must follow
@@ -26,35 +34,48 @@ func (s EnumMapSetter) Set(_ string) error {
 
 // SetWithVal (called when a value follows the parameter) splits the value
 // using the list separator. It then checks all the values for validity and
-// only if all the values are in the allowed values list does it set the entry
+// only if all the values match one of the allowed values does it set the entry
 // in the map of strings pointed to by the Value. It returns a error for the
-// first invalid value.
+// first invalid value or if a key to be removed is not in the map.
 func (s EnumMapSetter) SetWithVal(_ string, paramVal string) error {
-	sep := s.GetSeparator()
-	values := strings.Split(paramVal, sep)
-	for _, v := range values {
-		if _, ok := s.AllowedVals[v]; !ok {
-			return errors.New("invalid value: '" + v)
-		}
+	op, paramVal, hasVals, err := mapEditOp(paramVal, s.EditPrefixes)
+	if err != nil {
+		return err
+	}
+	values := []string{}
+	if hasVals {
+		values = strings.Split(paramVal, s.GetSeparator())
 	}
-	for _, v := range values {
-		(*s.Value)[v] = true
+	for i, v := range values {
+		av, err := s.matchAVal(v, s.AllowedVals)
+		if err != nil {
+			return err
+		}
+		values[i] = av
 	}
-	return nil
+	return applyMapEdit(op, *s.Value, values)
 }
 
 // AllowedValues returns a string listing the allowed values
 func (s EnumMapSetter) AllowedValues() string {
 	return "a list of string values separated by '" + s.GetSeparator() +
 		"'. The values must be from the following:\n" +
-		allowedValues(s.AllowedVals)
+		allowedValues(s.AllowedVals) + s.describe() + describeMapEdit(s.EditPrefixes)
 }
 
-// CurrentValue returns the current setting of the parameter value
+// CurrentValue returns the current setting of the parameter value. The
+// entries are shown in key order
 func (s EnumMapSetter) CurrentValue() string {
+	keys := make([]string, 0, len(*s.Value))
+	for k := range *s.Value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	cv := ""
 	sep := ""
-	for k, v := range *s.Value {
+	for _, k := range keys {
+		v := (*s.Value)[k]
 		cv += sep + fmt.Sprintf("%s=%v", k, v)
 		sep = s.GetSeparator()
 	}
@@ -62,6 +83,10 @@ func (s EnumMapSetter) CurrentValue() string {
 	return cv
 }
 
+// EditsList returns true if the setter accepts edit prefixes, in which
+// case it is a param.ListEditor
+func (s EnumMapSetter) EditsList() bool { return s.EditPrefixes }
+
 // CheckSetter panics if the setter has not been properly created - if the
 // Value is nil or the map has not been created yet or if there are no
 // allowed values.
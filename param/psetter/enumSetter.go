@@ -8,10 +8,13 @@ import (
 
 // EnumSetter allows you to specify a parameter that will only allow an
 // enumerated range of values which are specified in the AllowedVals map
-// which maps each allowed value to a description
+// which maps each allowed value to a description. The AValMatch can be set
+// to allow the case of the value to be ignored or to allow a value to be
+// abbreviated.
 type EnumSetter struct {
 	Value       *string
 	AllowedVals AValMap // map[allowedValue] => description
+	AValMatch
 }
 
 // ValueReq returns param.Mandatory indicating that some value must follow
@@ -24,19 +27,21 @@ func (s EnumSetter) Set(_ string) error {
 }
 
 // SetWithVal (called when a value follows the parameter) checks the value
-// for validity and only if it is in the allowed values list does it set the
-// Value. It returns an error if the value is invalid.
+// for validity and only if it matches one of the allowed values does it set
+// the Value to that allowed value. It returns an error if the value is
+// invalid; the error will suggest any similar allowed values.
 func (s EnumSetter) SetWithVal(_ string, paramVal string) error {
-	if _, ok := s.AllowedVals[paramVal]; ok {
-		*s.Value = paramVal
-		return nil
+	v, err := s.matchAVal(paramVal, s.AllowedVals)
+	if err != nil {
+		return err
 	}
-	return errors.New("invalid value: '" + paramVal)
+	*s.Value = v
+	return nil
 }
 
 // AllowedValues returns a string listing the allowed values
 func (s EnumSetter) AllowedValues() string {
-	return "one of\n" + allowedValues(s.AllowedVals)
+	return "one of\n" + allowedValues(s.AllowedVals) + s.describe()
 }
 
 // CurrentValue returns the current setting of the parameter value
@@ -1,6 +1,7 @@
 package psetter_test
 
 import (
+	"fmt"
 	"github.com/nickwells/golem/param"
 	"github.com/nickwells/golem/param/psetter"
 	"testing"
@@ -194,3 +195,249 @@ func TestEnumMapSetter(t *testing.T) {
 		}
 	}
 }
+
+func TestEnumSetterSuggestions(t *testing.T) {
+	var value string
+	es := psetter.EnumSetter{
+		Value: &value,
+		AllowedVals: psetter.AValMap{
+			"verbose": "show more",
+			"version": "show the version",
+			"quiet":   "show less",
+		}}
+
+	testCases := []struct {
+		testName  string
+		val       string
+		expErrStr string
+	}{
+		{
+			testName: "prefix",
+			val:      "verb",
+			expErrStr: "invalid value: 'verb'. Did you mean: verbose ?" +
+				" The allowed values are: quiet, verbose, version",
+		},
+		{
+			testName: "short value",
+			val:      "ver",
+			expErrStr: "invalid value: 'ver'. Did you mean: verbose or version ?" +
+				" The allowed values are: quiet, verbose, version",
+		},
+		{
+			testName: "typo",
+			val:      "quite",
+			expErrStr: "invalid value: 'quite'. Did you mean: quiet ?" +
+				" The allowed values are: quiet, verbose, version",
+		},
+		{
+			testName:  "no similar value",
+			val:       "xyzzy",
+			expErrStr: "invalid value: 'xyzzy'." + " The allowed values are: quiet, verbose, version",
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		err := es.SetWithVal("", tc.val)
+		if err == nil {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+		} else if err.Error() != tc.expErrStr {
+			t.Log(testID)
+			t.Logf("\t: expected: %s", tc.expErrStr)
+			t.Logf("\t:      got: %s", err)
+			t.Errorf("\t: unexpected error")
+		}
+	}
+}
+
+func TestEnumSetterManyAVals(t *testing.T) {
+	var value string
+	es := psetter.EnumSetter{
+		Value: &value,
+		AllowedVals: psetter.AValMap{
+			"red":    "the colour red",
+			"orange": "the colour orange",
+			"yellow": "the colour yellow",
+			"green":  "the colour green",
+			"blue":   "the colour blue",
+			"indigo": "the colour indigo",
+			"violet": "the colour violet",
+		}}
+
+	testCases := []struct {
+		testName  string
+		val       string
+		expErrStr string
+	}{
+		{
+			testName:  "typo",
+			val:       "gren",
+			expErrStr: "invalid value: 'gren'. Did you mean: green ?",
+		},
+		{
+			testName:  "no similar value",
+			val:       "xyzzy",
+			expErrStr: "invalid value: 'xyzzy'",
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		err := es.SetWithVal("", tc.val)
+		if err == nil {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+		} else if err.Error() != tc.expErrStr {
+			t.Log(testID)
+			t.Logf("\t: expected: %s", tc.expErrStr)
+			t.Logf("\t:      got: %s", err)
+			t.Errorf("\t: unexpected error")
+		}
+	}
+}
+
+func TestEnumSetterMatching(t *testing.T) {
+	avals := psetter.AValMap{
+		"verbose": "show more",
+		"version": "show the version",
+		"quiet":   "show less",
+	}
+
+	testCases := []struct {
+		testName    string
+		avm         psetter.AValMatch
+		val         string
+		expectedVal string
+		expErrStr   string
+	}{
+		{
+			testName:    "exact match",
+			val:         "quiet",
+			expectedVal: "quiet",
+		},
+		{
+			testName: "wrong case",
+			val:      "QUIET",
+			expErrStr: "invalid value: 'QUIET'. Did you mean: quiet ?" +
+				" The allowed values are: quiet, verbose, version",
+		},
+		{
+			testName:    "wrong case, IgnoreCase",
+			avm:         psetter.AValMatch{IgnoreCase: true},
+			val:         "QUIET",
+			expectedVal: "quiet",
+		},
+		{
+			testName:    "unique prefix, AllowPrefix",
+			avm:         psetter.AValMatch{AllowPrefix: true},
+			val:         "verb",
+			expectedVal: "verbose",
+		},
+		{
+			testName:  "ambiguous prefix, AllowPrefix",
+			avm:       psetter.AValMatch{AllowPrefix: true},
+			val:       "ver",
+			expErrStr: "ambiguous value: 'ver', it could be any of: verbose, version",
+		},
+		{
+			testName: "prefix in wrong case, AllowPrefix",
+			avm:      psetter.AValMatch{AllowPrefix: true},
+			val:      "Q",
+			expErrStr: "invalid value: 'Q'. Did you mean: quiet ?" +
+				" The allowed values are: quiet, verbose, version",
+		},
+		{
+			testName: "prefix in wrong case, IgnoreCase and AllowPrefix",
+			avm: psetter.AValMatch{
+				IgnoreCase:  true,
+				AllowPrefix: true,
+			},
+			val:         "Q",
+			expectedVal: "quiet",
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		value := ""
+		es := psetter.EnumSetter{
+			Value:       &value,
+			AllowedVals: avals,
+			AValMatch:   tc.avm,
+		}
+		err := es.SetWithVal("", tc.val)
+		if err != nil {
+			if tc.expErrStr == "" {
+				t.Log(testID)
+				t.Errorf("\t: unexpected error: %s", err)
+			} else if err.Error() != tc.expErrStr {
+				t.Log(testID)
+				t.Logf("\t: expected: %s", tc.expErrStr)
+				t.Logf("\t:      got: %s", err)
+				t.Errorf("\t: unexpected error")
+			}
+			continue
+		}
+		if tc.expErrStr != "" {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+		}
+		if value != tc.expectedVal {
+			t.Log(testID)
+			t.Logf("\t: expected: %s", tc.expectedVal)
+			t.Logf("\t:      got: %s", value)
+			t.Errorf("\t: unexpected value")
+		}
+	}
+}
+
+func TestEnumListAndMapSetterMatching(t *testing.T) {
+	avals := psetter.AValMap{
+		"verbose": "show more",
+		"version": "show the version",
+		"quiet":   "show less",
+	}
+	avm := psetter.AValMatch{IgnoreCase: true, AllowPrefix: true}
+
+	var listVal []string
+	els := psetter.EnumListSetter{
+		Value:       &listVal,
+		AllowedVals: avals,
+		AValMatch:   avm,
+	}
+	if err := els.SetWithVal("", "Q,verbo"); err != nil {
+		t.Errorf("EnumListSetter: unexpected error: %s", err)
+	} else if len(listVal) != 2 ||
+		listVal[0] != "quiet" || listVal[1] != "verbose" {
+		t.Errorf("EnumListSetter: unexpected value: %v", listVal)
+	}
+	expErrStr := "invalid value: 'quite'. Did you mean: quiet ?" +
+		" The allowed values are: quiet, verbose, version"
+	if err := els.SetWithVal("", "verbose,quite"); err == nil {
+		t.Errorf("EnumListSetter: an error was expected but none was returned")
+	} else if err.Error() != expErrStr {
+		t.Logf("EnumListSetter: expected: %s", expErrStr)
+		t.Logf("EnumListSetter:      got: %s", err)
+		t.Errorf("EnumListSetter: unexpected error")
+	}
+
+	mapVal := map[string]bool{}
+	ems := psetter.EnumMapSetter{
+		Value:       &mapVal,
+		AllowedVals: avals,
+		AValMatch:   avm,
+	}
+	if err := ems.SetWithVal("", "VERSION"); err != nil {
+		t.Errorf("EnumMapSetter: unexpected error: %s", err)
+	} else if len(mapVal) != 1 || !mapVal["version"] {
+		t.Errorf("EnumMapSetter: unexpected value: %v", mapVal)
+	}
+	if err := ems.SetWithVal("", "quite"); err == nil {
+		t.Errorf("EnumMapSetter: an error was expected but none was returned")
+	} else if err.Error() != expErrStr {
+		t.Logf("EnumMapSetter: expected: %s", expErrStr)
+		t.Logf("EnumMapSetter:      got: %s", err)
+		t.Errorf("EnumMapSetter: unexpected error")
+	}
+}
@@ -0,0 +1,103 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/filecheck"
+	"github.com/nickwells/golem/fileparser"
+	"github.com/nickwells/golem/param"
+	"io"
+	"os"
+)
+
+// FileContentSetter allows you to specify a parameter giving a file whose
+// contents are read into the Value, either a []byte or a string. If the
+// name is StdStreamName ("-") the standard input is read. Before the file
+// is read it is checked against the Expectation.
+//
+// If MaxSize is greater than zero then a file larger than MaxSize bytes is
+// an error and the Value is not set.
+type FileContentSetter[T []byte | string] struct {
+	Value       *T
+	Expectation filecheck.ExpectedStatus
+	MaxSize     int64
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s FileContentSetter[T]) ValueReq() param.ValueReq {
+	return param.Mandatory
+}
+
+// Set (called when there is no following value) returns an error
+func (s FileContentSetter[T]) Set(_ string) error {
+	return errors.New("no pathname given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks the named
+// file against the Expectation and reads it. Only if the whole file is
+// read successfully and it is not too large is the Value set.
+func (s FileContentSetter[T]) SetWithVal(_ string, paramVal string) error {
+	var r io.Reader = os.Stdin
+	if paramVal != StdStreamName {
+		pathname, err := fileparser.FixFileName(paramVal)
+		if err != nil {
+			return err
+		}
+		err = s.Expectation.StatusCheck(pathname)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(pathname)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if s.MaxSize > 0 {
+		r = io.LimitReader(r, s.MaxSize+1)
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("cannot read '%s': %s", paramVal, err)
+	}
+	if s.MaxSize > 0 && int64(len(content)) > s.MaxSize {
+		return fmt.Errorf("'%s' is too large: it must not be more than %s",
+			paramVal, formatWithUnits(float64(s.MaxSize), SizeUnits))
+	}
+
+	*s.Value = T(content)
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s FileContentSetter[T]) AllowedValues() string {
+	rval := "a pathname or '" + StdStreamName + "' for the standard input"
+
+	extras := s.Expectation.String()
+	if extras != "" {
+		rval += ". " + extras
+	}
+	if s.MaxSize > 0 {
+		rval += ". The file must not be more than " +
+			formatWithUnits(float64(s.MaxSize), SizeUnits)
+	}
+
+	return rval
+}
+
+// CurrentValue returns the size of the content
+func (s FileContentSetter[T]) CurrentValue() string {
+	return fmt.Sprintf("%d bytes", len(*s.Value))
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil.
+func (s FileContentSetter[T]) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name +
+			": FileContentSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,324 @@
+package psetter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/nickwells/golem/filecheck"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFile creates the named file in the directory with the given
+// content and returns the full pathname
+func writeTestFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+
+	pathname := filepath.Join(dir, name)
+	if err := os.WriteFile(pathname, content, 0644); err != nil {
+		t.Fatal("cannot create the test file: ", err)
+	}
+	return pathname
+}
+
+// gzipped returns the content compressed with gzip
+func gzipped(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal("cannot compress the content: ", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("cannot compress the content: ", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReaderSetter(t *testing.T) {
+	dir := t.TempDir()
+	plain := writeTestFile(t, dir, "plain", []byte("plain text"))
+	zipped := writeTestFile(t, dir, "zipped", gzipped(t, "zipped text"))
+
+	var rc io.ReadCloser
+	s := psetter.ReaderSetter{
+		Value:       &rc,
+		Expectation: filecheck.ExpectedStatus{Existence: filecheck.MustExist},
+		DetectGzip:  true,
+	}
+	if cv := s.CurrentValue(); cv != "none" {
+		t.Errorf("the initial value should be 'none', not %q", cv)
+	}
+
+	for _, tc := range []struct {
+		pathname string
+		expVal   string
+	}{
+		{pathname: plain, expVal: "plain text"},
+		{pathname: zipped, expVal: "zipped text"},
+	} {
+		prev := rc
+		if err := s.SetWithVal("", tc.pathname); err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+		if prev != nil {
+			if _, err := prev.Read(make([]byte, 1)); err == nil {
+				t.Errorf("the previous reader should have been closed")
+			}
+		}
+		if cv := s.CurrentValue(); cv != tc.pathname {
+			t.Errorf("the current value should be %q, not %q",
+				tc.pathname, cv)
+		}
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			t.Error("unexpected error reading: ", err)
+		} else if string(content) != tc.expVal {
+			t.Errorf("the content should be %q, not %q",
+				tc.expVal, string(content))
+		}
+	}
+
+	err := s.SetWithVal("", filepath.Join(dir, "nonesuch"))
+	if err == nil {
+		t.Errorf("a missing file should give an error")
+	} else {
+		testhelper.ShouldContain(t, "missing file", "error", err.Error(),
+			[]string{"does not exist"})
+	}
+
+	if err := s.Cleanup(); err != nil {
+		t.Error("unexpected error from Cleanup: ", err)
+	}
+	if rc != nil {
+		t.Errorf("the Value should be nil after Cleanup")
+	}
+}
+
+func TestWriterSetter(t *testing.T) {
+	dir := t.TempDir()
+	pathname := writeTestFile(t, dir, "out", []byte("old,"))
+
+	testCases := []struct {
+		testName       string
+		mode           psetter.WriteMode
+		expVal         string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			testName: "append",
+			mode:     psetter.WriteAppend,
+			expVal:   "old,new",
+		},
+		{
+			testName: "truncate",
+			mode:     psetter.WriteTruncate,
+			expVal:   "new",
+		},
+		{
+			testName:       "create - exists",
+			mode:           psetter.WriteCreate,
+			errExpected:    true,
+			errMustContain: []string{"file exists"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		if err := os.WriteFile(pathname, []byte("old,"), 0644); err != nil {
+			t.Fatal("cannot reset the test file: ", err)
+		}
+
+		var wc io.WriteCloser
+		s := psetter.WriterSetter{Value: &wc, Mode: tc.mode}
+		err := s.SetWithVal("", pathname)
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: unexpected error: %s", err)
+				continue
+			}
+			testhelper.ShouldContain(t, testID, "error", err.Error(),
+				tc.errMustContain)
+			continue
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+			s.Cleanup()
+			continue
+		}
+
+		if _, err := io.WriteString(wc, "new"); err != nil {
+			t.Error("unexpected error writing: ", err)
+		}
+		if err := s.Cleanup(); err != nil {
+			t.Error("unexpected error from Cleanup: ", err)
+		}
+		content, err := os.ReadFile(pathname)
+		if err != nil {
+			t.Error("unexpected error reading: ", err)
+		} else if string(content) != tc.expVal {
+			t.Log(testID)
+			t.Errorf("\t: the file should hold %q, not %q",
+				tc.expVal, string(content))
+		}
+	}
+
+	var wc io.WriteCloser
+	s := psetter.WriterSetter{Value: &wc, Mode: psetter.WriteCreate}
+	newPath := filepath.Join(dir, "new")
+	if err := s.SetWithVal("", newPath); err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		t.Errorf("the file should not be created until it is written to")
+	}
+	if err := s.SetWithVal("", psetter.StdStreamName); err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	if cv := s.CurrentValue(); cv != os.Stdout.Name() {
+		t.Errorf("the current value should be the standard output, not %q",
+			cv)
+	}
+	if err := s.Cleanup(); err != nil {
+		t.Error("unexpected error from Cleanup: ", err)
+	}
+}
+
+func TestWriterSetterBadPath(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTestFile(t, dir, "file", []byte("x"))
+
+	testCases := []struct {
+		testName       string
+		pathname       string
+		mode           psetter.WriteMode
+		errMustContain []string
+	}{
+		{
+			testName:       "no such directory",
+			pathname:       filepath.Join(dir, "nosuchdir", "out"),
+			errMustContain: []string{"cannot create", "no such file"},
+		},
+		{
+			testName:       "parent is not a directory",
+			pathname:       filepath.Join(file, "out"),
+			errMustContain: []string{"not a directory"},
+		},
+		{
+			testName:       "is a directory - truncate",
+			pathname:       dir,
+			errMustContain: []string{"is a directory"},
+		},
+		{
+			testName:       "is a directory - append",
+			pathname:       dir,
+			mode:           psetter.WriteAppend,
+			errMustContain: []string{"is a directory"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		var wc io.WriteCloser
+		s := psetter.WriterSetter{Value: &wc, Mode: tc.mode}
+		err := s.SetWithVal("", tc.pathname)
+		if err == nil {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+			s.Cleanup()
+			continue
+		}
+		testhelper.ShouldContain(t, testID, "error", err.Error(),
+			tc.errMustContain)
+		if wc != nil {
+			t.Log(testID)
+			t.Errorf("\t: the Value should not be set after an error")
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal("cannot read the test directory: ", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("the writability check should leave no files behind,"+
+			" found %d entries", len(entries))
+	}
+}
+
+func TestWriterSetterOverride(t *testing.T) {
+	dir := t.TempDir()
+	first := writeTestFile(t, dir, "first", []byte("keep"))
+	second := filepath.Join(dir, "second")
+
+	var wc io.WriteCloser
+	s := psetter.WriterSetter{Value: &wc}
+	if err := s.SetWithVal("", first); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if err := s.SetWithVal("", second); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if _, err := io.WriteString(wc, "new"); err != nil {
+		t.Error("unexpected error writing: ", err)
+	}
+	if err := s.Cleanup(); err != nil {
+		t.Error("unexpected error from Cleanup: ", err)
+	}
+
+	for pathname, expVal := range map[string]string{
+		first:  "keep",
+		second: "new",
+	} {
+		content, err := os.ReadFile(pathname)
+		if err != nil {
+			t.Error("unexpected error reading: ", err)
+		} else if string(content) != expVal {
+			t.Errorf("%s should hold %q, not %q", pathname, expVal, content)
+		}
+	}
+}
+
+func TestFileContentSetter(t *testing.T) {
+	dir := t.TempDir()
+	pathname := writeTestFile(t, dir, "content", []byte("0123456789"))
+
+	var b []byte
+	bs := psetter.FileContentSetter[[]byte]{Value: &b}
+	if err := bs.SetWithVal("", pathname); err != nil {
+		t.Error("unexpected error: ", err)
+	} else if string(b) != "0123456789" {
+		t.Errorf("the content should be %q, not %q", "0123456789", string(b))
+	}
+	if cv := bs.CurrentValue(); cv != "10 bytes" {
+		t.Errorf("the current value should be %q, not %q", "10 bytes", cv)
+	}
+
+	var str string
+	ss := psetter.FileContentSetter[string]{Value: &str, MaxSize: 10}
+	if err := ss.SetWithVal("", pathname); err != nil {
+		t.Error("unexpected error: ", err)
+	} else if str != "0123456789" {
+		t.Errorf("the content should be %q, not %q", "0123456789", str)
+	}
+
+	str = "unchanged"
+	ss.MaxSize = 9
+	err := ss.SetWithVal("", pathname)
+	if err == nil {
+		t.Errorf("a file larger than the MaxSize should give an error")
+	} else {
+		testhelper.ShouldContain(t, "too large", "error", err.Error(),
+			[]string{"is too large", "not be more than 9B"})
+	}
+	if str != "unchanged" {
+		t.Errorf("the Value should not change on error but is %q", str)
+	}
+}
@@ -0,0 +1,79 @@
+package psetter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// globStarStar is the path segment in a Glob which matches any number of
+// path segments
+const globStarStar = "**"
+
+// Glob is a compiled glob pattern. The pattern is split into segments at
+// each '/' and each segment is matched against the corresponding segment
+// of the name using path.Match. A segment of "**" matches zero or more
+// whole segments, so "src/**/*.go" matches "src/a.go" and "src/x/y/a.go".
+// A Glob always matches the whole of the name.
+type Glob struct {
+	pattern string
+	segs    []string
+}
+
+// CompileGlob checks the pattern and returns the corresponding Glob. It
+// returns an error if any segment of the pattern is malformed.
+func CompileGlob(pattern string) (Glob, error) {
+	g := Glob{pattern: pattern}
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == globStarStar {
+			if len(g.segs) > 0 && g.segs[len(g.segs)-1] == globStarStar {
+				continue
+			}
+		} else if _, err := path.Match(seg, ""); err != nil {
+			return Glob{}, fmt.Errorf("bad glob pattern '%s': %s",
+				pattern, err)
+		}
+		g.segs = append(g.segs, seg)
+	}
+	return g, nil
+}
+
+// MustCompileGlob is like CompileGlob but panics if the pattern is
+// malformed
+func MustCompileGlob(pattern string) Glob {
+	g, err := CompileGlob(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// MatchString returns true if the name matches the Glob
+func (g Glob) MatchString(name string) bool {
+	return matchSegs(g.segs, strings.Split(name, "/"))
+}
+
+// String returns the pattern the Glob was compiled from
+func (g Glob) String() string { return g.pattern }
+
+// matchSegs returns true if the name segments match the pattern segments
+func matchSegs(segs, names []string) bool {
+	for len(segs) > 0 {
+		if segs[0] == globStarStar {
+			for i := 0; i <= len(names); i++ {
+				if matchSegs(segs[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(segs[0], names[0]); !ok {
+			return false
+		}
+		segs, names = segs[1:], names[1:]
+	}
+	return len(names) == 0
+}
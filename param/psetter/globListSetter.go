@@ -0,0 +1,77 @@
+package psetter
+
+import (
+	"errors"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+)
+
+// GlobListSetter allows you to specify a parameter that can be used to set
+// a list of glob patterns, for instance a list of files to include or
+// exclude. See Glob for the pattern syntax, which extends that of
+// path.Match with "**" matching any number of directories.
+//
+// The Append field of the embedded ListEdit controls whether the patterns
+// replace the list or are added to it. If its EditPrefixes field is set
+// the value may also be given an edit prefix to add patterns to the list
+// or to remove them from it - see ListEdit for details. A pattern is
+// removed if it is the same as the pattern given.
+//
+// You can also supply check functions that each pattern must pass, for
+// instance check.PatternNotMatchEmpty(). Use Patterns to convert the Value
+// into a PatternList for use in a Matcher.
+type GlobListSetter struct {
+	Value *[]Glob
+	StrListSeparator
+	ListEdit
+	Checks []check.Pattern
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s GlobListSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s GlobListSetter) Set(_ string) error {
+	return errors.New("no pattern given (it should be followed by '=pattern')")
+}
+
+// SetWithVal (called when a value follows the parameter) splits the value
+// using the list separator and compiles each part as a Glob. It returns an
+// error if any pattern is malformed or fails a check or if a pattern to be
+// removed is not in the list; the Value is only changed if there is no
+// error.
+func (s GlobListSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := editPatternList(s.ListEdit, s.GetSeparator(), *s.Value,
+		paramVal, CompileGlob, s.Checks)
+	if err != nil {
+		return err
+	}
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s GlobListSetter) AllowedValues() string {
+	rval := "a list of glob patterns separated by '" +
+		s.GetSeparator() + "'. A '**' directory matches any number of" +
+		" directories"
+	if len(s.Checks) != 0 {
+		rval += ". The patterns are subject to checks"
+	}
+	return rval + s.describeListEdit(s.EditPrefixes)
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s GlobListSetter) CurrentValue() string {
+	return patternListString(*s.Value, s.GetSeparator())
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil.
+func (s GlobListSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name +
+			": GlobListSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,127 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// HostPortSetter allows you to specify a parameter that can be used to set
+// a string holding a network address of the form host:port, as used by
+// net.Dial and net.Listen. The host may be a host name, an IPv4 address or
+// an IPv6 address in square brackets and may be empty (meaning all local
+// addresses when listening). The port must be a number between 0 and 65535.
+//
+// If DfltPort is non-zero then the port may be left out and DfltPort will be
+// used. The Value is always set to the full host:port form.
+//
+// You can also supply check functions that will validate the Value.
+type HostPortSetter struct {
+	Value    *string
+	DfltPort int
+	Checks   []check.String
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s HostPortSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s HostPortSetter) Set(_ string) error {
+	return errors.New("no address given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be split into a host and a port, if it cannot it returns an
+// error. If there is a check and the check is violated it returns an
+// error. Only if the value is split successfully and the check is not
+// violated is the Value set.
+func (s HostPortSetter) SetWithVal(_ string, paramVal string) error {
+	host, port, err := s.split(paramVal)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as host:port: %s",
+			paramVal, err)
+	}
+	v := net.JoinHostPort(host, port)
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// split returns the host and port parts of the value, using the default
+// port if the value has no port and there is a default
+func (s HostPortSetter) split(val string) (host, port string, err error) {
+	host, port, err = net.SplitHostPort(val)
+	if err != nil {
+		if s.DfltPort == 0 {
+			return "", "", err
+		}
+		host = val
+		if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+			host = host[1 : len(host)-1]
+		} else if strings.Contains(host, ":") {
+			return "", "", err
+		}
+		if strings.ContainsAny(host, "[]") {
+			return "", "", err
+		}
+		port = strconv.Itoa(s.DfltPort)
+	}
+
+	p, convErr := strconv.Atoi(port)
+	if convErr != nil || p < 0 || p > 65535 {
+		return "", "", fmt.Errorf(
+			"the port (%s) must be a number between 0 and 65535", port)
+	}
+	return host, port, nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s HostPortSetter) AllowedValues() string {
+	rval := "a network address of the form host:port" +
+		" where the host is a host name, an IPv4 address," +
+		" an IPv6 address in square brackets or empty" +
+		" and the port is a number between 0 and 65535"
+	if s.DfltPort != 0 {
+		rval += fmt.Sprintf(". The port may be left out, the default is %d",
+			s.DfltPort)
+	}
+	if len(s.Checks) != 0 {
+		rval += ". The value is subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s HostPortSetter) CurrentValue() string {
+	return *s.Value
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or the default port is out of range
+func (s HostPortSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name +
+			": HostPortSetter Check failed: the Value to be set is nil")
+	}
+	if s.DfltPort < 0 || s.DfltPort > 65535 {
+		panic(fmt.Sprintf("%s: HostPortSetter Check failed:"+
+			" the default port (%d) must be between 0 and 65535",
+			name, s.DfltPort))
+	}
+}
@@ -13,10 +13,16 @@ import (
 // list (a slice) of int64's. You can override the list separator by setting
 // the Sep value.
 //
+// The Append field of the embedded ListEdit controls whether the values
+// replace the list or are added to it. If its EditPrefixes field is set
+// the value may also be given an edit prefix to add values to the list or
+// to remove them from it - see ListEdit for details.
+//
 // If you have a list of allowed values you should use EnumListSetter
 type Int64ListSetter struct {
 	Value *[]int64
 	StrListSeparator
+	ListEdit
 	Checks []check.Int64Slice
 }
 
@@ -30,13 +36,21 @@ func (s Int64ListSetter) Set(_ string) error {
 }
 
 // SetWithVal (called when a value follows the parameter) splits the value
-// into a slice of int64's and sets the Value accordingly. It will return
-// an error if a check is breached.
+// into a slice of int64's and edits the Value accordingly. It will return
+// an error if a check is breached by the resulting list or if a value to
+// be removed is not in the list; the Value is only changed if there is no
+// error.
 func (s Int64ListSetter) SetWithVal(_ string, paramVal string) error {
-	sep := s.GetSeparator()
-	sv := strings.Split(paramVal, sep)
+	op, paramVal, hasVals, err := s.editOp(paramVal)
+	if err != nil {
+		return err
+	}
+	sv := []string{}
+	if hasVals {
+		sv = strings.Split(paramVal, s.GetSeparator())
+	}
 
-	v := make([]int64, 0, len(sv))
+	vals := make([]int64, 0, len(sv))
 	for i, strVal := range sv {
 		intVal, err := strconv.ParseInt(strVal, 0, 0)
 		if err != nil {
@@ -45,7 +59,11 @@ func (s Int64ListSetter) SetWithVal(_ string, paramVal string) error {
 					" could not be parsed as an integer value: %s",
 				i, strVal, err)
 		}
-		v = append(v, intVal)
+		vals = append(vals, intVal)
+	}
+	v, err := applyListEdit(op, *s.Value, vals)
+	if err != nil {
+		return err
 	}
 
 	if len(s.Checks) != 0 {
@@ -74,7 +92,7 @@ func (s Int64ListSetter) AllowedValues() string {
 	if len(s.Checks) != 0 {
 		rval += " subject to checks"
 	}
-	return rval
+	return rval + s.describeListEdit(s.EditPrefixes)
 }
 
 // CurrentValue returns the current setting of the parameter value
@@ -0,0 +1,77 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"net"
+	"net/netip"
+)
+
+// IPSetter allows you to specify a parameter that can be used to set a
+// net.IP value. You can also supply check functions that will validate the
+// Value; the checks are given the address as a netip.Addr.
+type IPSetter struct {
+	Value  *net.IP
+	Checks []check.Addr
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s IPSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s IPSetter) Set(_ string) error {
+	return errors.New("no IP address given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to an IP address, if it cannot be parsed successfully
+// it returns an error. If there is a check and the check is violated it
+// returns an error. Only if the value is parsed successfully and the check
+// is not violated is the Value set.
+func (s IPSetter) SetWithVal(_ string, paramVal string) error {
+	v := net.ParseIP(paramVal)
+	if v == nil {
+		return fmt.Errorf("could not parse '%s' as an IP address", paramVal)
+	}
+
+	a, _ := netip.AddrFromSlice(v)
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(a.Unmap())
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s IPSetter) AllowedValues() string {
+	rval := "an IPv4 address in dotted decimal form (such as 192.0.2.1)" +
+		" or an IPv6 address (such as 2001:db8::1)"
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s IPSetter) CurrentValue() string {
+	return s.Value.String()
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s IPSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": IPSetter Check failed: the Value to be set is nil")
+	}
+}
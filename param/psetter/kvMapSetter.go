@@ -0,0 +1,256 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"sort"
+	"strings"
+)
+
+// KVDefaultSep is the default separator between the key and the value in
+// each entry given to a KVMapSetter or a StrMapSetter
+const KVDefaultSep = "="
+
+// DupKeyPolicy says what should happen if the same key is given more than
+// once in a parameter value
+type DupKeyPolicy int
+
+// These are the policies for duplicate keys
+const (
+	// DupKeyError reports an error if a key is repeated
+	DupKeyError DupKeyPolicy = iota
+	// DupKeyLastWins uses the last value given for a key
+	DupKeyLastWins
+	// DupKeyAccumulate combines the values given for a key
+	DupKeyAccumulate
+)
+
+// KVMapSetter sets entries in a map of strings to values of type T from a
+// list of key=value pairs, for instance "k1=v1,k2=v2". Each value is
+// converted by the Parse func. The entries are only set if every entry in
+// the list is valid.
+//
+// If AllowedKeys is not empty then each key must be one of them; the
+// AValMatch can be set to allow the case of the keys to be ignored or to
+// allow keys to be abbreviated. The key is stored in the map as the
+// allowed value it matches.
+//
+// The DupKeys policy says what happens if a key is repeated in the
+// list. For DupKeyAccumulate the Merge func is called with the earlier and
+// later values to give the value to be used. The policy also applies to a
+// key given in a later use of the parameter which is already in the map:
+// for DupKeyAccumulate the values are merged, otherwise the new value
+// replaces the existing entry.
+//
+// You can also supply check functions that will validate the keys and the
+// values.
+type KVMapSetter[T any] struct {
+	Value *map[string]T
+	Parse func(s string) (T, error)
+	// Format gives the string form of a value for CurrentValue. If it is
+	// nil the value is shown using the %v format
+	Format      func(v T) string
+	AllowedKeys AValMap
+	AValMatch
+	DupKeys     DupKeyPolicy
+	Merge       func(earlier, later T) T
+	KeyChecks   []check.String
+	ValueChecks []func(v T) error
+	// KVSep is the separator between the key and the value. If it is empty
+	// then KVDefaultSep is used
+	KVSep string
+	StrListSeparator
+}
+
+// kvSep returns the separator between keys and values
+func (s KVMapSetter[T]) kvSep() string {
+	if s.KVSep == "" {
+		return KVDefaultSep
+	}
+	return s.KVSep
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s KVMapSetter[T]) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s KVMapSetter[T]) Set(_ string) error {
+	return errors.New("no value given (it should be followed by '=key" +
+		s.kvSep() + "value...')")
+}
+
+// SetWithVal (called when a value follows the parameter) splits the value
+// into entries using the list separator and each entry into a key and a
+// value. It checks all the keys and values for validity and only if they
+// are all valid does it set the entries in the map pointed to by the
+// Value. It returns an error for the first invalid entry.
+func (s KVMapSetter[T]) SetWithVal(_ string, paramVal string) error {
+	kvSep := s.kvSep()
+	entries := make(map[string]T)
+	order := []string{}
+
+	for _, entry := range strings.Split(paramVal, s.GetSeparator()) {
+		parts := strings.SplitN(entry, kvSep, 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("'%s' is not of the form key%svalue",
+				entry, kvSep)
+		}
+		k, err := s.checkKey(parts[0])
+		if err != nil {
+			return err
+		}
+		v, err := s.parseVal(k, parts[1])
+		if err != nil {
+			return err
+		}
+
+		if earlier, ok := entries[k]; ok {
+			switch s.DupKeys {
+			case DupKeyLastWins:
+			case DupKeyAccumulate:
+				v = s.Merge(earlier, v)
+			default:
+				return fmt.Errorf("the key '%s' is given more than once", k)
+			}
+		} else {
+			order = append(order, k)
+		}
+		entries[k] = v
+	}
+
+	for _, k := range order {
+		v := entries[k]
+		if existing, ok := (*s.Value)[k]; ok && s.DupKeys == DupKeyAccumulate {
+			v = s.Merge(existing, v)
+		}
+		(*s.Value)[k] = v
+	}
+	return nil
+}
+
+// checkKey returns the key to be used in the map, checking it against the
+// allowed keys and the key checks
+func (s KVMapSetter[T]) checkKey(k string) (string, error) {
+	if k == "" {
+		return k, errors.New("a key must not be empty")
+	}
+	if len(s.AllowedKeys) != 0 {
+		var err error
+		k, err = s.matchAVal(k, s.AllowedKeys)
+		if err != nil {
+			return k, err
+		}
+	}
+
+	for _, check := range s.KeyChecks {
+		if check == nil {
+			continue
+		}
+
+		err := check(k)
+		if err != nil {
+			return k, fmt.Errorf("bad key: %s", err)
+		}
+	}
+	return k, nil
+}
+
+// parseVal converts the value for the key, checking it against the value
+// checks
+func (s KVMapSetter[T]) parseVal(k, val string) (T, error) {
+	v, err := s.Parse(val)
+	if err != nil {
+		return v, fmt.Errorf("bad value for the key '%s': %s", k, err)
+	}
+
+	for _, check := range s.ValueChecks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return v, fmt.Errorf("bad value for the key '%s': %s", k, err)
+		}
+	}
+	return v, nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s KVMapSetter[T]) AllowedValues() string {
+	rval := "a list of key" + s.kvSep() + "value entries separated by '" +
+		s.GetSeparator() + "'."
+	if len(s.AllowedKeys) != 0 {
+		rval += " The keys must be from the following:\n" +
+			allowedValues(s.AllowedKeys) + s.describe()
+	}
+	if len(s.KeyChecks) != 0 || len(s.ValueChecks) != 0 {
+		rval += "\nThe keys and values are subject to checks."
+	}
+	switch s.DupKeys {
+	case DupKeyLastWins:
+		rval += "\nIf a key is repeated the last value is used."
+	case DupKeyAccumulate:
+		rval += "\nIf a key is repeated, or given again in a later use of" +
+			" the parameter, the values are combined."
+	default:
+		rval += "\nA key may not be repeated."
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s KVMapSetter[T]) CurrentValue() string {
+	keys := make([]string, 0, len(*s.Value))
+	for k := range *s.Value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cv := ""
+	sep := ""
+	for _, k := range keys {
+		v := (*s.Value)[k]
+		if s.Format != nil {
+			cv += sep + k + s.kvSep() + s.Format(v)
+		} else {
+			cv += sep + fmt.Sprintf("%s%s%v", k, s.kvSep(), v)
+		}
+		sep = s.GetSeparator()
+	}
+
+	return cv
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or the map has not been created yet or if there is no Parse
+// func or if the duplicate key policy is to accumulate and there is no
+// Merge func.
+func (s KVMapSetter[T]) CheckSetter(name string) {
+	checkKVMapSetter(name, "KVMapSetter", s)
+}
+
+// checkKVMapSetter panics if the setter has not been properly created,
+// reporting the problem as being with the named type of setter
+func checkKVMapSetter[T any](name, setterType string, s KVMapSetter[T]) {
+	intro := name + ": " + setterType + " Check failed: "
+	if s.Value == nil {
+		panic(intro + "the Value to be set is nil")
+	}
+	if *s.Value == nil {
+		panic(intro + "the map has not been created")
+	}
+	if s.Parse == nil {
+		panic(intro + "there is no Parse func")
+	}
+	if s.DupKeys == DupKeyAccumulate && s.Merge == nil {
+		panic(intro + "duplicate keys are accumulated but there is no Merge func")
+	}
+	if s.kvSep() == s.GetSeparator() {
+		panic(intro + "the key/value separator is the same as" +
+			" the list separator")
+	}
+}
@@ -0,0 +1,233 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// parseInt64 converts the string into an int64
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 0, 64)
+}
+
+func TestStrMapSetter(t *testing.T) {
+	allowedKeys := psetter.AValMap{
+		"env":  "the environment",
+		"team": "the owning team",
+	}
+	testCases := []struct {
+		testName       string
+		setter         psetter.StrMapSetter
+		initVal        map[string]string
+		val            string
+		expVal         string
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			testName: "simple",
+			val:      "k1=v1,k2=v2",
+			expVal:   "k1=v1,k2=v2",
+		},
+		{
+			testName: "empty value and separator in value",
+			val:      "k1=,k2=a=b",
+			expVal:   "k1=,k2=a=b",
+		},
+		{
+			testName: "added to existing entries",
+			initVal:  map[string]string{"k1": "old", "k0": "v0"},
+			val:      "k1=v1",
+			expVal:   "k0=v0,k1=v1",
+		},
+		{
+			testName:       "no key/value separator",
+			val:            "k1=v1,k2",
+			errExpected:    true,
+			errMustContain: []string{"'k2' is not of the form key=value"},
+		},
+		{
+			testName:       "empty key",
+			val:            "=v1",
+			errExpected:    true,
+			errMustContain: []string{"a key must not be empty"},
+		},
+		{
+			testName:       "duplicate key - error",
+			val:            "k1=v1,k1=v2",
+			errExpected:    true,
+			errMustContain: []string{"the key 'k1' is given more than once"},
+		},
+		{
+			testName: "duplicate key - last wins",
+			setter:   psetter.StrMapSetter{DupKeys: psetter.DupKeyLastWins},
+			val:      "k1=v1,k1=v2",
+			expVal:   "k1=v2",
+		},
+		{
+			testName: "duplicate key - accumulate",
+			setter:   psetter.StrMapSetter{DupKeys: psetter.DupKeyAccumulate},
+			val:      "tag=a,tag=b,x=y",
+			expVal:   `tag="a,b",x=y`,
+		},
+		{
+			testName: "duplicate key - accumulate with an existing entry",
+			setter:   psetter.StrMapSetter{DupKeys: psetter.DupKeyAccumulate},
+			initVal:  map[string]string{"tag": "a"},
+			val:      "tag=b,x=y",
+			expVal:   `tag="a,b",x=y`,
+		},
+		{
+			testName: "duplicate key - last wins with an existing entry",
+			setter:   psetter.StrMapSetter{DupKeys: psetter.DupKeyLastWins},
+			initVal:  map[string]string{"tag": "a"},
+			val:      "tag=b",
+			expVal:   "tag=b",
+		},
+		{
+			testName: "quoted value is quoted",
+			val:      `k="v"`,
+			expVal:   `k="\"v\""`,
+		},
+		{
+			testName: "allowed keys",
+			setter: psetter.StrMapSetter{
+				AllowedKeys: allowedKeys,
+				AValMatch:   psetter.AValMatch{IgnoreCase: true},
+			},
+			val:    "ENV=prod,team=infra",
+			expVal: "env=prod,team=infra",
+		},
+		{
+			testName:       "key not allowed",
+			setter:         psetter.StrMapSetter{AllowedKeys: allowedKeys},
+			val:            "env=prod,tema=infra",
+			errExpected:    true,
+			errMustContain: []string{"invalid value: 'tema'", "team"},
+		},
+		{
+			testName: "key check fails",
+			setter: psetter.StrMapSetter{
+				KeyChecks: []check.String{
+					check.StringMatchesPattern(
+						regexp.MustCompile(`^[a-z]+$`), "lower-case letters"),
+				},
+			},
+			val:            "k1=v1",
+			errExpected:    true,
+			errMustContain: []string{"bad key: k1 does not match"},
+		},
+		{
+			testName: "value check fails",
+			setter: psetter.StrMapSetter{
+				ValueChecks: []check.String{nil, check.StringLenLT(4)},
+			},
+			val:         "k1=v1,k2=long",
+			errExpected: true,
+			errMustContain: []string{
+				"bad value for the key 'k2'",
+				"must be less than 4",
+			},
+		},
+		{
+			testName: "other separators",
+			setter: psetter.StrMapSetter{
+				KVSep:            ":",
+				StrListSeparator: psetter.StrListSeparator{Sep: ";"},
+			},
+			val:    "Accept:text/html;Host:example.com",
+			expVal: "Accept:text/html;Host:example.com",
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		m := make(map[string]string)
+		for k, v := range tc.initVal {
+			m[k] = v
+		}
+		s := tc.setter
+		s.Value = &m
+		initCV := s.CurrentValue()
+
+		err := s.SetWithVal("", tc.val)
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: an unexpected error was returned"+
+					" when processing '%s': %s", tc.val, err)
+				continue
+			}
+			testhelper.ShouldContain(t, testID, "error", err.Error(),
+				tc.errMustContain)
+			if cv := s.CurrentValue(); cv != initCV {
+				t.Log(testID)
+				t.Errorf("\t: the map should not change on error: %q", cv)
+			}
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected when processing '%s'"+
+				" but none was returned", tc.val)
+		} else if cv := s.CurrentValue(); cv != tc.expVal {
+			t.Log(testID)
+			t.Errorf("\t: the map was not as expected, got %q, expected %q",
+				cv, tc.expVal)
+		}
+	}
+}
+
+func TestKVMapSetter(t *testing.T) {
+	m := make(map[string]int64)
+	s := psetter.KVMapSetter[int64]{
+		Value:       &m,
+		Parse:       parseInt64,
+		DupKeys:     psetter.DupKeyAccumulate,
+		Merge:       func(a, b int64) int64 { return a + b },
+		ValueChecks: []func(int64) error{check.Int64GT(0)},
+	}
+
+	if err := s.SetWithVal("", "a=1,b=0x10,a=2"); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if cv := s.CurrentValue(); cv != "a=3,b=16" {
+		t.Errorf("unexpected value: %q", cv)
+	}
+
+	if err := s.SetWithVal("", "a=4"); err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if cv := s.CurrentValue(); cv != "a=7,b=16" {
+		t.Errorf("a later use should accumulate, unexpected value: %q", cv)
+	}
+
+	err := s.SetWithVal("", "c=x")
+	if err == nil {
+		t.Errorf("an unparseable value should give an error")
+	} else {
+		testhelper.ShouldContain(t, "unparseable", "error", err.Error(),
+			[]string{"bad value for the key 'c'", "invalid syntax"})
+	}
+
+	err = s.SetWithVal("", "c=-1")
+	if err == nil {
+		t.Errorf("a value failing the checks should give an error")
+	} else {
+		testhelper.ShouldContain(t, "check fails", "error", err.Error(),
+			[]string{"bad value for the key 'c'", "must be greater than 0"})
+	}
+
+	s.Format = func(v int64) string { return fmt.Sprintf("%#x", v) }
+	if cv := s.CurrentValue(); cv != "a=0x7,b=0x10" {
+		t.Errorf("unexpected formatted value: %q", cv)
+	}
+	testhelper.ShouldContain(t, "allowed values", "allowed values",
+		s.AllowedValues(),
+		[]string{"a list of key=value entries separated by ','",
+			"subject to checks",
+			"the values are combined"})
+}
@@ -0,0 +1,187 @@
+package psetter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ListEdit is embedded in the list setters to control how a value
+// changes the list. If Append is false (the default) the value replaces the
+// list, if it is true the values are added to the end of the list so that
+// repeated parameters accumulate.
+//
+// If EditPrefixes is set the value may also start with an edit prefix:
+// "+=" adds the values to the list, "-=" removes them from the list and
+// "=" replaces the list (an empty value after "=" empties the list). A
+// parameter name followed by '+' or '-' is then turned into the
+// corresponding prefix by the param package so that, for instance,
+// "-tags+=x" adds "x" to the tags. A value starting with one of these
+// prefixes cannot then be given as it is, so EditPrefixes should only be
+// set if no value will start with '=', '+=' or '-='. If EditPrefixes is
+// not set the value is used as given.
+type ListEdit struct {
+	Append       bool
+	EditPrefixes bool
+}
+
+// EditsList returns true if the setter accepts edit prefixes, in which
+// case it is a param.ListEditor
+func (le ListEdit) EditsList() bool { return le.EditPrefixes }
+
+// editOp returns the operation to be applied to the list and the value
+// with any edit prefix removed. Edit prefixes are only recognised if
+// EditPrefixes is set. The returned bool and error are as for splitEditOp.
+func (le ListEdit) editOp(val string) (listEditOp, string, bool, error) {
+	if le.EditPrefixes {
+		return splitEditOp(val, le.Append)
+	}
+	if le.Append {
+		return listAppend, val, true, nil
+	}
+	return listSet, val, true, nil
+}
+
+// listEditOp describes how the values given should change the list
+type listEditOp int
+
+const (
+	listSet listEditOp = iota
+	listAppend
+	listRemove
+)
+
+// splitEditOp strips any edit prefix from the value and returns the
+// operation it gives. If there is no prefix the operation is to append if
+// dfltAppend is true and to replace the list otherwise. The returned bool
+// is false if the prefix is "=" with no values, in which case the list
+// should be emptied. It returns an error if there is a "+=" or "-=" prefix
+// but no values are given.
+func splitEditOp(val string, dfltAppend bool) (listEditOp, string, bool, error) {
+	var op listEditOp
+	switch {
+	case strings.HasPrefix(val, "+="):
+		op, val = listAppend, val[2:]
+	case strings.HasPrefix(val, "-="):
+		op, val = listRemove, val[2:]
+	case strings.HasPrefix(val, "="):
+		return listSet, val[1:], val != "=", nil
+	case dfltAppend:
+		return listAppend, val, true, nil
+	default:
+		return listSet, val, true, nil
+	}
+
+	if val == "" {
+		return op, val, false, fmt.Errorf("no values given to %s",
+			op.verb())
+	}
+	return op, val, true, nil
+}
+
+// verb returns a word describing the operation
+func (op listEditOp) verb() string {
+	switch op {
+	case listAppend:
+		return "add"
+	case listRemove:
+		return "remove"
+	}
+	return "set"
+}
+
+// applyListEdit returns the list resulting from applying the operation
+// with the given values to the current list. The current list is not
+// changed. Removing a value which is not in the list is an error; if the
+// value appears more than once only the first is removed.
+func applyListEdit[T comparable](op listEditOp, current, vals []T) ([]T, error) {
+	switch op {
+	case listAppend:
+		rval := make([]T, 0, len(current)+len(vals))
+		rval = append(rval, current...)
+		return append(rval, vals...), nil
+	case listRemove:
+		rval := append([]T{}, current...)
+	Vals:
+		for _, v := range vals {
+			for i, cv := range rval {
+				if cv == v {
+					rval = append(rval[:i], rval[i+1:]...)
+					continue Vals
+				}
+			}
+			return nil, fmt.Errorf("cannot remove '%v': it is not in the list",
+				v)
+		}
+		return rval, nil
+	}
+	return vals, nil
+}
+
+// applyMapEdit applies the operation with the given keys to the map. The
+// map is changed in place rather than replaced so that any other
+// references to it will see the change. A listSet operation leaves the map
+// holding just the given keys. Removing a key which is not in the map is
+// an error, in which case the map is not changed.
+func applyMapEdit(op listEditOp, m map[string]bool, keys []string) error {
+	if op == listRemove {
+		removed := make(map[string]bool, len(keys))
+		for _, k := range keys {
+			if _, ok := m[k]; !ok || removed[k] {
+				return fmt.Errorf("cannot remove '%s': it is not in the map",
+					k)
+			}
+			removed[k] = true
+		}
+	}
+
+	if op == listSet {
+		for k := range m {
+			delete(m, k)
+		}
+	}
+	for _, k := range keys {
+		if op == listRemove {
+			delete(m, k)
+		} else {
+			m[k] = true
+		}
+	}
+	return nil
+}
+
+// describeListEdit returns a string describing the edit syntax for the
+// list setters. The edit prefixes are only described if hasPrefixes is true
+func (le ListEdit) describeListEdit(hasPrefixes bool) string {
+	rval := ""
+	if hasPrefixes {
+		rval = "\nThe values may be preceded by '+=' to add them to the" +
+			" list, '-=' to remove them or '=' to replace the list."
+		if le.Append {
+			rval += " Otherwise the values are added to the list."
+		}
+	} else if le.Append {
+		rval = "\nThe values are added to the list."
+	}
+	return rval
+}
+
+// mapEditOp returns the operation to be applied to a map and the value
+// with any edit prefix removed. Edit prefixes are only recognised if
+// editPrefixes is true; otherwise the keys are added to the map. The
+// returned bool and error are as for splitEditOp.
+func mapEditOp(val string, editPrefixes bool) (listEditOp, string, bool, error) {
+	if editPrefixes {
+		return splitEditOp(val, true)
+	}
+	return listAppend, val, true, nil
+}
+
+// describeMapEdit returns a string describing the edit syntax for the map
+// setters. The edit prefixes are only described if hasPrefixes is true
+func describeMapEdit(hasPrefixes bool) string {
+	if !hasPrefixes {
+		return ""
+	}
+	return "\nThe values may be preceded by '+=' to add them to the map," +
+		" '-=' to remove them or '=' to clear the map first."
+}
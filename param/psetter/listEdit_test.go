@@ -0,0 +1,362 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+)
+
+// editTestCase holds a sequence of values to be applied to a setter and
+// the expected value after each one
+type editTestCase struct {
+	testName       string
+	vals           []string
+	expVals        []string
+	errExpected    bool
+	errMustContain []string
+}
+
+// testListEdits applies each value in turn to the setter made by mkSetter,
+// checking the value after each edit. If an error is expected it must be
+// returned by the last value and the current value must not change.
+func testListEdits(t *testing.T, setterName string,
+	mkSetter func() param.Setter, testCases []editTestCase) {
+	t.Helper()
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("%s: test %d: %s", setterName, i, tc.testName)
+		s := mkSetter()
+		for j, val := range tc.vals {
+			prevCV := s.CurrentValue()
+			err := s.SetWithVal("", val)
+			if err != nil {
+				if !tc.errExpected || j != len(tc.vals)-1 {
+					t.Log(testID)
+					t.Errorf("\t: an unexpected error was returned"+
+						" when processing '%s': %s", val, err)
+					break
+				}
+				testhelper.ShouldContain(t, testID, "error", err.Error(),
+					tc.errMustContain)
+				if cv := s.CurrentValue(); cv != prevCV {
+					t.Log(testID)
+					t.Errorf("\t: the value should not change on error: %q",
+						cv)
+				}
+				break
+			}
+			if tc.errExpected && j == len(tc.vals)-1 {
+				t.Log(testID)
+				t.Errorf("\t: an error was expected when processing '%s'"+
+					" but none was returned", val)
+				break
+			}
+			if cv := s.CurrentValue(); cv != tc.expVals[j] {
+				t.Log(testID)
+				t.Errorf("\t: after '%s' the value was %q, expected %q",
+					val, cv, tc.expVals[j])
+			}
+		}
+	}
+}
+
+func TestStrListSetterEdits(t *testing.T) {
+	testListEdits(t, "StrListSetter",
+		func() param.Setter {
+			v := []string{"a", "b"}
+			return psetter.StrListSetter{
+				Value:    &v,
+				ListEdit: psetter.ListEdit{EditPrefixes: true},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "no prefix replaces",
+				vals:     []string{"x,y", "z"},
+				expVals:  []string{"x,y", "z"},
+			},
+			{
+				testName: "append, remove and reset",
+				vals:     []string{"+=c,d", "-=a,c", "=", "+=e"},
+				expVals:  []string{"a,b,c,d", "b,d", "", "e"},
+			},
+			{
+				testName: "reset with values",
+				vals:     []string{"=x,y"},
+				expVals:  []string{"x,y"},
+			},
+			{
+				testName: "only the first duplicate is removed",
+				vals:     []string{"+=a", "-=a"},
+				expVals:  []string{"a,b,a", "b,a"},
+			},
+			{
+				testName:       "remove an absent value",
+				vals:           []string{"-=b,x"},
+				errExpected:    true,
+				errMustContain: []string{"cannot remove 'x'"},
+			},
+			{
+				testName:       "append nothing",
+				vals:           []string{"+="},
+				errExpected:    true,
+				errMustContain: []string{"no values given to add"},
+			},
+		})
+
+	testListEdits(t, "StrListSetter (Append)",
+		func() param.Setter {
+			v := []string{"a"}
+			return psetter.StrListSetter{
+				Value:    &v,
+				ListEdit: psetter.ListEdit{Append: true, EditPrefixes: true},
+				Checks:   []check.StringSlice{check.StringSliceLenLT(4)},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "no prefix appends",
+				vals:     []string{"b", "c", "=d"},
+				expVals:  []string{"a,b", "a,b,c", "d"},
+			},
+			{
+				testName:       "the check applies to the whole list",
+				vals:           []string{"b,c", "d"},
+				expVals:        []string{"a,b,c"},
+				errExpected:    true,
+				errMustContain: []string{"must be less than 4"},
+			},
+		})
+}
+
+func TestStrListSetterNoEditPrefixes(t *testing.T) {
+	testListEdits(t, "StrListSetter (no EditPrefixes)",
+		func() param.Setter {
+			v := []string{"a"}
+			return psetter.StrListSetter{Value: &v}
+		},
+		[]editTestCase{
+			{
+				testName: "prefixes are part of the value",
+				vals:     []string{"=x", "+=y,-=z"},
+				expVals:  []string{"=x", "+=y,-=z"},
+			},
+		})
+
+	v := []string{}
+	s := psetter.StrListSetter{Value: &v}
+	if s.EditsList() {
+		t.Errorf("a StrListSetter without EditPrefixes should not edit lists")
+	}
+}
+
+func TestInt64ListSetterEdits(t *testing.T) {
+	testListEdits(t, "Int64ListSetter",
+		func() param.Setter {
+			v := []int64{1, 2}
+			return psetter.Int64ListSetter{
+				Value:    &v,
+				ListEdit: psetter.ListEdit{Append: true, EditPrefixes: true},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "append and remove",
+				vals:     []string{"3", "+=0x10", "-=1,3", "=7"},
+				expVals:  []string{"1,2,3", "1,2,3,16", "2,16", "7"},
+			},
+			{
+				testName:       "bad number",
+				vals:           []string{"-=x"},
+				errExpected:    true,
+				errMustContain: []string{"could not be parsed"},
+			},
+			{
+				testName:       "remove an absent value",
+				vals:           []string{"-=5"},
+				errExpected:    true,
+				errMustContain: []string{"cannot remove '5'"},
+			},
+		})
+}
+
+func TestEnumListSetterEdits(t *testing.T) {
+	testListEdits(t, "EnumListSetter",
+		func() param.Setter {
+			v := []string{}
+			return psetter.EnumListSetter{
+				Value:       &v,
+				AllowedVals: psetter.AValMap{"red": "", "green": ""},
+				AValMatch:   psetter.AValMatch{IgnoreCase: true},
+				ListEdit:    psetter.ListEdit{EditPrefixes: true},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "edits match the allowed values",
+				vals:     []string{"RED", "+=Green", "-=red"},
+				expVals:  []string{"red", "red,green", "green"},
+			},
+			{
+				testName:       "bad value",
+				vals:           []string{"+=blue"},
+				errExpected:    true,
+				errMustContain: []string{"invalid value: 'blue'"},
+			},
+		})
+}
+
+func TestMapSetterEdits(t *testing.T) {
+	testListEdits(t, "MapSetter",
+		func() param.Setter {
+			m := map[string]bool{"a": true}
+			return psetter.MapSetter{Value: &m, EditPrefixes: true}
+		},
+		[]editTestCase{
+			{
+				testName: "add, remove and reset",
+				vals:     []string{"b", "+=c", "-=a,b", "=d", "="},
+				expVals: []string{
+					"a=true,b=true", "a=true,b=true,c=true", "c=true",
+					"d=true", "",
+				},
+			},
+			{
+				testName:       "remove an absent key",
+				vals:           []string{"-=a,x"},
+				errExpected:    true,
+				errMustContain: []string{"cannot remove 'x'"},
+			},
+			{
+				testName:       "remove a key twice",
+				vals:           []string{"-=a,a"},
+				errExpected:    true,
+				errMustContain: []string{"cannot remove 'a'"},
+			},
+		})
+
+	m := map[string]bool{"a": true}
+	shared := m
+	s := psetter.MapSetter{Value: &m, EditPrefixes: true}
+	for _, val := range []string{"b", "=c"} {
+		if err := s.SetWithVal("", val); err != nil {
+			t.Errorf("unexpected error setting %q: %s", val, err)
+		}
+	}
+	if len(shared) != 1 || !shared["c"] {
+		t.Errorf("the map should be edited in place, the shared map is: %v",
+			shared)
+	}
+
+	testListEdits(t, "EnumMapSetter",
+		func() param.Setter {
+			m := map[string]bool{}
+			return psetter.EnumMapSetter{
+				Value:        &m,
+				AllowedVals:  psetter.AValMap{"x": "", "y": ""},
+				EditPrefixes: true,
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "add and remove",
+				vals:     []string{"x", "+=y", "-=x"},
+				expVals:  []string{"x=true", "x=true,y=true", "y=true"},
+			},
+			{
+				testName:       "bad key",
+				vals:           []string{"-=z"},
+				errExpected:    true,
+				errMustContain: []string{"invalid value: 'z'"},
+			},
+		})
+}
+
+func TestListSettersNoEditPrefixes(t *testing.T) {
+	testListEdits(t, "Int64ListSetter (no EditPrefixes)",
+		func() param.Setter {
+			v := []int64{1}
+			return psetter.Int64ListSetter{
+				Value:    &v,
+				ListEdit: psetter.ListEdit{Append: true},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "negative values are appended",
+				vals:     []string{"-1,-2"},
+				expVals:  []string{"1,-1,-2"},
+			},
+			{
+				testName:       "prefixes are part of the value",
+				vals:           []string{"-=1"},
+				errExpected:    true,
+				errMustContain: []string{"(-=1) could not be parsed"},
+			},
+		})
+
+	testListEdits(t, "EnumListSetter (no EditPrefixes)",
+		func() param.Setter {
+			v := []string{}
+			return psetter.EnumListSetter{
+				Value:       &v,
+				AllowedVals: psetter.AValMap{"red": "", "=red": ""},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "prefixes are part of the value",
+				vals:     []string{"=red"},
+				expVals:  []string{"=red"},
+			},
+		})
+
+	testListEdits(t, "MapSetter (no EditPrefixes)",
+		func() param.Setter {
+			m := map[string]bool{"a": true}
+			return psetter.MapSetter{Value: &m}
+		},
+		[]editTestCase{
+			{
+				testName: "prefixes are part of the value",
+				vals:     []string{"-=a", "=b"},
+				expVals: []string{
+					"-=a=true,a=true",
+					"-=a=true,=b=true,a=true",
+				},
+			},
+		})
+
+	testListEdits(t, "EnumMapSetter (no EditPrefixes)",
+		func() param.Setter {
+			m := map[string]bool{}
+			return psetter.EnumMapSetter{
+				Value:       &m,
+				AllowedVals: psetter.AValMap{"x": "", "-=x": ""},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "prefixes are part of the value",
+				vals:     []string{"x", "-=x"},
+				expVals:  []string{"x=true", "-=x=true,x=true"},
+			},
+		})
+
+	var strs []string
+	var ints []int64
+	m := map[string]bool{}
+	for _, s := range []param.Setter{
+		psetter.Int64ListSetter{Value: &ints},
+		psetter.EnumListSetter{Value: &strs},
+		psetter.MapSetter{Value: &m},
+		psetter.EnumMapSetter{Value: &m},
+	} {
+		if le, ok := s.(param.ListEditor); !ok || le.EditsList() {
+			t.Errorf("a %T without EditPrefixes should not edit lists", s)
+		}
+	}
+}
@@ -4,14 +4,24 @@ import (
 	"errors"
 	"fmt"
 	"github.com/nickwells/golem/param"
+	"sort"
 	"strings"
 )
 
 // MapSetter sets the entry in a map of strings. Each value from the
 // parameter is used as a key in the map with the map entry set to true.
+//
+// If EditPrefixes is set the value may be given an edit prefix: "+=" adds
+// the keys (as when there is no prefix), "-=" removes them from the map
+// and "=" replaces the map with one holding just the given keys. A value
+// starting with '=', '+=' or '-=' is then always taken as having an edit
+// prefix. If EditPrefixes is not set the keys are always added. The map
+// is edited in place rather than replaced so the map must be created
+// before the parameters are parsed.
 type MapSetter struct {
 	Value *map[string]bool
 	StrListSeparator
+	EditPrefixes bool
 }
 
 // ValueReq returns param.Mandatory indicating that some value must follow
@@ -24,27 +34,40 @@ func (s MapSetter) Set(_ string) error {
 }
 
 // SetWithVal (called when a value follows the parameter) splits the value
-// using the list separator.
+// using the list separator and edits the map accordingly. It returns an
+// error if a key to be removed is not in the map, in which case the map is
+// not changed.
 func (s MapSetter) SetWithVal(_ string, paramVal string) error {
-	sep := s.GetSeparator()
-	values := strings.Split(paramVal, sep)
-	for _, v := range values {
-		(*s.Value)[v] = true
+	op, paramVal, hasVals, err := mapEditOp(paramVal, s.EditPrefixes)
+	if err != nil {
+		return err
 	}
-	return nil
+	values := []string{}
+	if hasVals {
+		values = strings.Split(paramVal, s.GetSeparator())
+	}
+	return applyMapEdit(op, *s.Value, values)
 }
 
 // AllowedValues returns a string listing the allowed values
 func (s MapSetter) AllowedValues() string {
 	return "a list of string values separated by '" +
-		s.GetSeparator() + "'."
+		s.GetSeparator() + "'." + describeMapEdit(s.EditPrefixes)
 }
 
-// CurrentValue returns the current setting of the parameter value
+// CurrentValue returns the current setting of the parameter value. The
+// entries are shown in key order
 func (s MapSetter) CurrentValue() string {
+	keys := make([]string, 0, len(*s.Value))
+	for k := range *s.Value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	cv := ""
 	sep := ""
-	for k, v := range *s.Value {
+	for _, k := range keys {
+		v := (*s.Value)[k]
 		cv += sep + fmt.Sprintf("%s=%v", k, v)
 		sep = s.GetSeparator()
 	}
@@ -52,6 +75,10 @@ func (s MapSetter) CurrentValue() string {
 	return cv
 }
 
+// EditsList returns true if the setter accepts edit prefixes, in which
+// case it is a param.ListEditor
+func (s MapSetter) EditsList() bool { return s.EditPrefixes }
+
 // CheckSetter panics if the setter has not been properly created - if the
 // Value is nil or the map has not been created yet.
 func (s MapSetter) CheckSetter(name string) {
@@ -0,0 +1,73 @@
+package psetter
+
+import "github.com/nickwells/golem/check"
+
+// PatternList is a list of compiled patterns such as those set by a
+// RegexpListSetter or a GlobListSetter
+type PatternList []check.StringMatcher
+
+// Patterns converts a slice of compiled patterns into a PatternList
+func Patterns[T check.StringMatcher](pats []T) PatternList {
+	pl := make(PatternList, 0, len(pats))
+	for _, p := range pats {
+		pl = append(pl, p)
+	}
+	return pl
+}
+
+// MatchAny returns true if any of the patterns matches the string. It
+// returns false if the list is empty
+func (pl PatternList) MatchAny(s string) bool {
+	for _, p := range pl {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAll returns true if all of the patterns match the string. It
+// returns true if the list is empty
+func (pl PatternList) MatchAll(s string) bool {
+	for _, p := range pl {
+		if !p.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchPrecedence says which of the Include and Exclude patterns of a
+// Matcher should win if a string matches both
+type MatchPrecedence int
+
+// These are the precedence rules for a Matcher
+const (
+	// ExcludeFirst means that a string matching any Exclude pattern is
+	// rejected even if it matches an Include pattern
+	ExcludeFirst MatchPrecedence = iota
+	// IncludeFirst means that a string matching any Include pattern is
+	// accepted even if it matches an Exclude pattern
+	IncludeFirst
+)
+
+// Matcher decides whether strings should be accepted using lists of
+// Include and Exclude patterns. A string matching neither list is
+// accepted only if there are no Include patterns. A string matching both
+// lists is accepted or rejected according to the Precedence.
+type Matcher struct {
+	Include    PatternList
+	Exclude    PatternList
+	Precedence MatchPrecedence
+}
+
+// Matches returns true if the string should be accepted
+func (m Matcher) Matches(s string) bool {
+	if m.Precedence == IncludeFirst && m.Include.MatchAny(s) {
+		return true
+	}
+	if m.Exclude.MatchAny(s) {
+		return false
+	}
+	return len(m.Include) == 0 || m.Include.MatchAny(s)
+}
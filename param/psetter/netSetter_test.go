@@ -0,0 +1,313 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+// netSetterTC holds the details of a test of one of the network setters
+type netSetterTC struct {
+	testName       string
+	s              param.Setter
+	val            string
+	expVal         string
+	errExpected    bool
+	errMustContain []string
+}
+
+// checkNetSetter calls SetWithVal on the setter and checks that the error
+// and the resulting value are as expected
+func checkNetSetter(t *testing.T, i int, tc netSetterTC) {
+	t.Helper()
+	testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+	initVal := tc.s.CurrentValue()
+
+	err := tc.s.SetWithVal("", tc.val)
+	if err != nil {
+		if !tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an unexpected error was returned"+
+				" when processing '%s': %s", tc.val, err)
+			return
+		}
+		testhelper.ShouldContain(t, testID, "error", err.Error(),
+			tc.errMustContain)
+		if v := tc.s.CurrentValue(); v != initVal {
+			t.Log(testID)
+			t.Errorf("\t: the value should not change on error: %q", v)
+		}
+	} else if tc.errExpected {
+		t.Log(testID)
+		t.Errorf("\t: an error was expected when processing '%s'"+
+			" but none was returned", tc.val)
+	} else if v := tc.s.CurrentValue(); v != tc.expVal {
+		t.Log(testID)
+		t.Errorf("\t: the value was not as expected, got %q, expected %q",
+			v, tc.expVal)
+	}
+}
+
+func TestIPAndAddrSetters(t *testing.T) {
+	loopback := []check.Addr{check.AddrIsLoopback()}
+	var ip net.IP
+	var addr netip.Addr
+
+	testCases := []netSetterTC{
+		{
+			testName: "IP: IPv4",
+			s:        psetter.IPSetter{Value: &ip},
+			val:      "192.0.2.1",
+			expVal:   "192.0.2.1",
+		},
+		{
+			testName: "IP: IPv6",
+			s:        psetter.IPSetter{Value: &ip},
+			val:      "2001:DB8::1",
+			expVal:   "2001:db8::1",
+		},
+		{
+			testName:       "IP: bad",
+			s:              psetter.IPSetter{Value: &ip},
+			val:            "192.0.2",
+			errExpected:    true,
+			errMustContain: []string{"could not parse '192.0.2' as an IP"},
+		},
+		{
+			testName: "IP: check passes",
+			s:        psetter.IPSetter{Value: &ip, Checks: loopback},
+			val:      "127.0.0.1",
+			expVal:   "127.0.0.1",
+		},
+		{
+			testName:       "IP: check fails",
+			s:              psetter.IPSetter{Value: &ip, Checks: loopback},
+			val:            "10.0.0.1",
+			errExpected:    true,
+			errMustContain: []string{"must be a loopback address"},
+		},
+		{
+			testName: "Addr: IPv6 with zone",
+			s:        psetter.AddrSetter{Value: &addr},
+			val:      "fe80::1%eth0",
+			expVal:   "fe80::1%eth0",
+		},
+		{
+			testName: "Addr: check passes",
+			s: psetter.AddrSetter{
+				Value: &addr,
+				Checks: []check.Addr{
+					check.AddrInPrefix(netip.MustParsePrefix("10.0.0.0/8")),
+				},
+			},
+			val:    "10.9.8.7",
+			expVal: "10.9.8.7",
+		},
+		{
+			testName:       "Addr: bad",
+			s:              psetter.AddrSetter{Value: &addr},
+			val:            "example.com",
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'example.com'"},
+		},
+	}
+
+	for i, tc := range testCases {
+		checkNetSetter(t, i, tc)
+	}
+}
+
+func TestPrefixSetter(t *testing.T) {
+	var p netip.Prefix
+
+	testCases := []netSetterTC{
+		{
+			testName: "IPv4",
+			s:        psetter.PrefixSetter{Value: &p},
+			val:      "10.1.2.3/8",
+			expVal:   "10.1.2.3/8",
+		},
+		{
+			testName: "IPv4 masked",
+			s:        psetter.PrefixSetter{Value: &p, Masked: true},
+			val:      "10.1.2.3/8",
+			expVal:   "10.0.0.0/8",
+		},
+		{
+			testName: "IPv6",
+			s:        psetter.PrefixSetter{Value: &p},
+			val:      "2001:db8::/32",
+			expVal:   "2001:db8::/32",
+		},
+		{
+			testName:       "no length",
+			s:              psetter.PrefixSetter{Value: &p},
+			val:            "10.0.0.0",
+			errExpected:    true,
+			errMustContain: []string{"could not parse '10.0.0.0' as a CIDR"},
+		},
+		{
+			testName: "check fails",
+			s: psetter.PrefixSetter{
+				Value: &p,
+				Checks: []check.Prefix{
+					check.PrefixWithin(netip.MustParsePrefix("10.0.0.0/8")),
+				},
+			},
+			val:            "192.168.0.0/16",
+			errExpected:    true,
+			errMustContain: []string{"must be within 10.0.0.0/8"},
+		},
+	}
+
+	for i, tc := range testCases {
+		checkNetSetter(t, i, tc)
+	}
+}
+
+func TestHostPortSetter(t *testing.T) {
+	var hp string
+
+	testCases := []netSetterTC{
+		{
+			testName: "host and port",
+			s:        psetter.HostPortSetter{Value: &hp},
+			val:      "example.com:8080",
+			expVal:   "example.com:8080",
+		},
+		{
+			testName: "empty host",
+			s:        psetter.HostPortSetter{Value: &hp},
+			val:      ":8080",
+			expVal:   ":8080",
+		},
+		{
+			testName: "IPv6 and port",
+			s:        psetter.HostPortSetter{Value: &hp},
+			val:      "[::1]:8080",
+			expVal:   "[::1]:8080",
+		},
+		{
+			testName:       "no port and no default",
+			s:              psetter.HostPortSetter{Value: &hp},
+			val:            "example.com",
+			errExpected:    true,
+			errMustContain: []string{"missing port"},
+		},
+		{
+			testName: "default port",
+			s:        psetter.HostPortSetter{Value: &hp, DfltPort: 443},
+			val:      "example.com",
+			expVal:   "example.com:443",
+		},
+		{
+			testName: "default port with IPv6",
+			s:        psetter.HostPortSetter{Value: &hp, DfltPort: 443},
+			val:      "[2001:db8::1]",
+			expVal:   "[2001:db8::1]:443",
+		},
+		{
+			testName:    "default port with unbracketed IPv6",
+			s:           psetter.HostPortSetter{Value: &hp, DfltPort: 443},
+			val:         "2001:db8::1",
+			errExpected: true,
+		},
+		{
+			testName:       "bad port",
+			s:              psetter.HostPortSetter{Value: &hp},
+			val:            "example.com:http",
+			errExpected:    true,
+			errMustContain: []string{"must be a number between 0 and 65535"},
+		},
+		{
+			testName:       "port too big",
+			s:              psetter.HostPortSetter{Value: &hp},
+			val:            "example.com:65536",
+			errExpected:    true,
+			errMustContain: []string{"the port (65536)"},
+		},
+		{
+			testName: "check fails",
+			s: psetter.HostPortSetter{
+				Value:    &hp,
+				DfltPort: 80,
+				Checks: []check.String{
+					check.StringMatchesPattern(
+						regexp.MustCompile(`^localhost:`), "localhost"),
+				},
+			},
+			val:            "example.com",
+			errExpected:    true,
+			errMustContain: []string{"example.com:80 does not match"},
+		},
+	}
+
+	for i, tc := range testCases {
+		checkNetSetter(t, i, tc)
+	}
+}
+
+func TestURLSetter(t *testing.T) {
+	var u *url.URL
+	web := []string{"http", "https"}
+
+	testCases := []netSetterTC{
+		{
+			testName: "good",
+			s:        psetter.URLSetter{Value: &u},
+			val:      "https://example.com/a?b=c",
+			expVal:   "https://example.com/a?b=c",
+		},
+		{
+			testName:       "relative",
+			s:              psetter.URLSetter{Value: &u},
+			val:            "/a/b",
+			errExpected:    true,
+			errMustContain: []string{"the URL (/a/b) has no scheme"},
+		},
+		{
+			testName:       "unparseable",
+			s:              psetter.URLSetter{Value: &u},
+			val:            "http://[::1",
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'http://[::1' as a URL"},
+		},
+		{
+			testName: "allowed scheme",
+			s:        psetter.URLSetter{Value: &u, Schemes: web},
+			val:      "HTTP://example.com",
+			expVal:   "http://example.com",
+		},
+		{
+			testName:       "disallowed scheme",
+			s:              psetter.URLSetter{Value: &u, Schemes: web},
+			val:            "ftp://example.com",
+			errExpected:    true,
+			errMustContain: []string{"must be one of: http, https"},
+		},
+		{
+			testName: "check fails",
+			s: psetter.URLSetter{
+				Value: &u,
+				Checks: []check.URL{
+					check.URLHostMatches(
+						regexp.MustCompile(`^internal\.`), "an internal host"),
+				},
+			},
+			val:            "https://example.com",
+			errExpected:    true,
+			errMustContain: []string{"does not match the pattern"},
+		},
+	}
+
+	for i, tc := range testCases {
+		checkNetSetter(t, i, tc)
+	}
+}
@@ -0,0 +1,87 @@
+package psetter
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"strconv"
+)
+
+// OptInt64Setter allows you to specify a parameter that can be used to set
+// an int64 value where the value need not be given. If the parameter is
+// given without a value, for instance "-level", the Value is set to the
+// Dflt; if a value is given, for instance "-level=3", the Value is set to
+// that. Note that, as the value is optional, it must be joined to the
+// parameter with an '=' on the command line.
+//
+// You can also supply check functions that will validate the Value; they
+// are applied to the Dflt as well as to any explicit value.
+type OptInt64Setter struct {
+	Value  *int64
+	Dflt   int64
+	Checks []check.Int64
+}
+
+// ValueReq returns param.Optional indicating that the parameter may have
+// but need not have a following value
+func (s OptInt64Setter) ValueReq() param.ValueReq { return param.Optional }
+
+// Set (called when there is no following value) sets the Value to the Dflt
+// if the checks are not violated
+func (s OptInt64Setter) Set(_ string) error {
+	return s.setChecked(s.Dflt)
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to an integer, if it cannot be parsed successfully it
+// returns an error. If there are checks and any check is violated it returns
+// an error. Only if the value is parsed successfully and no checks are
+// violated is the Value set.
+func (s OptInt64Setter) SetWithVal(_ string, paramVal string) error {
+	v, err := strconv.ParseInt(paramVal, 0, 0)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as an integer value: %s",
+			paramVal, err)
+	}
+	return s.setChecked(v)
+}
+
+// setChecked sets the Value to v if none of the checks are violated
+func (s OptInt64Setter) setChecked(v int64) error {
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s OptInt64Setter) AllowedValues() string {
+	rval := fmt.Sprintf("none (which will be taken as %d)", s.Dflt) +
+		" or any value that can be read as a whole number"
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s OptInt64Setter) CurrentValue() string {
+	return fmt.Sprintf("%v", *s.Value)
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil.
+func (s OptInt64Setter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": OptInt64Setter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,61 @@
+package psetter
+
+import (
+	"github.com/nickwells/golem/check"
+	"strings"
+)
+
+// editPatternList returns the list of patterns resulting from applying the
+// list edit given in the paramVal to the current list. Patterns to be
+// removed are identified by the string they were compiled from. Each
+// pattern in the resulting list must pass all the checks.
+func editPatternList[T check.StringMatcher](le ListEdit, sep string,
+	current []T, paramVal string,
+	compile func(string) (T, error), checks []check.Pattern) ([]T, error) {
+	op, paramVal, hasVals, err := le.editOp(paramVal)
+	if err != nil {
+		return nil, err
+	}
+	vals := []string{}
+	if hasVals {
+		vals = strings.Split(paramVal, sep)
+	}
+
+	curVals := make([]string, 0, len(current))
+	for _, p := range current {
+		curVals = append(curVals, p.String())
+	}
+	newVals, err := applyListEdit(op, curVals, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	pats := make([]T, 0, len(newVals))
+	for _, v := range newVals {
+		p, err := compile(v)
+		if err != nil {
+			return nil, err
+		}
+		for _, check := range checks {
+			if check == nil {
+				continue
+			}
+
+			err := check(p)
+			if err != nil {
+				return nil, err
+			}
+		}
+		pats = append(pats, p)
+	}
+	return pats, nil
+}
+
+// patternListString returns the patterns joined by the separator
+func patternListString[T check.StringMatcher](pats []T, sep string) string {
+	strs := make([]string, 0, len(pats))
+	for _, p := range pats {
+		strs = append(strs, p.String())
+	}
+	return strings.Join(strs, sep)
+}
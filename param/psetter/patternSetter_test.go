@@ -0,0 +1,247 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"regexp"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{pattern: "*.go", name: "a.go", match: true},
+		{pattern: "*.go", name: "x/a.go", match: false},
+		{pattern: "src/*/a.go", name: "src/x/a.go", match: true},
+		{pattern: "src/**/*.go", name: "src/a.go", match: true},
+		{pattern: "src/**/*.go", name: "src/x/y/a.go", match: true},
+		{pattern: "src/**/*.go", name: "lib/x/a.go", match: false},
+		{pattern: "**", name: "a/b/c", match: true},
+		{pattern: "**/**/a", name: "a", match: true},
+		{pattern: "**/test/*", name: "x/test", match: false},
+		{pattern: "a?[0-9]", name: "ab1", match: true},
+		{pattern: "a?[0-9]", name: "ab", match: false},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s ~ %s", i, tc.pattern, tc.name)
+		g, err := psetter.CompileGlob(tc.pattern)
+		if err != nil {
+			t.Log(testID)
+			t.Errorf("\t: unexpected error: %s", err)
+			continue
+		}
+		if g.MatchString(tc.name) != tc.match {
+			t.Log(testID)
+			t.Errorf("\t: MatchString should have returned %v", tc.match)
+		}
+		if g.String() != tc.pattern {
+			t.Log(testID)
+			t.Errorf("\t: String should return the pattern, not %q", g)
+		}
+	}
+
+	_, err := psetter.CompileGlob("src/[a-/*.go")
+	if err == nil {
+		t.Errorf("a malformed pattern should give an error")
+	} else {
+		testhelper.ShouldContain(t, "malformed glob", "error", err.Error(),
+			[]string{"bad glob pattern 'src/[a-/*.go'"})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	include := psetter.Patterns([]psetter.Glob{
+		psetter.MustCompileGlob("**/*.go"),
+	})
+	exclude := psetter.Patterns([]*regexp.Regexp{
+		regexp.MustCompile(`_test\.go$`),
+		regexp.MustCompile(`^vendor/`),
+	})
+
+	testCases := []struct {
+		name    string
+		m       psetter.Matcher
+		val     string
+		matches bool
+	}{
+		{
+			name:    "no patterns",
+			m:       psetter.Matcher{},
+			val:     "anything",
+			matches: true,
+		},
+		{
+			name:    "exclude only",
+			m:       psetter.Matcher{Exclude: exclude},
+			val:     "vendor/x.go",
+			matches: false,
+		},
+		{
+			name:    "exclude only - not excluded",
+			m:       psetter.Matcher{Exclude: exclude},
+			val:     "README",
+			matches: true,
+		},
+		{
+			name:    "include only - not included",
+			m:       psetter.Matcher{Include: include},
+			val:     "README",
+			matches: false,
+		},
+		{
+			name:    "both - exclude first",
+			m:       psetter.Matcher{Include: include, Exclude: exclude},
+			val:     "x/a_test.go",
+			matches: false,
+		},
+		{
+			name: "both - include first",
+			m: psetter.Matcher{
+				Include:    include,
+				Exclude:    exclude,
+				Precedence: psetter.IncludeFirst,
+			},
+			val:     "x/a_test.go",
+			matches: true,
+		},
+		{
+			name: "include first - excluded",
+			m: psetter.Matcher{
+				Include:    include,
+				Exclude:    exclude,
+				Precedence: psetter.IncludeFirst,
+			},
+			val:     "vendor/README",
+			matches: false,
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		if tc.m.Matches(tc.val) != tc.matches {
+			t.Log(testID)
+			t.Errorf("\t: Matches(%q) should have returned %v",
+				tc.val, tc.matches)
+		}
+	}
+
+	if !exclude.MatchAny("vendor/a_test.go") || exclude.MatchAny("a.go") {
+		t.Errorf("MatchAny gave an unexpected result")
+	}
+	if !exclude.MatchAll("vendor/a_test.go") || exclude.MatchAll("a_test.go") {
+		t.Errorf("MatchAll gave an unexpected result")
+	}
+	if (psetter.PatternList{}).MatchAny("a") ||
+		!(psetter.PatternList{}).MatchAll("a") {
+		t.Errorf("an empty list should match all but not any")
+	}
+}
+
+func TestPatternListSetters(t *testing.T) {
+	testListEdits(t, "RegexpListSetter",
+		func() param.Setter {
+			var v []*regexp.Regexp
+			return psetter.RegexpListSetter{
+				Value:            &v,
+				StrListSeparator: psetter.StrListSeparator{Sep: " "},
+				ListEdit: psetter.ListEdit{
+					Append:       true,
+					EditPrefixes: true,
+				},
+				Checks: []check.Pattern{
+					check.PatternNotMatchEmpty(),
+				},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "append and remove",
+				vals:     []string{"a{1,3}", "^b+ c", "-=a{1,3}"},
+				expVals:  []string{"a{1,3}", "a{1,3} ^b+ c", "^b+ c"},
+			},
+			{
+				testName:       "bad pattern",
+				vals:           []string{"a", "b("},
+				expVals:        []string{"a"},
+				errExpected:    true,
+				errMustContain: []string{"could not parse 'b('"},
+			},
+			{
+				testName:       "check fails",
+				vals:           []string{"x*"},
+				errExpected:    true,
+				errMustContain: []string{"must not match the empty string"},
+			},
+		})
+
+	testListEdits(t, "GlobListSetter",
+		func() param.Setter {
+			var v []psetter.Glob
+			return psetter.GlobListSetter{
+				Value:    &v,
+				ListEdit: psetter.ListEdit{EditPrefixes: true},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "replace, add and remove",
+				vals:     []string{"*.go,*.md", "+=**/*.c", "-=*.md"},
+				expVals: []string{
+					"*.go,*.md", "*.go,*.md,**/*.c", "*.go,**/*.c",
+				},
+			},
+			{
+				testName:       "remove an absent pattern",
+				vals:           []string{"*.go", "-=*.c"},
+				expVals:        []string{"*.go"},
+				errExpected:    true,
+				errMustContain: []string{"cannot remove '*.c'"},
+			},
+		})
+	testListEdits(t, "RegexpListSetter (no EditPrefixes)",
+		func() param.Setter {
+			var v []*regexp.Regexp
+			return psetter.RegexpListSetter{
+				Value:    &v,
+				ListEdit: psetter.ListEdit{Append: true},
+			}
+		},
+		[]editTestCase{
+			{
+				testName: "prefixes are part of the pattern",
+				vals:     []string{"a", "-=a", "=b"},
+				expVals:  []string{"a", "a,-=a", "a,-=a,=b"},
+			},
+		})
+
+	testListEdits(t, "GlobListSetter (no EditPrefixes)",
+		func() param.Setter {
+			var v []psetter.Glob
+			return psetter.GlobListSetter{Value: &v}
+		},
+		[]editTestCase{
+			{
+				testName: "prefixes are part of the pattern",
+				vals:     []string{"+=*.go"},
+				expVals:  []string{"+=*.go"},
+			},
+		})
+
+	var res []*regexp.Regexp
+	var globs []psetter.Glob
+	for _, s := range []param.Setter{
+		psetter.RegexpListSetter{Value: &res},
+		psetter.GlobListSetter{Value: &globs},
+	} {
+		if le, ok := s.(param.ListEditor); !ok || le.EditsList() {
+			t.Errorf("a %T without EditPrefixes should not edit lists", s)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"net/netip"
+)
+
+// PrefixSetter allows you to specify a parameter that can be used to set a
+// netip.Prefix value from a CIDR block such as 10.0.0.0/8. If Masked is
+// set then any bits of the address beyond the prefix length are cleared so
+// that, for instance, 10.1.2.3/8 gives 10.0.0.0/8. You can also supply check
+// functions that will validate the Value.
+type PrefixSetter struct {
+	Value  *netip.Prefix
+	Masked bool
+	Checks []check.Prefix
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s PrefixSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s PrefixSetter) Set(_ string) error {
+	return errors.New("no CIDR block given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to a prefix, if it cannot be parsed successfully it
+// returns an error. If there is a check and the check is violated it
+// returns an error. Only if the value is parsed successfully and the check
+// is not violated is the Value set.
+func (s PrefixSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := netip.ParsePrefix(paramVal)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a CIDR block: %s",
+			paramVal, err)
+	}
+	if s.Masked {
+		v = v.Masked()
+	}
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s PrefixSetter) AllowedValues() string {
+	rval := "a CIDR block: an IP address followed by a '/' and the" +
+		" number of bits in the prefix (such as 192.0.2.0/24 or 2001:db8::/32)"
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	if s.Masked {
+		rval += ". Any address bits beyond the prefix are cleared"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s PrefixSetter) CurrentValue() string {
+	return s.Value.String()
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s PrefixSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": PrefixSetter Check failed: the Value to be set is nil")
+	}
+}
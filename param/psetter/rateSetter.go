@@ -0,0 +1,106 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"strings"
+)
+
+// RateSetter allows you to specify a parameter that can be used to set a
+// float64 value holding a rate per second. The value is given as a
+// quantity, a '/' and a unit of time, for instance "100/s", "5MB/s" or
+// "30/min" - see RateTimeUnits for the units of time. The quantity is a
+// number optionally followed by one of the Units. If Units is nil then the
+// quantity is taken as a number of bytes and the SizeUnits are used; for a
+// rate of events set the Units to CountUnits.
+//
+// You can also supply check functions that will validate the Value; they
+// are given the rate per second.
+type RateSetter struct {
+	Value  *float64
+	Units  []Unit
+	Checks []check.Float64
+}
+
+// units returns the units for the quantity
+func (s RateSetter) units() []Unit {
+	if s.Units == nil {
+		return SizeUnits
+	}
+	return s.Units
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s RateSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s RateSetter) Set(_ string) error {
+	return errors.New("no rate given (it should be followed by '=num/unit')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to a rate, if it cannot be parsed successfully it
+// returns an error. If there is a check and the check is violated it
+// returns an error. Only if the value is parsed successfully and the check
+// is not violated is the Value set.
+func (s RateSetter) SetWithVal(_ string, paramVal string) error {
+	parts := strings.SplitN(paramVal, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("could not parse '%s' as a rate:"+
+			" it must be of the form quantity/time-unit", paramVal)
+	}
+	q, err := parseWithUnits(parts[0], s.units())
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a rate: %s",
+			paramVal, err)
+	}
+	tu, err := findUnit(strings.TrimSpace(parts[1]), RateTimeUnits)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a rate: %s",
+			paramVal, err)
+	}
+	v := q / tu.Factor
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s RateSetter) AllowedValues() string {
+	rval := "a rate given as a number optionally followed by one of the" +
+		" units: " + describeUnits(s.units()) +
+		" then a '/' and one of the units of time: " +
+		describeUnits(RateTimeUnits)
+	if len(s.Checks) != 0 {
+		rval += ". The value is subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value as a
+// rate per second
+func (s RateSetter) CurrentValue() string {
+	return formatWithUnits(*s.Value, s.units()) + "/s"
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s RateSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": RateSetter Check failed: the Value to be set is nil")
+	}
+}
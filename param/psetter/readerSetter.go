@@ -0,0 +1,170 @@
+package psetter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/filecheck"
+	"github.com/nickwells/golem/fileparser"
+	"github.com/nickwells/golem/param"
+	"io"
+	"os"
+)
+
+// StdStreamName is the name which can be given to a ReaderSetter, a
+// WriterSetter or a FileContentSetter in place of a pathname to use the
+// standard input or output
+const StdStreamName = "-"
+
+// namedReadCloser is the io.ReadCloser set by the ReaderSetter. It records
+// the name it was opened with and the things to be closed
+type namedReadCloser struct {
+	io.Reader
+	name    string
+	closers []io.Closer
+}
+
+// Name returns the name of the reader
+func (nrc *namedReadCloser) Name() string { return nrc.name }
+
+// Close closes the reader, returning the first error
+func (nrc *namedReadCloser) Close() error {
+	var rval error
+	for _, c := range nrc.closers {
+		if err := c.Close(); err != nil && rval == nil {
+			rval = err
+		}
+	}
+	nrc.closers = nil
+	return rval
+}
+
+// ReaderSetter allows you to specify a parameter giving a file to be read
+// from. The Value is set to an io.ReadCloser opened on the named file or,
+// if the name is StdStreamName ("-"), on the standard input; closing the
+// standard input reader does not close os.Stdin. Before the file is opened
+// it is checked against the Expectation.
+//
+// If DetectGzip is set then a file starting with the gzip magic number is
+// decompressed as it is read.
+//
+// If the Value already holds a reader when a new value is set the old
+// reader is closed. The ReaderSetter is a param.Cleaner and so the reader
+// is closed by the ParamSet Cleanup method.
+type ReaderSetter struct {
+	Value       *io.ReadCloser
+	Expectation filecheck.ExpectedStatus
+	DetectGzip  bool
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s ReaderSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s ReaderSetter) Set(_ string) error {
+	return errors.New("no pathname given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks the named
+// file against the Expectation and opens it. Only if the file is opened
+// successfully is the Value set.
+func (s ReaderSetter) SetWithVal(_ string, paramVal string) error {
+	rc := &namedReadCloser{name: paramVal}
+	var r io.Reader
+	if paramVal == StdStreamName {
+		rc.name = os.Stdin.Name()
+		r = os.Stdin
+	} else {
+		pathname, err := fileparser.FixFileName(paramVal)
+		if err != nil {
+			return err
+		}
+		err = s.Expectation.StatusCheck(pathname)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(pathname)
+		if err != nil {
+			return err
+		}
+		rc.name = pathname
+		rc.closers = append(rc.closers, f)
+		r = f
+	}
+
+	if s.DetectGzip {
+		br := bufio.NewReader(r)
+		r = br
+		magic, err := br.Peek(2)
+		if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+			gzr, err := gzip.NewReader(br)
+			if err != nil {
+				rc.Close()
+				return fmt.Errorf("cannot read '%s' as gzipped data: %s",
+					paramVal, err)
+			}
+			rc.closers = append([]io.Closer{gzr}, rc.closers...)
+			r = gzr
+		}
+	}
+	rc.Reader = r
+
+	err := s.Cleanup()
+	if err != nil {
+		rc.Close()
+		return err
+	}
+	*s.Value = rc
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s ReaderSetter) AllowedValues() string {
+	rval := "a pathname or '" + StdStreamName + "' for the standard input"
+
+	extras := s.Expectation.String()
+	if extras != "" {
+		rval += ". " + extras
+	}
+	if s.DetectGzip {
+		rval += ". Gzipped files will be decompressed"
+	}
+
+	return rval
+}
+
+// CurrentValue returns the name of the file being read
+func (s ReaderSetter) CurrentValue() string {
+	return streamName(*s.Value)
+}
+
+// Cleanup closes the reader, if any, and sets the Value to nil
+func (s ReaderSetter) Cleanup() error {
+	if *s.Value == nil {
+		return nil
+	}
+	err := (*s.Value).Close()
+	*s.Value = nil
+	return err
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil.
+func (s ReaderSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": ReaderSetter Check failed: the Value to be set is nil")
+	}
+}
+
+// streamName returns the name of the stream if it has one
+func streamName(v any) string {
+	if v == nil {
+		return "none"
+	}
+	if n, ok := v.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", v)
+}
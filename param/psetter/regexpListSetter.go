@@ -0,0 +1,91 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"regexp"
+)
+
+// RegexpListSetter allows you to specify a parameter that can be used to
+// set a list of regular expressions, for instance a list of patterns to
+// include or exclude. Note that the default list separator (a comma) is
+// also used in regular expressions (as in "a{1,3}") so you may want to
+// set a different separator.
+//
+// The Append field of the embedded ListEdit controls whether the patterns
+// replace the list or are added to it. If its EditPrefixes field is set
+// the value may also be given an edit prefix to add patterns to the list
+// or to remove them from it - see ListEdit for details. A pattern is
+// removed if it is the same as the pattern given.
+//
+// You can also supply check functions that each pattern must pass, for
+// instance check.PatternIsAnchored(). Use Patterns to convert the Value
+// into a PatternList for use in a Matcher.
+type RegexpListSetter struct {
+	Value *[]*regexp.Regexp
+	StrListSeparator
+	ListEdit
+	Checks []check.Pattern
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s RegexpListSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s RegexpListSetter) Set(_ string) error {
+	return errors.New("no pattern given (it should be followed by '=pattern')")
+}
+
+// SetWithVal (called when a value follows the parameter) splits the value
+// using the list separator and compiles each part as a regular expression.
+// It returns an error if any pattern cannot be compiled or fails a check
+// or if a pattern to be removed is not in the list; the Value is only
+// changed if there is no error.
+func (s RegexpListSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := editPatternList(s.ListEdit, s.GetSeparator(), *s.Value,
+		paramVal, compileRegexp, s.Checks)
+	if err != nil {
+		return err
+	}
+	*s.Value = v
+	return nil
+}
+
+// compileRegexp compiles the pattern, reporting any error in the same way
+// as the RegexpSetter
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil,
+			fmt.Errorf("could not parse '%s' to a regular expression: %s",
+				pattern, err)
+	}
+	return re, nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s RegexpListSetter) AllowedValues() string {
+	rval := "a list of regular expressions separated by '" +
+		s.GetSeparator() + "'"
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval + s.describeListEdit(s.EditPrefixes)
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s RegexpListSetter) CurrentValue() string {
+	return patternListString(*s.Value, s.GetSeparator())
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil.
+func (s RegexpListSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name +
+			": RegexpListSetter Check failed: the Value to be set is nil")
+	}
+}
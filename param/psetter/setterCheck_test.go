@@ -5,6 +5,10 @@ import (
 	"github.com/nickwells/golem/param"
 	"github.com/nickwells/golem/param/psetter"
 	"github.com/nickwells/golem/testhelper"
+	"io"
+	"net"
+	"net/netip"
+	"net/url"
 	"regexp"
 	"testing"
 	"time"
@@ -22,6 +26,25 @@ func TestSetterCheck(t *testing.T) {
 	var intList []int64
 	var re *regexp.Regexp
 	var timeLoc *time.Location
+	var tm, tmEnd time.Time
+	var strToStrMap = make(map[string]string)
+	var strToStrMapNil map[string]string
+	var strToIntMap = make(map[string]int64)
+	var ip net.IP
+	var addr netip.Addr
+	var prefix netip.Prefix
+	var u *url.URL
+	var tb psetter.TriBool
+	var rc io.ReadCloser
+	var wc io.WriteCloser
+	var content []byte
+	var reList []*regexp.Regexp
+	var globList []psetter.Glob
+	var vr param.ValueReq
+	vrVals := map[string]psetter.EnumVal[param.ValueReq]{
+		"must": {Val: param.Mandatory, Aliases: []string{"m"}},
+		"none": {Val: param.None, Aliases: []string{"n"}},
+	}
 
 	nilValueMsg := "Check failed: the Value to be set is nil"
 	noAllowedValsMsg := "Check failed: there are no allowed values"
@@ -290,6 +313,346 @@ func TestSetterCheck(t *testing.T) {
 			panicExpected: true,
 			expVals:       []string{"test: TimeLocationSetter " + nilValueMsg},
 		},
+		{
+			name:          "TimeSetter - ok",
+			s:             &psetter.TimeSetter{Value: &tm},
+			panicExpected: false,
+		},
+		{
+			name:          "TimeSetter - bad",
+			s:             &psetter.TimeSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: TimeSetter " + nilValueMsg},
+		},
+		{
+			name:          "DateRangeSetter - ok",
+			s:             &psetter.DateRangeSetter{Start: &tm, End: &tmEnd},
+			panicExpected: false,
+		},
+		{
+			name:          "DateRangeSetter - bad - no start",
+			s:             &psetter.DateRangeSetter{End: &tmEnd},
+			panicExpected: true,
+			expVals: []string{"test: DateRangeSetter" +
+				" Check failed: the Start value to be set is nil"},
+		},
+		{
+			name:          "DateRangeSetter - bad - no end",
+			s:             &psetter.DateRangeSetter{Start: &tm},
+			panicExpected: true,
+			expVals: []string{"test: DateRangeSetter" +
+				" Check failed: the End value to be set is nil"},
+		},
+		{
+			name:          "IPSetter - ok",
+			s:             &psetter.IPSetter{Value: &ip},
+			panicExpected: false,
+		},
+		{
+			name:          "IPSetter - bad",
+			s:             &psetter.IPSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: IPSetter " + nilValueMsg},
+		},
+		{
+			name:          "AddrSetter - ok",
+			s:             &psetter.AddrSetter{Value: &addr},
+			panicExpected: false,
+		},
+		{
+			name:          "AddrSetter - bad",
+			s:             &psetter.AddrSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: AddrSetter " + nilValueMsg},
+		},
+		{
+			name:          "PrefixSetter - ok",
+			s:             &psetter.PrefixSetter{Value: &prefix},
+			panicExpected: false,
+		},
+		{
+			name:          "PrefixSetter - bad",
+			s:             &psetter.PrefixSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: PrefixSetter " + nilValueMsg},
+		},
+		{
+			name:          "HostPortSetter - ok",
+			s:             &psetter.HostPortSetter{Value: &str, DfltPort: 80},
+			panicExpected: false,
+		},
+		{
+			name:          "HostPortSetter - bad",
+			s:             &psetter.HostPortSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: HostPortSetter " + nilValueMsg},
+		},
+		{
+			name:          "HostPortSetter - bad - default port",
+			s:             &psetter.HostPortSetter{Value: &str, DfltPort: 70000},
+			panicExpected: true,
+			expVals: []string{"test: HostPortSetter Check failed:" +
+				" the default port (70000) must be between 0 and 65535"},
+		},
+		{
+			name:          "URLSetter - ok",
+			s:             &psetter.URLSetter{Value: &u},
+			panicExpected: false,
+		},
+		{
+			name:          "URLSetter - bad",
+			s:             &psetter.URLSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: URLSetter " + nilValueMsg},
+		},
+		{
+			name:          "SizeSetter - ok",
+			s:             &psetter.SizeSetter{Value: &i},
+			panicExpected: false,
+		},
+		{
+			name:          "SizeSetter - bad",
+			s:             &psetter.SizeSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: SizeSetter " + nilValueMsg},
+		},
+		{
+			name:          "RateSetter - ok",
+			s:             &psetter.RateSetter{Value: &f},
+			panicExpected: false,
+		},
+		{
+			name:          "RateSetter - bad",
+			s:             &psetter.RateSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: RateSetter " + nilValueMsg},
+		},
+		{
+			name: "UnitFloat64Setter - ok",
+			s: &psetter.UnitFloat64Setter{
+				Value: &f,
+				Units: psetter.CountUnits,
+			},
+			panicExpected: false,
+		},
+		{
+			name: "UnitFloat64Setter - bad",
+			s: &psetter.UnitFloat64Setter{
+				Units: psetter.CountUnits,
+			},
+			panicExpected: true,
+			expVals:       []string{"test: UnitFloat64Setter " + nilValueMsg},
+		},
+		{
+			name:          "UnitFloat64Setter - bad - no units",
+			s:             &psetter.UnitFloat64Setter{Value: &f},
+			panicExpected: true,
+			expVals: []string{"test: UnitFloat64Setter" +
+				" Check failed: there are no units"},
+		},
+		{
+			name:          "StrMapSetter - ok",
+			s:             &psetter.StrMapSetter{Value: &strToStrMap},
+			panicExpected: false,
+		},
+		{
+			name:          "StrMapSetter - bad - no value",
+			s:             &psetter.StrMapSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: StrMapSetter " + nilValueMsg},
+		},
+		{
+			name:          "StrMapSetter - bad - nil map",
+			s:             &psetter.StrMapSetter{Value: &strToStrMapNil},
+			panicExpected: true,
+			expVals:       []string{"test: StrMapSetter " + mapNotCreatedMsg},
+		},
+		{
+			name: "StrMapSetter - bad - same separators",
+			s: &psetter.StrMapSetter{
+				Value:            &strToStrMap,
+				KVSep:            ":",
+				StrListSeparator: psetter.StrListSeparator{Sep: ":"},
+			},
+			panicExpected: true,
+			expVals: []string{"test: StrMapSetter Check failed:" +
+				" the key/value separator is the same as the list separator"},
+		},
+		{
+			name: "KVMapSetter - ok",
+			s: &psetter.KVMapSetter[int64]{
+				Value: &strToIntMap,
+				Parse: parseInt64,
+			},
+			panicExpected: false,
+		},
+		{
+			name:          "KVMapSetter - bad - no parser",
+			s:             &psetter.KVMapSetter[int64]{Value: &strToIntMap},
+			panicExpected: true,
+			expVals: []string{"test: KVMapSetter Check failed:" +
+				" there is no Parse func"},
+		},
+		{
+			name: "KVMapSetter - bad - no merge",
+			s: &psetter.KVMapSetter[int64]{
+				Value:   &strToIntMap,
+				Parse:   parseInt64,
+				DupKeys: psetter.DupKeyAccumulate,
+			},
+			panicExpected: true,
+			expVals: []string{"test: KVMapSetter Check failed:" +
+				" duplicate keys are accumulated but there is no Merge func"},
+		},
+		{
+			name:          "CounterSetter - ok",
+			s:             &psetter.CounterSetter{Value: &i, Max: 3},
+			panicExpected: false,
+		},
+		{
+			name:          "CounterSetter - bad",
+			s:             &psetter.CounterSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: CounterSetter " + nilValueMsg},
+		},
+		{
+			name:          "CounterSetter - bad - negative max",
+			s:             &psetter.CounterSetter{Value: &i, Max: -1},
+			panicExpected: true,
+			expVals: []string{"test: CounterSetter" +
+				" Check failed: the Max is negative"},
+		},
+		{
+			name:          "OptInt64Setter - ok",
+			s:             &psetter.OptInt64Setter{Value: &i},
+			panicExpected: false,
+		},
+		{
+			name:          "OptInt64Setter - bad",
+			s:             &psetter.OptInt64Setter{},
+			panicExpected: true,
+			expVals:       []string{"test: OptInt64Setter " + nilValueMsg},
+		},
+		{
+			name:          "TriBoolSetter - ok",
+			s:             &psetter.TriBoolSetter{Value: &tb},
+			panicExpected: false,
+		},
+		{
+			name:          "TriBoolSetter - bad",
+			s:             &psetter.TriBoolSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: TriBoolSetter " + nilValueMsg},
+		},
+		{
+			name:          "ReaderSetter - ok",
+			s:             &psetter.ReaderSetter{Value: &rc},
+			panicExpected: false,
+		},
+		{
+			name:          "ReaderSetter - bad",
+			s:             &psetter.ReaderSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: ReaderSetter " + nilValueMsg},
+		},
+		{
+			name:          "WriterSetter - ok",
+			s:             &psetter.WriterSetter{Value: &wc},
+			panicExpected: false,
+		},
+		{
+			name:          "WriterSetter - bad",
+			s:             &psetter.WriterSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: WriterSetter " + nilValueMsg},
+		},
+		{
+			name:          "WriterSetter - bad - bad mode",
+			s:             &psetter.WriterSetter{Value: &wc, Mode: 99},
+			panicExpected: true,
+			expVals:       []string{"test: WriterSetter Check failed: bad Mode"},
+		},
+		{
+			name:          "FileContentSetter - ok",
+			s:             &psetter.FileContentSetter[[]byte]{Value: &content},
+			panicExpected: false,
+		},
+		{
+			name:          "FileContentSetter - bad",
+			s:             &psetter.FileContentSetter[string]{},
+			panicExpected: true,
+			expVals:       []string{"test: FileContentSetter " + nilValueMsg},
+		},
+		{
+			name:          "RegexpListSetter - ok",
+			s:             &psetter.RegexpListSetter{Value: &reList},
+			panicExpected: false,
+		},
+		{
+			name:          "RegexpListSetter - bad",
+			s:             &psetter.RegexpListSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: RegexpListSetter " + nilValueMsg},
+		},
+		{
+			name:          "GlobListSetter - ok",
+			s:             &psetter.GlobListSetter{Value: &globList},
+			panicExpected: false,
+		},
+		{
+			name:          "GlobListSetter - bad",
+			s:             &psetter.GlobListSetter{},
+			panicExpected: true,
+			expVals:       []string{"test: GlobListSetter " + nilValueMsg},
+		},
+		{
+			name: "TypedEnumSetter - ok",
+			s: &psetter.TypedEnumSetter[param.ValueReq]{
+				Value:       &vr,
+				AllowedVals: vrVals,
+			},
+			panicExpected: false,
+		},
+		{
+			name: "TypedEnumSetter - bad",
+			s: &psetter.TypedEnumSetter[param.ValueReq]{
+				AllowedVals: vrVals,
+			},
+			panicExpected: true,
+			expVals:       []string{"test: TypedEnumSetter " + nilValueMsg},
+		},
+		{
+			name:          "TypedEnumSetter - bad - no allowed values",
+			s:             &psetter.TypedEnumSetter[param.ValueReq]{Value: &vr},
+			panicExpected: true,
+			expVals:       []string{"test: TypedEnumSetter " + noAllowedValsMsg},
+		},
+		{
+			name: "TypedEnumSetter - bad - alias is a name",
+			s: &psetter.TypedEnumSetter[param.ValueReq]{
+				Value: &vr,
+				AllowedVals: map[string]psetter.EnumVal[param.ValueReq]{
+					"must": {Val: param.Mandatory, Aliases: []string{"none"}},
+					"none": {Val: param.None},
+				},
+			},
+			panicExpected: true,
+			expVals: []string{"test: TypedEnumSetter Check failed:" +
+				" the alias 'none' of 'must' is also an allowed value"},
+		},
+		{
+			name: "TypedEnumSetter - bad - repeated alias",
+			s: &psetter.TypedEnumSetter[param.ValueReq]{
+				Value: &vr,
+				AllowedVals: map[string]psetter.EnumVal[param.ValueReq]{
+					"must": {Val: param.Mandatory, Aliases: []string{"x"}},
+					"none": {Val: param.None, Aliases: []string{"x"}},
+				},
+			},
+			panicExpected: true,
+			expVals: []string{"test: TypedEnumSetter Check failed:" +
+				" the alias 'x' is used for both 'must' and 'none'"},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -0,0 +1,96 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+)
+
+// SizeSetter allows you to specify a parameter that can be used to set an
+// int64 value holding a number of bytes. The value may have a suffix giving
+// an SI (kB, MB, GB, ...) or IEC (KiB, MiB, GiB, ...) multiplier, for
+// instance "10k", "64MiB" or "1.5G" - see SizeUnits for the full list. The
+// resulting number of bytes must be a whole number and must not be
+// negative.
+//
+// You can also supply check functions that will validate the Value; they
+// are given the number of bytes. For instance you can set a maximum size by
+// setting one of the Checks to the value returned by check.Int64LE(1<<30)
+type SizeSetter struct {
+	Value  *int64
+	Checks []check.Int64
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s SizeSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s SizeSetter) Set(_ string) error {
+	return errors.New("no size given (it should be followed by '=num')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to a size. The number is parsed exactly, so that a
+// value such as "1.001k" gives 1001 bytes; if it cannot be parsed
+// successfully, or it is not a whole number of bytes, if it cannot be parsed successfully it
+// returns an error. If there are checks and any check is violated it
+// returns an error. Only if the value is parsed successfully and no checks
+// are violated is the Value set.
+func (s SizeSetter) SetWithVal(_ string, paramVal string) error {
+	r, err := parseWithUnitsExact(paramVal, SizeUnits)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a size: %s", paramVal, err)
+	}
+	if r.Sign() < 0 {
+		return fmt.Errorf("the size (%s) must not be negative", paramVal)
+	}
+	if !r.IsInt() {
+		return fmt.Errorf("the size (%s) must be a whole number of bytes",
+			paramVal)
+	}
+	if !r.Num().IsInt64() {
+		return fmt.Errorf("the size (%s) is too large", paramVal)
+	}
+	v := r.Num().Int64()
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s SizeSetter) AllowedValues() string {
+	rval := "a number of bytes optionally followed by one of the units: " +
+		describeUnits(SizeUnits) +
+		". Units are matched ignoring case where this is unambiguous"
+	if len(s.Checks) != 0 {
+		rval += ". The value is subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value shown
+// in the largest unit that gives a short exact value
+func (s SizeSetter) CurrentValue() string {
+	return formatIntWithUnits(*s.Value, SizeUnits)
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s SizeSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": SizeSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -11,10 +11,17 @@ import (
 // list (a slice) of strings. You can override the list separator by setting
 // the Sep value.
 //
+// By default each use of the parameter replaces the list; if the Append
+// field of the embedded ListEdit is set then the values are added to the
+// list instead. If its EditPrefixes field is set the value may also be
+// given an edit prefix to add values to the list or to remove them from it
+// - see ListEdit for details.
+//
 // If you have a list of allowed values you should use EnumListSetter
 type StrListSetter struct {
 	Value *[]string
 	StrListSeparator
+	ListEdit
 	Checks []check.StringSlice
 }
 
@@ -28,11 +35,23 @@ func (s StrListSetter) Set(_ string) error {
 }
 
 // SetWithVal (called when a value follows the parameter) splits the value
-// into a slice of strings and sets the Value accordingly. It will return
-// an error if a check is breached.
+// into a slice of strings and edits the Value accordingly. It will return
+// an error if a check is breached by the resulting list or if a value to
+// be removed is not in the list; the Value is only changed if there is no
+// error.
 func (s StrListSetter) SetWithVal(_ string, paramVal string) error {
-	sep := s.GetSeparator()
-	v := strings.Split(paramVal, sep)
+	op, paramVal, hasVals, err := s.editOp(paramVal)
+	if err != nil {
+		return err
+	}
+	vals := []string{}
+	if hasVals {
+		vals = strings.Split(paramVal, s.GetSeparator())
+	}
+	v, err := applyListEdit(op, *s.Value, vals)
+	if err != nil {
+		return err
+	}
 
 	if len(s.Checks) != 0 {
 		for _, check := range s.Checks {
@@ -60,7 +79,7 @@ func (s StrListSetter) AllowedValues() string {
 	if len(s.Checks) != 0 {
 		rval += " subject to checks"
 	}
-	return rval
+	return rval + s.describeListEdit(s.EditPrefixes)
 }
 
 // CurrentValue returns the current setting of the parameter value
@@ -0,0 +1,102 @@
+package psetter
+
+import (
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"strconv"
+	"strings"
+)
+
+// StrMapSetter sets entries in a map of strings to strings from a list of
+// key=value pairs, for instance "k1=v1,k2=v2". This is suitable for
+// labels, headers or build tags. The value may be empty and may contain
+// the key/value separator; only the first separator in each entry is used.
+//
+// It behaves as a KVMapSetter of strings. If the DupKeys policy is
+// DupKeyAccumulate then the values given for a repeated key are joined
+// with the list separator, so "tag=a,tag=b" gives the key "tag" a value of
+// "a,b". The current value shows such values quoted, as in tag="a,b", so
+// that they can be told apart from separate entries.
+type StrMapSetter struct {
+	Value       *map[string]string
+	AllowedKeys AValMap
+	AValMatch
+	DupKeys     DupKeyPolicy
+	KeyChecks   []check.String
+	ValueChecks []check.String
+	// KVSep is the separator between the key and the value. If it is empty
+	// then KVDefaultSep is used
+	KVSep string
+	StrListSeparator
+}
+
+// kvMapSetter returns the equivalent KVMapSetter
+func (s StrMapSetter) kvMapSetter() KVMapSetter[string] {
+	valChecks := make([]func(string) error, 0, len(s.ValueChecks))
+	for _, c := range s.ValueChecks {
+		if c != nil {
+			valChecks = append(valChecks, c)
+		}
+	}
+	sep := s.GetSeparator()
+
+	return KVMapSetter[string]{
+		Value:       s.Value,
+		Parse:       func(v string) (string, error) { return v, nil },
+		Format:      func(v string) string { return quoteMapVal(v, sep) },
+		AllowedKeys: s.AllowedKeys,
+		AValMatch:   s.AValMatch,
+		DupKeys:     s.DupKeys,
+		Merge: func(earlier, later string) string {
+			return earlier + sep + later
+		},
+		KeyChecks:        s.KeyChecks,
+		ValueChecks:      valChecks,
+		KVSep:            s.KVSep,
+		StrListSeparator: s.StrListSeparator,
+	}
+}
+
+// quoteMapVal returns the value quoted (as by strconv.Quote) if it contains
+// the list separator or starts with a double quote, otherwise it returns
+// the value unchanged
+func quoteMapVal(v, sep string) string {
+	if strings.Contains(v, sep) || strings.HasPrefix(v, `"`) {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s StrMapSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s StrMapSetter) Set(paramName string) error {
+	return s.kvMapSetter().Set(paramName)
+}
+
+// SetWithVal (called when a value follows the parameter) splits the value
+// into entries using the list separator and each entry into a key and a
+// value. It checks all the keys and values for validity and only if they
+// are all valid does it set the entries in the map pointed to by the
+// Value. It returns an error for the first invalid entry.
+func (s StrMapSetter) SetWithVal(paramName string, paramVal string) error {
+	return s.kvMapSetter().SetWithVal(paramName, paramVal)
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s StrMapSetter) AllowedValues() string {
+	return s.kvMapSetter().AllowedValues()
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s StrMapSetter) CurrentValue() string {
+	return s.kvMapSetter().CurrentValue()
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or the map has not been created yet.
+func (s StrMapSetter) CheckSetter(name string) {
+	checkKVMapSetter(name, "StrMapSetter", s.kvMapSetter())
+}
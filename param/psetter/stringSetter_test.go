@@ -1,8 +1,11 @@
 package psetter_test
 
 import (
+	"fmt"
+	"github.com/nickwells/golem/check"
 	"github.com/nickwells/golem/param"
 	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
 	"testing"
 )
 
@@ -91,3 +94,69 @@ func TestStringListSetter(t *testing.T) {
 		}
 	}
 }
+
+func TestStringChecksInSetters(t *testing.T) {
+	var str string
+	var strList []string
+	var pathname string
+
+	setters := []struct {
+		name   string
+		s      param.Setter
+		good   string
+		bad    string
+		errMsg string
+	}{
+		{
+			name: "StringSetter",
+			s: psetter.StringSetter{
+				Value:  &str,
+				Checks: []check.String{check.StringIsHostname()},
+			},
+			good:   "www.example.com",
+			bad:    "www_example.com",
+			errMsg: "must be a valid hostname",
+		},
+		{
+			name: "StrListSetter",
+			s: psetter.StrListSetter{
+				Value: &strList,
+				Checks: []check.StringSlice{
+					check.StringSliceStringCheck(check.StringIsGoIdentifier()),
+				},
+			},
+			good:   "a,b_c",
+			bad:    "a,b-c",
+			errMsg: "must be a valid Go identifier",
+		},
+		{
+			name: "PathnameSetter",
+			s: psetter.PathnameSetter{
+				Value: &pathname,
+				Checks: []check.String{
+					check.StringNot(check.StringHasPrefix("/"),
+						"be an absolute path"),
+				},
+			},
+			good:   "a/b",
+			bad:    "/a/b",
+			errMsg: `the value ("/a/b") must not be an absolute path`,
+		},
+	}
+
+	for i, tc := range setters {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		if err := tc.s.SetWithVal("", tc.good); err != nil {
+			t.Log(testID)
+			t.Errorf("\t: unexpected error: %s", err)
+		}
+		err := tc.s.SetWithVal("", tc.bad)
+		if err == nil {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+			continue
+		}
+		testhelper.ShouldContain(t, testID, "error", err.Error(),
+			[]string{tc.errMsg})
+	}
+}
@@ -0,0 +1,238 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DfltTimeLayouts are the layouts that a TimeSetter or a DateRangeSetter
+// will try, in order, if no Layouts are given
+var DfltTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// timeParser holds the details needed to convert a string into a time
+type timeParser struct {
+	layouts []string
+	loc     *time.Location
+	now     func() time.Time
+}
+
+// newTimeParser returns a timeParser with any missing values replaced by
+// the defaults
+func newTimeParser(layouts []string, loc *time.Location, now func() time.Time) timeParser {
+	tp := timeParser{layouts: layouts, loc: loc, now: now}
+	if len(tp.layouts) == 0 {
+		tp.layouts = DfltTimeLayouts
+	}
+	if tp.loc == nil {
+		tp.loc = time.Local
+	}
+	if tp.now == nil {
+		tp.now = time.Now
+	}
+	return tp
+}
+
+// parse converts the value into a time. It first tries each of the layouts
+// in turn, a layout without a time zone being interpreted in the
+// timeParser's location, and then tries the value as a relative time.
+func (tp timeParser) parse(val string) (time.Time, error) {
+	val = strings.TrimSpace(val)
+	for _, l := range tp.layouts {
+		if t, err := time.ParseInLocation(l, val, tp.loc); err == nil {
+			return t, nil
+		}
+	}
+
+	t, ok, err := tp.parseRelative(val)
+	if err != nil {
+		return t, fmt.Errorf("could not parse '%s' as a time: %s", val, err)
+	}
+	if !ok {
+		return t, fmt.Errorf("could not parse '%s' as a time", val)
+	}
+	return t, nil
+}
+
+// parseRelative converts a value of the form "now", "today", "yesterday"
+// or "tomorrow" optionally followed by an offset into a time. The bool
+// returned is false if the value does not start with one of these words.
+// The word must be the whole of the value or be followed by the offset
+// (or by white space) so that, for instance, "nowhere" is not taken as
+// "now" with a bad offset.
+func (tp timeParser) parseRelative(val string) (time.Time, bool, error) {
+	now := tp.now().In(tp.loc)
+	y, m, d := now.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, tp.loc)
+
+	for _, rel := range []struct {
+		word string
+		base time.Time
+	}{
+		{"now", now},
+		{"today", midnight},
+		{"yesterday", midnight.AddDate(0, 0, -1)},
+		{"tomorrow", midnight.AddDate(0, 0, 1)},
+	} {
+		offset, ok := cutRelWord(val, rel.word)
+		if !ok {
+			continue
+		}
+		t, err := addOffset(rel.base, strings.TrimSpace(offset))
+		return t, true, err
+	}
+	return time.Time{}, false, nil
+}
+
+// cutRelWord returns the value with the word removed from its start and
+// true if the value starts with the word, ignoring case, and the word is
+// followed by the end of the value, a '+', a '-' or white space. Otherwise
+// it returns false.
+func cutRelWord(val, word string) (string, bool) {
+	if len(val) < len(word) || !strings.EqualFold(val[:len(word)], word) {
+		return "", false
+	}
+	rest := val[len(word):]
+	if rest == "" || rest[0] == '+' || rest[0] == '-' ||
+		unicode.IsSpace(rune(rest[0])) {
+		return rest, true
+	}
+	return "", false
+}
+
+// addOffset adds the offset to the time. The offset must be empty or else
+// start with a '+' or a '-'. The rest of the offset is either a whole
+// number of days followed by 'd' or else a duration as accepted by
+// time.ParseDuration. Days are added as calendar days so that the time of
+// day is kept across daylight saving changes.
+func addOffset(t time.Time, offset string) (time.Time, error) {
+	if offset == "" {
+		return t, nil
+	}
+	if offset[0] != '+' && offset[0] != '-' {
+		return t, fmt.Errorf("the offset (%s) must start with '+' or '-'",
+			offset)
+	}
+
+	if strings.HasSuffix(offset, "d") {
+		if days, err := strconv.Atoi(offset[:len(offset)-1]); err == nil {
+			return t.AddDate(0, 0, days), nil
+		}
+	}
+
+	d, err := time.ParseDuration(offset)
+	if err != nil {
+		return t, fmt.Errorf("bad offset: %s", err)
+	}
+	return t.Add(d), nil
+}
+
+// describe returns a string describing the values that the timeParser
+// will accept
+func (tp timeParser) describe() string {
+	return "a time in one of the layouts: " +
+		strings.Join(tp.layouts, ", ") +
+		" (interpreted in the " + tp.loc.String() +
+		" location if the layout has no time zone)" +
+		" or one of 'now', 'today', 'yesterday' or 'tomorrow'" +
+		" optionally followed by an offset such as '-2h', '+9h30m' or '-7d'"
+}
+
+// checkTime runs the checks against the time, returning the first error
+// found
+func checkTime(t time.Time, checks []check.Time) error {
+	for _, check := range checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TimeSetter allows you to specify a parameter that can be used to set a
+// time.Time value. The value can be given in any of the Layouts (or the
+// DfltTimeLayouts if no Layouts are given) or as a time relative to now
+// such as "now-2h" or "yesterday". Layouts which do not include a time
+// zone, and the relative times, are interpreted in the Loc location (or
+// the local time zone if Loc is nil). You can also supply check functions
+// that will validate the Value.
+type TimeSetter struct {
+	Value   *time.Time
+	Layouts []string
+	Loc     *time.Location
+	Checks  []check.Time
+	// Now gives the current time used for relative times. If it is nil
+	// then time.Now is used
+	Now func() time.Time
+}
+
+// parser returns the timeParser for the setter
+func (s TimeSetter) parser() timeParser {
+	return newTimeParser(s.Layouts, s.Loc, s.Now)
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s TimeSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s TimeSetter) Set(_ string) error {
+	return errors.New("no time given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to a time, if it cannot be parsed successfully it
+// returns an error. If there is a check and the check is violated it
+// returns an error. Only if the value is parsed successfully and the check
+// is not violated is the Value set.
+func (s TimeSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := s.parser().parse(paramVal)
+	if err != nil {
+		return err
+	}
+
+	if err := checkTime(v, s.Checks); err != nil {
+		return err
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s TimeSetter) AllowedValues() string {
+	rval := s.parser().describe()
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s TimeSetter) CurrentValue() string {
+	return s.Value.Format(s.parser().layouts[0])
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s TimeSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": TimeSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,306 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+	"time"
+)
+
+func TestTimeSetter(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("the America/New_York location is not available: ", err)
+	}
+	// a Sunday, shortly after the start of daylight saving time
+	now := time.Date(2019, time.March, 10, 15, 30, 0, 0, ny)
+	nowFunc := func() time.Time { return now }
+
+	testCases := []struct {
+		testName       string
+		val            string
+		layouts        []string
+		loc            *time.Location
+		checks         []check.Time
+		expTime        time.Time
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			testName: "RFC3339",
+			val:      "2019-03-01T09:00:00Z",
+			expTime:  time.Date(2019, time.March, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			testName: "date in the location",
+			val:      "2019-03-01",
+			expTime:  time.Date(2019, time.March, 1, 0, 0, 0, 0, ny),
+		},
+		{
+			testName: "date and time in a different location",
+			val:      "2019-03-01 09:30",
+			loc:      time.UTC,
+			expTime:  time.Date(2019, time.March, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			testName: "own layout",
+			val:      "01/03/2019",
+			layouts:  []string{"02/01/2006"},
+			expTime:  time.Date(2019, time.March, 1, 0, 0, 0, 0, ny),
+		},
+		{
+			testName:       "own layout - default layouts not used",
+			val:            "2019-03-01",
+			layouts:        []string{"02/01/2006"},
+			errExpected:    true,
+			errMustContain: []string{"could not parse '2019-03-01' as a time"},
+		},
+		{
+			testName: "now",
+			val:      "now",
+			expTime:  now,
+		},
+		{
+			testName: "now with an offset",
+			val:      "now-2h",
+			expTime:  now.Add(-2 * time.Hour),
+		},
+		{
+			testName: "today",
+			val:      "Today",
+			expTime:  time.Date(2019, time.March, 10, 0, 0, 0, 0, ny),
+		},
+		{
+			// a duration is elapsed time so the hour lost to daylight
+			// saving time is added to the clock time
+			testName: "today with an offset",
+			val:      "today+9h30m",
+			expTime:  time.Date(2019, time.March, 10, 10, 30, 0, 0, ny),
+		},
+		{
+			testName: "yesterday",
+			val:      "yesterday",
+			expTime:  time.Date(2019, time.March, 9, 0, 0, 0, 0, ny),
+		},
+		{
+			testName: "tomorrow",
+			val:      "tomorrow",
+			expTime:  time.Date(2019, time.March, 11, 0, 0, 0, 0, ny),
+		},
+		{
+			testName: "days across a daylight saving change",
+			val:      "now-2d",
+			expTime:  time.Date(2019, time.March, 8, 15, 30, 0, 0, ny),
+		},
+		{
+			testName: "relative in a different location",
+			val:      "today",
+			loc:      time.UTC,
+			expTime:  time.Date(2019, time.March, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			testName:       "bad offset",
+			val:            "now-2x",
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'now-2x'", "bad offset"},
+		},
+		{
+			testName:       "offset without a sign",
+			val:            "now 2h",
+			errExpected:    true,
+			errMustContain: []string{"must start with '+' or '-'"},
+		},
+		{
+			testName:       "relative word is not a whole token",
+			val:            "nowhere",
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'nowhere' as a time"},
+		},
+		{
+			testName:       "relative word is not a whole token - today",
+			val:            "todays",
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'todays' as a time"},
+		},
+		{
+			testName: "relative word - upper case with a spaced offset",
+			val:      "NOW +2h",
+			expTime:  now.Add(2 * time.Hour),
+		},
+		{
+			testName:       "nonsense",
+			val:            "blah",
+			errExpected:    true,
+			errMustContain: []string{"could not parse 'blah' as a time"},
+		},
+		{
+			testName: "check passes",
+			val:      "yesterday",
+			checks:   []check.Time{nil, check.TimeBefore(now)},
+			expTime:  time.Date(2019, time.March, 9, 0, 0, 0, 0, ny),
+		},
+		{
+			testName:       "check fails",
+			val:            "today",
+			checks:         []check.Time{check.TimeIsWeekday()},
+			errExpected:    true,
+			errMustContain: []string{"must be on a weekday"},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		loc := tc.loc
+		if loc == nil {
+			loc = ny
+		}
+		var v time.Time
+		s := psetter.TimeSetter{
+			Value:   &v,
+			Layouts: tc.layouts,
+			Loc:     loc,
+			Checks:  tc.checks,
+			Now:     nowFunc,
+		}
+
+		err := s.SetWithVal("", tc.val)
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: an unexpected error was returned"+
+					" when processing '%s': %s", tc.val, err)
+			} else {
+				testhelper.ShouldContain(t, testID, "error", err.Error(),
+					tc.errMustContain)
+			}
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected when processing '%s'"+
+				" but none was returned", tc.val)
+		} else if !v.Equal(tc.expTime) {
+			t.Log(testID)
+			t.Errorf("\t: the time was not as expected, got %v, expected %v",
+				v, tc.expTime)
+		}
+	}
+}
+
+func TestDateRangeSetter(t *testing.T) {
+	now := time.Date(2019, time.March, 14, 15, 30, 0, 0, time.UTC)
+	nowFunc := func() time.Time { return now }
+	dflt := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		testName       string
+		val            string
+		checks         []check.Time
+		expStart       time.Time
+		expEnd         time.Time
+		errExpected    bool
+		errMustContain []string
+	}{
+		{
+			testName: "dates",
+			val:      "2019-03-01..2019-03-08",
+			expStart: time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expEnd:   time.Date(2019, time.March, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			testName: "relative",
+			val:      "now-2h..now",
+			expStart: now.Add(-2 * time.Hour),
+			expEnd:   now,
+		},
+		{
+			testName: "mixed",
+			val:      "2019-03-01T12:00:00Z..today",
+			expStart: time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC),
+			expEnd:   time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			testName: "same start and end",
+			val:      "today..today",
+			expStart: time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC),
+			expEnd:   time.Date(2019, time.March, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			testName:       "no separator",
+			val:            "2019-03-01",
+			errExpected:    true,
+			errMustContain: []string{"must be of the form start..end"},
+		},
+		{
+			testName:       "start after end",
+			val:            "today..yesterday",
+			errExpected:    true,
+			errMustContain: []string{"is after the end"},
+		},
+		{
+			testName:    "bad start",
+			val:         "blah..today",
+			errExpected: true,
+			errMustContain: []string{
+				"bad start of the date range",
+				"could not parse 'blah'",
+			},
+		},
+		{
+			testName:    "bad end",
+			val:         "today..blah",
+			errExpected: true,
+			errMustContain: []string{
+				"bad end of the date range",
+				"could not parse 'blah'",
+			},
+		},
+		{
+			testName:    "check fails on the end",
+			val:         "yesterday..tomorrow",
+			checks:      []check.Time{check.TimeBefore(now)},
+			errExpected: true,
+			errMustContain: []string{
+				"bad end of the date range",
+				"must be before",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		start, end := dflt, dflt
+		s := psetter.DateRangeSetter{
+			Start:  &start,
+			End:    &end,
+			Loc:    time.UTC,
+			Checks: tc.checks,
+			Now:    nowFunc,
+		}
+
+		err := s.SetWithVal("", tc.val)
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: an unexpected error was returned"+
+					" when processing '%s': %s", tc.val, err)
+			} else {
+				testhelper.ShouldContain(t, testID, "error", err.Error(),
+					tc.errMustContain)
+				if !start.Equal(dflt) || !end.Equal(dflt) {
+					t.Log(testID)
+					t.Errorf("\t: the values should not change on error")
+				}
+			}
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected when processing '%s'"+
+				" but none was returned", tc.val)
+		} else if !start.Equal(tc.expStart) || !end.Equal(tc.expEnd) {
+			t.Log(testID)
+			t.Errorf("\t: the range was not as expected,"+
+				" got %v to %v, expected %v to %v",
+				start, end, tc.expStart, tc.expEnd)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package psetter
+
+import (
+	"github.com/nickwells/golem/param"
+	"strconv"
+)
+
+// TriBool holds a boolean value together with a record of whether it has
+// been set. The zero value is unset. This allows a program to distinguish
+// between a value explicitly chosen by the user and a default.
+type TriBool struct {
+	isSet bool
+	val   bool
+}
+
+// IsSet returns true if the value has been set
+func (tb TriBool) IsSet() bool { return tb.isSet }
+
+// Value returns the value and whether it has been set. If it has not been
+// set the value returned is false
+func (tb TriBool) Value() (val, isSet bool) { return tb.val, tb.isSet }
+
+// ValueOr returns the value if it has been set and the dflt otherwise
+func (tb TriBool) ValueOr(dflt bool) bool {
+	if !tb.isSet {
+		return dflt
+	}
+	return tb.val
+}
+
+// Set sets the value to v and records that it has been set
+func (tb *TriBool) Set(v bool) {
+	tb.isSet = true
+	tb.val = v
+}
+
+// Unset returns the TriBool to the unset state
+func (tb *TriBool) Unset() {
+	*tb = TriBool{}
+}
+
+// String returns "true" or "false" if the value has been set and "unset"
+// otherwise
+func (tb TriBool) String() string {
+	if !tb.isSet {
+		return "unset"
+	}
+	return strconv.FormatBool(tb.val)
+}
+
+// TriBoolSetter is used to set a TriBool. As with the BoolSetter, the
+// parameter given without a value sets the Value to true. Once the
+// parameter has been given the Value records that it has been set so a
+// program can tell whether the user has made an explicit choice.
+type TriBoolSetter struct {
+	Value *TriBool
+}
+
+// ValueReq returns param.Optional indicating that the parameter may have
+// but need not have a following value
+func (s TriBoolSetter) ValueReq() param.ValueReq { return param.Optional }
+
+// Set sets the parameter value to true
+func (s TriBoolSetter) Set(_ string) error {
+	s.Value.Set(true)
+	return nil
+}
+
+// SetWithVal should be called when a value is given for the parameter
+func (s TriBoolSetter) SetWithVal(_, val string) error {
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return err
+	}
+	s.Value.Set(b)
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values.
+func (s TriBoolSetter) AllowedValues() string {
+	return "none (which will be taken as 'true')" +
+		" or some value that can be interpreted as true or false." +
+		" If the parameter is not given the value is unset"
+}
+
+// CurrentValue returns the current setting of the parameter value: "true",
+// "false" or "unset"
+func (s TriBoolSetter) CurrentValue() string {
+	return s.Value.String()
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s TriBoolSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": TriBoolSetter Check failed: the Value to be set is nil")
+	}
+}
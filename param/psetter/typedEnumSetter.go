@@ -0,0 +1,201 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/param"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnumVal describes one of the allowed values of a TypedEnumSetter
+type EnumVal[T any] struct {
+	// Val is the value the Value is set to
+	Val T
+	// Desc describes the value
+	Desc string
+	// Aliases are alternative names which may be given for the value
+	Aliases []string
+	// Hidden, if set, means that the value is accepted but is not shown in
+	// the list of allowed values
+	Hidden bool
+	// Deprecated, if not empty, means that the value is accepted but it is
+	// shown as deprecated, with this text, in the list of allowed values,
+	// for instance "use 'fast' instead". A warning, with this text, is
+	// written when the value is used
+	Deprecated string
+}
+
+// StringerEnumVals returns a map of allowed values for a TypedEnumSetter
+// built from the given values. Each value is given the name returned by
+// its String method, as generated by the stringer tool, and the
+// description from the descs map (which may be nil). It panics if two
+// values have the same name.
+func StringerEnumVals[T interface {
+	comparable
+	fmt.Stringer
+}](vals []T, descs map[T]string) map[string]EnumVal[T] {
+	ev := make(map[string]EnumVal[T], len(vals))
+	for _, v := range vals {
+		name := v.String()
+		if _, ok := ev[name]; ok {
+			panic(fmt.Sprintf("StringerEnumVals: the name %q is repeated",
+				name))
+		}
+		ev[name] = EnumVal[T]{Val: v, Desc: descs[v]}
+	}
+	return ev
+}
+
+// TypedEnumSetter allows you to specify a parameter that will only allow
+// an enumerated range of values which are specified in the AllowedVals
+// map. This maps each allowed name to the value of type T to be set, for
+// instance one of a set of typed constants, so that, unlike the
+// EnumSetter, the program need not convert the name to a value itself.
+// The map can be built from a list of values with a String method using
+// StringerEnumVals. The AValMatch can be set to allow the case of the
+// value to be ignored or to allow a value to be abbreviated; aliases are
+// matched in the same way as names.
+//
+// If a deprecated value is given a warning is written to the
+// DeprecationWriter or, if that is nil, to the standard error.
+type TypedEnumSetter[T comparable] struct {
+	Value       *T
+	AllowedVals map[string]EnumVal[T]
+	AValMatch
+	DeprecationWriter io.Writer
+}
+
+// avals returns the AValMap of all the names and aliases which will be
+// accepted, the AValMap of the names to be shown and a map of each
+// accepted name or alias to the name of the allowed value
+func (s TypedEnumSetter[T]) avals() (all, shown AValMap, names map[string]string) {
+	all = make(AValMap)
+	shown = make(AValMap)
+	names = make(map[string]string)
+
+	for name, ev := range s.AllowedVals {
+		all[name] = ev.Desc
+		names[name] = name
+		for _, a := range ev.Aliases {
+			all[a] = ev.Desc
+			names[a] = name
+		}
+		if ev.Hidden {
+			continue
+		}
+
+		desc := ev.Desc
+		if len(ev.Aliases) != 0 {
+			desc += " (alias: " + strings.Join(ev.Aliases, ", ") + ")"
+		}
+		if ev.Deprecated != "" {
+			desc += " (deprecated: " + ev.Deprecated + ")"
+		}
+		shown[name] = desc
+	}
+	return all, shown, names
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s TypedEnumSetter[T]) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s TypedEnumSetter[T]) Set(_ string) error {
+	return errors.New("no value given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks the value
+// for validity and only if it matches one of the allowed names or aliases
+// does it set the Value to the corresponding value. It returns an error if
+// the value is invalid; the error will suggest any similar allowed values
+// but will not suggest hidden values. If the value is deprecated a warning
+// is written.
+func (s TypedEnumSetter[T]) SetWithVal(paramName string, paramVal string) error {
+	all, shown, names := s.avals()
+	name, err := s.matchAValShowing(paramVal, all, shown, names)
+	if err != nil {
+		return err
+	}
+	ev := s.AllowedVals[name]
+	if ev.Deprecated != "" {
+		w := s.DeprecationWriter
+		if w == nil {
+			w = os.Stderr
+		}
+		fmt.Fprintf(w, "Warning: %s: the value '%s' is deprecated: %s\n",
+			paramName, name, ev.Deprecated)
+	}
+	*s.Value = ev.Val
+	return nil
+}
+
+// AllowedValues returns a string listing the allowed values
+func (s TypedEnumSetter[T]) AllowedValues() string {
+	_, shown, _ := s.avals()
+	return "one of\n" + allowedValues(shown) + s.describe()
+}
+
+// CurrentValue returns the name of the current setting of the parameter
+// value. If more than one name has the value the first (in sorted order)
+// which is not hidden is used
+func (s TypedEnumSetter[T]) CurrentValue() string {
+	hiddenName := ""
+	for _, name := range sortedKeys(s.AllowedVals) {
+		ev := s.AllowedVals[name]
+		if ev.Val != *s.Value {
+			continue
+		}
+		if !ev.Hidden {
+			return name
+		}
+		if hiddenName == "" {
+			hiddenName = name
+		}
+	}
+	if hiddenName != "" {
+		return hiddenName
+	}
+	return fmt.Sprintf("%v", *s.Value)
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or there are no allowed values or if an alias is the same
+// as another name or alias.
+func (s TypedEnumSetter[T]) CheckSetter(name string) {
+	intro := name + ": TypedEnumSetter Check failed: "
+	if s.Value == nil {
+		panic(intro + "the Value to be set is nil")
+	}
+	if len(s.AllowedVals) == 0 {
+		panic(intro + "there are no allowed values")
+	}
+
+	aliasOf := make(map[string]string)
+	for _, n := range sortedKeys(s.AllowedVals) {
+		for _, a := range s.AllowedVals[n].Aliases {
+			if _, ok := s.AllowedVals[a]; ok {
+				panic(intro + "the alias '" + a + "' of '" + n +
+					"' is also an allowed value")
+			}
+			if other, ok := aliasOf[a]; ok {
+				panic(intro + "the alias '" + a + "' is used for both '" +
+					other + "' and '" + n + "'")
+			}
+			aliasOf[a] = n
+		}
+	}
+}
+
+// sortedKeys returns the keys of the map in sorted order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
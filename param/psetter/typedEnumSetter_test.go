@@ -0,0 +1,233 @@
+package psetter_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"strings"
+	"testing"
+)
+
+// speed is an enumerated type for testing the TypedEnumSetter
+type speed int
+
+const (
+	slow speed = iota
+	medium
+	fast
+)
+
+func TestTypedEnumSetter(t *testing.T) {
+	allowedVals := map[string]psetter.EnumVal[speed]{
+		"slow": {Val: slow, Desc: "take care"},
+		"medium": {
+			Val:     medium,
+			Desc:    "a balance",
+			Aliases: []string{"normal", "med"},
+		},
+		"steady": {Val: medium, Desc: "keep going"},
+		"fast":   {Val: fast, Desc: "hurry"},
+		"quick": {
+			Val:        fast,
+			Desc:       "hurry",
+			Deprecated: "use 'fast' instead",
+		},
+		"turbo":   {Val: fast, Hidden: true},
+		"stealth": {Val: slow, Hidden: true},
+	}
+
+	testCases := []struct {
+		testName       string
+		match          psetter.AValMatch
+		val            string
+		expVal         speed
+		expCV          string
+		expWarning     string
+		errExpected    bool
+		errMustContain []string
+		errMustNotShow string
+	}{
+		{
+			testName: "name",
+			val:      "slow",
+			expVal:   slow,
+			expCV:    "slow",
+		},
+		{
+			testName: "alias",
+			val:      "normal",
+			expVal:   medium,
+			expCV:    "medium",
+		},
+		{
+			testName:   "deprecated",
+			val:        "quick",
+			expVal:     fast,
+			expCV:      "fast",
+			expWarning: "'quick' is deprecated: use 'fast' instead",
+		},
+		{
+			testName:   "deprecated - prefix",
+			match:      psetter.AValMatch{AllowPrefix: true},
+			val:        "qu",
+			expVal:     fast,
+			expCV:      "fast",
+			expWarning: "'quick' is deprecated: use 'fast' instead",
+		},
+		{
+			testName: "hidden",
+			val:      "turbo",
+			expVal:   fast,
+			expCV:    "fast",
+		},
+		{
+			testName: "alias - ignore case and prefix",
+			match:    psetter.AValMatch{IgnoreCase: true, AllowPrefix: true},
+			val:      "NORM",
+			expVal:   medium,
+			expCV:    "medium",
+		},
+		{
+			testName: "prefix of a name and its alias",
+			match:    psetter.AValMatch{AllowPrefix: true},
+			val:      "me",
+			expVal:   medium,
+			expCV:    "medium",
+		},
+		{
+			testName:    "ambiguous prefix - hidden values are not listed",
+			match:       psetter.AValMatch{AllowPrefix: true},
+			val:         "s",
+			errExpected: true,
+			errMustContain: []string{
+				"ambiguous value: 's'",
+				"it could be any of: slow, steady",
+			},
+			errMustNotShow: "stealth",
+		},
+		{
+			testName:    "ambiguous prefix - only one value is shown",
+			match:       psetter.AValMatch{AllowPrefix: true},
+			val:         "ste",
+			errExpected: true,
+			errMustContain: []string{
+				"ambiguous value: 'ste'",
+				"it matches more than one allowed value",
+			},
+			errMustNotShow: "stealth",
+		},
+		{
+			testName:    "bad value - hidden values are not suggested",
+			val:         "turb",
+			errExpected: true,
+			errMustContain: []string{
+				"invalid value: 'turb'",
+				"fast, medium, quick, slow, steady",
+			},
+			errMustNotShow: "turbo",
+		},
+	}
+
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+		v := slow
+		var warning bytes.Buffer
+		s := psetter.TypedEnumSetter[speed]{
+			Value:             &v,
+			AllowedVals:       allowedVals,
+			AValMatch:         tc.match,
+			DeprecationWriter: &warning,
+		}
+		err := s.SetWithVal("speed", tc.val)
+		if tc.expWarning == "" {
+			if warning.Len() != 0 {
+				t.Log(testID)
+				t.Errorf("\t: unexpected warning: %s", warning.String())
+			}
+		} else {
+			testhelper.ShouldContain(t, testID, "warning", warning.String(),
+				[]string{"speed", tc.expWarning})
+		}
+		if err != nil {
+			if !tc.errExpected {
+				t.Log(testID)
+				t.Errorf("\t: unexpected error: %s", err)
+				continue
+			}
+			testhelper.ShouldContain(t, testID, "error", err.Error(),
+				tc.errMustContain)
+			if tc.errMustNotShow != "" &&
+				strings.Contains(err.Error(), tc.errMustNotShow) {
+				t.Log(testID)
+				t.Errorf("\t: the error should not show %q: %s",
+					tc.errMustNotShow, err)
+			}
+		} else if tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an error was expected but none was returned")
+		} else {
+			if v != tc.expVal {
+				t.Log(testID)
+				t.Errorf("\t: the value should be %d but is %d",
+					tc.expVal, v)
+			}
+			if cv := s.CurrentValue(); cv != tc.expCV {
+				t.Log(testID)
+				t.Errorf("\t: the current value should be %q but is %q",
+					tc.expCV, cv)
+			}
+		}
+	}
+
+	s := psetter.TypedEnumSetter[speed]{AllowedVals: allowedVals}
+	av := s.AllowedValues()
+	testhelper.ShouldContain(t, "AllowedValues", "allowed values", av,
+		[]string{
+			"a balance (alias: normal, med)",
+			"hurry (deprecated: use 'fast' instead)",
+		})
+	if strings.Contains(av, "turbo") {
+		t.Errorf("hidden values should not be shown: %s", av)
+	}
+}
+
+func TestStringerEnumVals(t *testing.T) {
+	var vr param.ValueReq
+	s := psetter.TypedEnumSetter[param.ValueReq]{
+		Value: &vr,
+		AllowedVals: psetter.StringerEnumVals(
+			[]param.ValueReq{param.Mandatory, param.Optional, param.None},
+			map[param.ValueReq]string{param.None: "no value allowed"}),
+	}
+
+	if err := s.SetWithVal("", "None"); err != nil {
+		t.Error("unexpected error: ", err)
+	} else if vr != param.None {
+		t.Errorf("the value should be None but is %s", vr)
+	}
+	if cv := s.CurrentValue(); cv != "None" {
+		t.Errorf("the current value should be None but is %q", cv)
+	}
+	testhelper.ShouldContain(t, "StringerEnumVals", "allowed values",
+		s.AllowedValues(), []string{"None     : no value allowed"})
+
+	panicked, panicVal := panicSafeStringerEnumVals(
+		[]param.ValueReq{param.None, param.None})
+	testhelper.PanicCheckString(t, "repeated value", panicked, true,
+		panicVal, []string{`the name "None" is repeated`})
+}
+
+// panicSafeStringerEnumVals calls StringerEnumVals with the values,
+// returning true if it panicked and the panic value
+func panicSafeStringerEnumVals(vals []param.ValueReq) (panicked bool, panicVal interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			panicVal = r
+		}
+	}()
+	psetter.StringerEnumVals(vals, nil)
+	return false, nil
+}
@@ -0,0 +1,93 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+)
+
+// UnitFloat64Setter allows you to specify a parameter that can be used to
+// set a float64 value given as a number followed by one of the Units, for
+// instance "2.5km" with a unit of "km" having a Factor of 1000. The Value
+// is set to the number multiplied by the Factor of the unit. If one of the
+// Units has an empty name (or an empty Alt) then the unit may be left out.
+//
+// Units are matched exactly or, if there is no exact match, ignoring case
+// provided that only one unit matches.
+//
+// You can also supply check functions that will validate the Value; they
+// are given the value after the Factor has been applied.
+type UnitFloat64Setter struct {
+	Value  *float64
+	Units  []Unit
+	Checks []check.Float64
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s UnitFloat64Setter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s UnitFloat64Setter) Set(_ string) error {
+	return errors.New("no value given (it should be followed by '=num unit')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to a number and a unit, if it cannot be parsed
+// successfully it returns an error. If there is a check and the check is
+// violated it returns an error. Only if the value is parsed successfully
+// and the check is not violated is the Value set.
+func (s UnitFloat64Setter) SetWithVal(_ string, paramVal string) error {
+	v, err := parseWithUnits(paramVal, s.Units)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a number with a unit: %s",
+			paramVal, err)
+	}
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s UnitFloat64Setter) AllowedValues() string {
+	rval := "a number followed by one of the units: " + describeUnits(s.Units)
+	if unitsOptional(s.Units) {
+		rval = "a number optionally followed by one of the units: " +
+			describeUnits(s.Units)
+	}
+	if len(s.Checks) != 0 {
+		rval += ". The value is subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value shown
+// in the largest unit that gives a short exact value
+func (s UnitFloat64Setter) CurrentValue() string {
+	return formatWithUnits(*s.Value, s.Units)
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or there are no Units
+func (s UnitFloat64Setter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name +
+			": UnitFloat64Setter Check failed: the Value to be set is nil")
+	}
+	if len(s.Units) == 0 {
+		panic(name +
+			": UnitFloat64Setter Check failed: there are no units")
+	}
+}
@@ -0,0 +1,314 @@
+package psetter_test
+
+import (
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+)
+
+// unitSetterTC holds the details of a test of one of the unit setters
+type unitSetterTC struct {
+	testName       string
+	s              param.Setter
+	val            string
+	expVal         string
+	errExpected    bool
+	errMustContain []string
+}
+
+// checkUnitSetter calls SetWithVal on the setter and checks that the error
+// and the resulting value, as shown by CurrentValue, are as expected
+func checkUnitSetter(t *testing.T, i int, tc unitSetterTC) {
+	t.Helper()
+	testID := fmt.Sprintf("test %d: %s", i, tc.testName)
+	initVal := tc.s.CurrentValue()
+
+	err := tc.s.SetWithVal("", tc.val)
+	if err != nil {
+		if !tc.errExpected {
+			t.Log(testID)
+			t.Errorf("\t: an unexpected error was returned"+
+				" when processing '%s': %s", tc.val, err)
+			return
+		}
+		testhelper.ShouldContain(t, testID, "error", err.Error(),
+			tc.errMustContain)
+		if v := tc.s.CurrentValue(); v != initVal {
+			t.Log(testID)
+			t.Errorf("\t: the value should not change on error: %q", v)
+		}
+	} else if tc.errExpected {
+		t.Log(testID)
+		t.Errorf("\t: an error was expected when processing '%s'"+
+			" but none was returned", tc.val)
+	} else if v := tc.s.CurrentValue(); v != tc.expVal {
+		t.Log(testID)
+		t.Errorf("\t: the value was not as expected, got %q, expected %q",
+			v, tc.expVal)
+	}
+}
+
+func TestSizeSetter(t *testing.T) {
+	var v int64
+	testCases := []unitSetterTC{
+		{testName: "bytes", val: "512", expVal: "512B"},
+		{testName: "zero", val: "0", expVal: "0B"},
+		{testName: "SI", val: "10k", expVal: "10kB"},
+		{testName: "SI - case ignored", val: "10KB", expVal: "10kB"},
+		{testName: "IEC", val: "64MiB", expVal: "64MiB"},
+		{testName: "IEC - short form", val: "64Mi", expVal: "64MiB"},
+		{testName: "fraction", val: "1.5G", expVal: "1.5GB"},
+		{testName: "IEC fraction", val: "1.5 KiB", expVal: "1.5KiB"},
+		{testName: "exabyte", val: "1E", expVal: "1EB"},
+		{testName: "not a round number", val: "1234567", expVal: "1234.567kB"},
+		{testName: "below the smallest multiplier", val: "999", expVal: "999B"},
+		{testName: "exact decimal - 1.001k", val: "1.001k", expVal: "1.001kB"},
+		{testName: "exact decimal - 1.003k", val: "1.003k", expVal: "1.003kB"},
+		{testName: "exact decimal - 1.005k", val: "1.005k", expVal: "1.005kB"},
+		{testName: "exact decimal - 0.1k", val: "0.1k", expVal: "100B"},
+		{testName: "exact decimal - 4.097M", val: "4.097M", expVal: "4.097MB"},
+		{testName: "exact decimal - 1.000001M", val: "1.000001M", expVal: "1000.001kB"},
+		{testName: "exact decimal - 2.123456789G", val: "2.123456789G", expVal: "2123456.789kB"},
+		{testName: "exact IEC fraction", val: "0.25KiB", expVal: "256B"},
+		{testName: "trailing zeros", val: "1.500000k", expVal: "1.5kB"},
+		{testName: "largest size", val: "9223372036854775807", expVal: "9223372036854775.807kB"},
+		{
+			testName:       "part of a byte",
+			val:            "1.5",
+			errExpected:    true,
+			errMustContain: []string{"must be a whole number of bytes"},
+		},
+		{
+			testName:       "part of a byte - k",
+			val:            "1.0005k",
+			errExpected:    true,
+			errMustContain: []string{"must be a whole number of bytes"},
+		},
+		{
+			testName:       "part of a byte - IEC",
+			val:            "0.001KiB",
+			errExpected:    true,
+			errMustContain: []string{"must be a whole number of bytes"},
+		},
+		{
+			testName:       "part of a byte - G",
+			val:            "1.0000000001G",
+			errExpected:    true,
+			errMustContain: []string{"must be a whole number of bytes"},
+		},
+		{
+			testName:       "one more than the largest size",
+			val:            "9223372036854775808",
+			errExpected:    true,
+			errMustContain: []string{"is too large"},
+		},
+		{
+			testName:       "negative",
+			val:            "-1k",
+			errExpected:    true,
+			errMustContain: []string{"must not be negative"},
+		},
+		{
+			testName:       "too large",
+			val:            "10EiB",
+			errExpected:    true,
+			errMustContain: []string{"is too large"},
+		},
+		{
+			testName:       "bad unit",
+			val:            "10 parsecs",
+			errExpected:    true,
+			errMustContain: []string{"unknown unit: 'parsecs'"},
+		},
+		{
+			testName:       "no number",
+			val:            "MB",
+			errExpected:    true,
+			errMustContain: []string{"it does not start with a number"},
+		},
+		{
+			testName:       "bad number",
+			val:            "1.2.3k",
+			errExpected:    true,
+			errMustContain: []string{"bad number: '1.2.3'"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tc.s = psetter.SizeSetter{Value: &v}
+		checkUnitSetter(t, i, tc)
+	}
+
+	bounded := psetter.SizeSetter{
+		Value:  &v,
+		Checks: []check.Int64{check.Int64LE(1 << 20)},
+	}
+	checkUnitSetter(t, len(testCases), unitSetterTC{
+		testName: "within the bound",
+		s:        bounded,
+		val:      "1MiB",
+		expVal:   "1MiB",
+	})
+	checkUnitSetter(t, len(testCases)+1, unitSetterTC{
+		testName:       "beyond the bound",
+		s:              bounded,
+		val:            "1049000",
+		errExpected:    true,
+		errMustContain: []string{"must be less than or equal to 1048576"},
+	})
+}
+
+func TestRateSetter(t *testing.T) {
+	var v float64
+	testCases := []unitSetterTC{
+		{
+			testName: "bytes per second",
+			s:        psetter.RateSetter{Value: &v},
+			val:      "5MB/s",
+			expVal:   "5MB/s",
+		},
+		{
+			testName: "bytes per minute",
+			s:        psetter.RateSetter{Value: &v},
+			val:      "3MB/min",
+			expVal:   "50kB/s",
+		},
+		{
+			testName: "count per second",
+			s:        psetter.RateSetter{Value: &v, Units: psetter.CountUnits},
+			val:      "100/s",
+			expVal:   "100/s",
+		},
+		{
+			testName: "count per hour",
+			s:        psetter.RateSetter{Value: &v, Units: psetter.CountUnits},
+			val:      "36k/hour",
+			expVal:   "10/s",
+		},
+		{
+			testName: "count per minute",
+			s:        psetter.RateSetter{Value: &v, Units: psetter.CountUnits},
+			val:      "30/min",
+			expVal:   "0.5/s",
+		},
+		{
+			testName: "count per millisecond",
+			s:        psetter.RateSetter{Value: &v, Units: psetter.CountUnits},
+			val:      "2/ms",
+			expVal:   "2k/s",
+		},
+		{
+			testName:       "no time unit",
+			s:              psetter.RateSetter{Value: &v},
+			val:            "100",
+			errExpected:    true,
+			errMustContain: []string{"must be of the form quantity/time-unit"},
+		},
+		{
+			testName:       "bad time unit",
+			s:              psetter.RateSetter{Value: &v},
+			val:            "100/fortnight",
+			errExpected:    true,
+			errMustContain: []string{"unknown unit: 'fortnight'"},
+		},
+		{
+			testName: "check fails",
+			s: psetter.RateSetter{
+				Value:  &v,
+				Checks: []check.Float64{check.Float64LE(1e6)},
+			},
+			val:            "2MB/s",
+			errExpected:    true,
+			errMustContain: []string{"must be less than or equal to"},
+		},
+	}
+
+	for i, tc := range testCases {
+		checkUnitSetter(t, i, tc)
+	}
+}
+
+func TestUnitFloat64Setter(t *testing.T) {
+	var v float64
+	distUnits := []psetter.Unit{
+		{Name: "m", Factor: 1, Alts: []string{"metre", "metres"}},
+		{Name: "km", Factor: 1000},
+		{Name: "mm", Factor: 0.001},
+		{Name: "Mm", Factor: 1e6},
+	}
+	optUnits := []psetter.Unit{
+		{Name: "x", Factor: 1, Alts: []string{""}},
+		{Name: "dozen", Factor: 12},
+	}
+
+	testCases := []unitSetterTC{
+		{
+			testName: "base unit",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:      "250m",
+			expVal:   "250m",
+		},
+		{
+			testName: "alternative name",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:      "250 metres",
+			expVal:   "250m",
+		},
+		{
+			testName: "larger unit",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:      "2.5km",
+			expVal:   "2.5km",
+		},
+		{
+			testName: "smaller unit",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:      "3mm",
+			expVal:   "3mm",
+		},
+		{
+			testName: "case sensitive exact match",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:      "1Mm",
+			expVal:   "1Mm",
+		},
+		{
+			testName: "unique match ignoring case",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:      "1KM",
+			expVal:   "1km",
+		},
+		{
+			testName:       "ambiguous match ignoring case",
+			s:              psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:            "1MM",
+			errExpected:    true,
+			errMustContain: []string{"ambiguous unit: 'MM'", "mm, Mm"},
+		},
+		{
+			testName:       "unit required",
+			s:              psetter.UnitFloat64Setter{Value: &v, Units: distUnits},
+			val:            "1",
+			errExpected:    true,
+			errMustContain: []string{"unknown unit: ''"},
+		},
+		{
+			testName: "unit optional",
+			s:        psetter.UnitFloat64Setter{Value: &v, Units: optUnits},
+			val:      "24",
+			expVal:   "2dozen",
+		},
+	}
+
+	for i, tc := range testCases {
+		checkUnitSetter(t, i, tc)
+	}
+
+	s := psetter.UnitFloat64Setter{Value: &v, Units: optUnits}
+	testhelper.ShouldContain(t, "AllowedValues", "allowed values",
+		s.AllowedValues(),
+		[]string{"a number optionally followed by one of the units: x, dozen"})
+}
@@ -0,0 +1,277 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Unit describes a unit that may follow a number. The value of the number
+// is multiplied by the Factor. The Name is used when showing values and
+// either the Name or any of the Alts may be given in a parameter value.
+type Unit struct {
+	Name   string
+	Factor float64
+	Alts   []string
+}
+
+// SizeUnits are the units for a size in bytes, with both the SI
+// (powers of 1000) and IEC (powers of 1024) multipliers
+var SizeUnits = []Unit{
+	{Name: "B", Factor: 1, Alts: []string{""}},
+	{Name: "kB", Factor: 1e3, Alts: []string{"k"}},
+	{Name: "MB", Factor: 1e6, Alts: []string{"M"}},
+	{Name: "GB", Factor: 1e9, Alts: []string{"G"}},
+	{Name: "TB", Factor: 1e12, Alts: []string{"T"}},
+	{Name: "PB", Factor: 1e15, Alts: []string{"P"}},
+	{Name: "EB", Factor: 1e18, Alts: []string{"E"}},
+	{Name: "KiB", Factor: 1 << 10, Alts: []string{"Ki"}},
+	{Name: "MiB", Factor: 1 << 20, Alts: []string{"Mi"}},
+	{Name: "GiB", Factor: 1 << 30, Alts: []string{"Gi"}},
+	{Name: "TiB", Factor: 1 << 40, Alts: []string{"Ti"}},
+	{Name: "PiB", Factor: 1 << 50, Alts: []string{"Pi"}},
+	{Name: "EiB", Factor: 1 << 60, Alts: []string{"Ei"}},
+}
+
+// CountUnits are the units for a count of things, with the SI multipliers
+var CountUnits = []Unit{
+	{Name: "", Factor: 1},
+	{Name: "k", Factor: 1e3, Alts: []string{"K"}},
+	{Name: "M", Factor: 1e6},
+	{Name: "G", Factor: 1e9},
+	{Name: "T", Factor: 1e12},
+}
+
+// RateTimeUnits are the units of time that may follow the '/' in a rate,
+// the Factor being the number of seconds
+var RateTimeUnits = []Unit{
+	{Name: "s", Factor: 1, Alts: []string{"sec", "second"}},
+	{Name: "ms", Factor: 1e-3, Alts: []string{"msec", "millisecond"}},
+	{Name: "min", Factor: 60, Alts: []string{"m", "minute"}},
+	{Name: "h", Factor: 60 * 60, Alts: []string{"hr", "hour"}},
+	{Name: "d", Factor: 24 * 60 * 60, Alts: []string{"day"}},
+}
+
+// maxUnitDecimals is the largest number of decimal places a value may have
+// when shown in a unit
+const maxUnitDecimals = 3
+
+// findUnit returns the unit with the name. An exact match of the Name or
+// one of the Alts is preferred but if there is none then a match ignoring
+// case is used provided that only one unit matches.
+func findUnit(name string, units []Unit) (Unit, error) {
+	var folded []Unit
+	for _, u := range units {
+		exact, fold := u.matches(name)
+		if exact {
+			return u, nil
+		}
+		if fold {
+			folded = append(folded, u)
+		}
+	}
+
+	switch len(folded) {
+	case 1:
+		return folded[0], nil
+	case 0:
+		return Unit{}, fmt.Errorf("unknown unit: '%s'", name)
+	}
+	names := make([]string, 0, len(folded))
+	for _, u := range folded {
+		names = append(names, u.Name)
+	}
+	return Unit{}, fmt.Errorf("ambiguous unit: '%s', it could be any of: %s",
+		name, strings.Join(names, ", "))
+}
+
+// matches reports whether the name exactly matches the Unit's Name or one
+// of its Alts and whether it matches any of them ignoring case
+func (u Unit) matches(name string) (exact, fold bool) {
+	for _, n := range append([]string{u.Name}, u.Alts...) {
+		if n == name {
+			return true, true
+		}
+		if strings.EqualFold(n, name) {
+			fold = true
+		}
+	}
+	return false, fold
+}
+
+// splitNumStr splits the value into the text of the leading number and the
+// rest of the value with any spaces between them removed. The number may
+// have a leading sign and a decimal point but no exponent (so that, for
+// instance, "1E" is read as 1 exabyte).
+func splitNumStr(val string) (string, string, error) {
+	val = strings.TrimSpace(val)
+	end := 0
+	for i, r := range val {
+		if (r == '+' || r == '-') && i == 0 ||
+			r == '.' || (r >= '0' && r <= '9') {
+			end = i + 1
+			continue
+		}
+		break
+	}
+	if end == 0 {
+		return "", "", errors.New("it does not start with a number")
+	}
+	return val[:end], strings.TrimSpace(val[end:]), nil
+}
+
+// splitNumber splits the value into the leading number and the rest of
+// the value as for splitNumStr
+func splitNumber(val string) (float64, string, error) {
+	numStr, rest, err := splitNumStr(val)
+	if err != nil {
+		return 0, "", err
+	}
+
+	n, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("bad number: '%s'", numStr)
+	}
+	return n, rest, nil
+}
+
+// parseWithUnits converts the value, a number optionally followed by one
+// of the units, into a number
+func parseWithUnits(val string, units []Unit) (float64, error) {
+	n, unitName, err := splitNumber(val)
+	if err != nil {
+		return 0, err
+	}
+	u, err := findUnit(unitName, units)
+	if err != nil {
+		return 0, err
+	}
+	return n * u.Factor, nil
+}
+
+// parseWithUnitsExact converts the value, a number optionally followed by
+// one of the units, into an exact rational number. Unlike parseWithUnits
+// the decimal number is not converted to a float64 and so, for instance,
+// "1.001k" gives exactly 1001. The unit factors should be whole numbers
+// (or exact binary fractions) for the result to be exact.
+func parseWithUnitsExact(val string, units []Unit) (*big.Rat, error) {
+	numStr, unitName, err := splitNumStr(val)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Rat).SetString(numStr)
+	if !ok {
+		return nil, fmt.Errorf("bad number: '%s'", numStr)
+	}
+	u, err := findUnit(unitName, units)
+	if err != nil {
+		return nil, err
+	}
+	return n.Mul(n, new(big.Rat).SetFloat64(u.Factor)), nil
+}
+
+// formatWithUnits returns the value in the largest of the units that it
+// can be shown in exactly with no more than a few decimal places. If there
+// is no such unit, or the value is zero, it is shown in the unit with a
+// factor of 1 or, failing that, the smallest unit.
+func formatWithUnits(v float64, units []Unit) string {
+	if len(units) == 0 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	byFactor := append([]Unit(nil), units...)
+	sort.SliceStable(byFactor, func(i, j int) bool {
+		return byFactor[i].Factor > byFactor[j].Factor
+	})
+
+	base := byFactor[len(byFactor)-1]
+	for _, u := range byFactor {
+		if u.Factor == 1 {
+			base = u
+			break
+		}
+	}
+
+	if v != 0 {
+		for _, u := range byFactor {
+			if u.Factor > math.Abs(v) {
+				continue
+			}
+			s := strconv.FormatFloat(v/u.Factor, 'f', -1, 64)
+			if i := strings.IndexByte(s, '.'); i < 0 ||
+				len(s)-i-1 <= maxUnitDecimals {
+				return s + u.Name
+			}
+		}
+	}
+
+	return strconv.FormatFloat(v/base.Factor, 'f', -1, 64) + base.Name
+}
+
+// formatIntWithUnits returns the value in the largest of the units that
+// it can be shown in exactly with no more than a few decimal places, as
+// for formatWithUnits, but calculated exactly. The unit factors should be
+// whole numbers.
+func formatIntWithUnits(v int64, units []Unit) string {
+	byFactor := append([]Unit(nil), units...)
+	sort.SliceStable(byFactor, func(i, j int) bool {
+		return byFactor[i].Factor > byFactor[j].Factor
+	})
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(maxUnitDecimals), nil)
+	scaled := new(big.Int).Mul(big.NewInt(v), scale)
+	for _, u := range byFactor {
+		if u.Factor != math.Trunc(u.Factor) || u.Factor < 1 ||
+			(v != 0 && u.Factor > math.Abs(float64(v))) {
+			continue
+		}
+		f, _ := new(big.Float).SetFloat64(u.Factor).Int(nil)
+		if new(big.Int).Rem(scaled, f).Sign() != 0 {
+			continue
+		}
+		if v == 0 && u.Factor != 1 {
+			continue
+		}
+		s := new(big.Rat).SetFrac(big.NewInt(v), f).FloatString(maxUnitDecimals)
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		return s + u.Name
+	}
+	return formatWithUnits(float64(v), units)
+}
+
+// describeUnits returns a string describing the units
+func describeUnits(units []Unit) string {
+	names := make([]string, 0, len(units))
+	for _, u := range units {
+		if u.Name == "" {
+			continue
+		}
+		n := u.Name
+		var alts []string
+		for _, a := range u.Alts {
+			if a != "" {
+				alts = append(alts, a)
+			}
+		}
+		if len(alts) > 0 {
+			n += " (or " + strings.Join(alts, ", ") + ")"
+		}
+		names = append(names, n)
+	}
+	return strings.Join(names, ", ")
+}
+
+// unitsOptional returns true if one of the units may be given as an empty
+// string
+func unitsOptional(units []Unit) bool {
+	for _, u := range units {
+		if exact, _ := u.matches(""); exact {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/check"
+	"github.com/nickwells/golem/param"
+	"net/url"
+	"strings"
+)
+
+// URLSetter allows you to specify a parameter that can be used to set a
+// *url.URL value. The URL must be absolute, that is, it must have a
+// scheme. If Schemes is not empty then the scheme must be one of them
+// (ignoring case). You can also supply check functions that will validate
+// the Value.
+type URLSetter struct {
+	Value   **url.URL
+	Schemes []string
+	Checks  []check.URL
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s URLSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s URLSetter) Set(_ string) error {
+	return errors.New("no URL given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks that the
+// value can be parsed to an absolute URL with an allowed scheme, if it
+// cannot it returns an error. If there is a check and the check is violated
+// it returns an error. Only if the value is parsed successfully and the
+// check is not violated is the Value set.
+func (s URLSetter) SetWithVal(_ string, paramVal string) error {
+	v, err := url.Parse(paramVal)
+	if err != nil {
+		return fmt.Errorf("could not parse '%s' as a URL: %s", paramVal, err)
+	}
+	if !v.IsAbs() {
+		return fmt.Errorf("the URL (%s) has no scheme", paramVal)
+	}
+	if len(s.Schemes) != 0 {
+		if err := check.URLSchemeIs(s.Schemes...)(v); err != nil {
+			return err
+		}
+	}
+
+	for _, check := range s.Checks {
+		if check == nil {
+			continue
+		}
+
+		err := check(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.Value = v
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s URLSetter) AllowedValues() string {
+	rval := "an absolute URL (such as https://example.com/path)"
+	if len(s.Schemes) != 0 {
+		rval += " with a scheme of: " + strings.Join(s.Schemes, ", ")
+	}
+	if len(s.Checks) != 0 {
+		rval += " subject to checks"
+	}
+	return rval
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s URLSetter) CurrentValue() string {
+	if *s.Value == nil {
+		return ""
+	}
+	return (*s.Value).String()
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil
+func (s URLSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": URLSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,279 @@
+package psetter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nickwells/golem/filecheck"
+	"github.com/nickwells/golem/fileparser"
+	"github.com/nickwells/golem/param"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WriteMode says how a WriterSetter should open a file
+type WriteMode int
+
+// These are the ways a file can be opened for writing
+const (
+	// WriteTruncate creates the file or truncates it if it exists
+	WriteTruncate WriteMode = iota
+	// WriteAppend creates the file or appends to it if it exists
+	WriteAppend
+	// WriteCreate creates the file; it is an error if it exists
+	WriteCreate
+)
+
+// flags returns the os.OpenFile flags for the mode
+func (wm WriteMode) flags() int {
+	switch wm {
+	case WriteAppend:
+		return os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	case WriteCreate:
+		return os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	}
+	return os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+}
+
+// String returns a description of the mode
+func (wm WriteMode) String() string {
+	switch wm {
+	case WriteAppend:
+		return "appended to"
+	case WriteCreate:
+		return "created and must not already exist"
+	}
+	return "truncated"
+}
+
+// DfltWriterPerm is the permission used by a WriterSetter when creating a
+// file if the Perm is zero. It is subject to the umask
+const DfltWriterPerm os.FileMode = 0666
+
+// stdoutWriteCloser is the io.WriteCloser set by the WriterSetter for the
+// standard output. Closing it does not close os.Stdout
+type stdoutWriteCloser struct {
+	io.Writer
+}
+
+// Name returns the name of the standard output
+func (stdoutWriteCloser) Name() string { return os.Stdout.Name() }
+
+// Close does nothing
+func (stdoutWriteCloser) Close() error { return nil }
+
+// lazyFile is the io.WriteCloser set by the WriterSetter for a named
+// file. The file is not opened until it is first written to so that
+// setting a value which is then replaced by a later value, or setting a
+// value during a parse which then fails, does not create or truncate the
+// file. The open is guarded so that concurrent first Writes open the file
+// only once
+type lazyFile struct {
+	name   string
+	flags  int
+	perm   os.FileMode
+	once   sync.Once
+	f      *os.File
+	err    error
+	closed bool
+}
+
+// Name returns the name of the file
+func (lf *lazyFile) Name() string { return lf.name }
+
+// open opens the file, recording any error
+func (lf *lazyFile) open() {
+	lf.f, lf.err = os.OpenFile(lf.name, lf.flags, lf.perm)
+}
+
+// Write opens the file if it has not yet been opened and writes to it. If
+// the file could not be opened the error is returned by this and every
+// later Write
+func (lf *lazyFile) Write(p []byte) (int, error) {
+	if lf.closed {
+		return 0, os.ErrClosed
+	}
+	lf.once.Do(lf.open)
+	if lf.err != nil {
+		return 0, lf.err
+	}
+	return lf.f.Write(p)
+}
+
+// Close closes the file if it has been opened. It must not be called
+// concurrently with Write
+func (lf *lazyFile) Close() error {
+	lf.closed = true
+	lf.once.Do(func() {})
+	if lf.f == nil {
+		return nil
+	}
+	f := lf.f
+	lf.f = nil
+	return f.Close()
+}
+
+// checkWritable returns a non-nil error if the named file could not be
+// opened with the given mode. An existing file must not be a directory and
+// must be writable (and must not exist at all if the mode is
+// WriteCreate). If the file does not exist its directory must exist and
+// be writable; this is checked by creating and removing a temporary file
+// in that directory.
+func checkWritable(pathname string, mode WriteMode) error {
+	fi, err := os.Stat(pathname)
+	if err == nil {
+		if mode == WriteCreate {
+			return fmt.Errorf("file exists: %s", pathname)
+		}
+		if fi.IsDir() {
+			return fmt.Errorf("%s is a directory", pathname)
+		}
+		f, err := os.OpenFile(pathname, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if mode == WriteCreate {
+		if _, err := os.Lstat(pathname); err == nil {
+			return fmt.Errorf("file exists: %s", pathname)
+		}
+	}
+
+	dir := filepath.Dir(pathname)
+	dfi, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", pathname, err)
+	}
+	if !dfi.IsDir() {
+		return fmt.Errorf("cannot create %s: %s is not a directory",
+			pathname, dir)
+	}
+	f, err := os.CreateTemp(dir, ".writerSetterCheck*")
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", pathname, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// WriterSetter allows you to specify a parameter giving a file to be
+// written to. The Value is set to an io.WriteCloser for the named file or,
+// if the name is StdStreamName ("-"), for the standard output; closing the
+// standard output writer does not close os.Stdout. The Mode says whether
+// an existing file is truncated or appended to or whether the file must
+// not exist. A file is created with the Perm permissions or DfltWriterPerm
+// if the Perm is zero.
+//
+// The file is checked against the Expectation when the value is set. It
+// is also checked that it could be opened: that it is not a directory and
+// is writable or, if it does not exist, that its directory exists and is
+// writable (and, if the Mode is WriteCreate, that it does not exist). So
+// a bad pathname is reported when the parameters are parsed. The file is
+// not opened until it is first written to. This means that a value
+// from a config file which is replaced by a later value does not create or
+// truncate its file, nor does a value set by a parse which fails. It also
+// means that a file which is never written to is not created or
+// truncated. If the file cannot be opened when it is first written to (if
+// it has been removed or its permissions changed since the value was set)
+// the error is returned by that Write.
+//
+// If the Value already holds a writer when a new value is set the old
+// writer is closed. The WriterSetter is a param.Cleaner and so the writer
+// is closed by the ParamSet Cleanup method.
+type WriterSetter struct {
+	Value       *io.WriteCloser
+	Expectation filecheck.ExpectedStatus
+	Mode        WriteMode
+	Perm        os.FileMode
+}
+
+// ValueReq returns param.Mandatory indicating that some value must follow
+// the parameter
+func (s WriterSetter) ValueReq() param.ValueReq { return param.Mandatory }
+
+// Set (called when there is no following value) returns an error
+func (s WriterSetter) Set(_ string) error {
+	return errors.New("no pathname given (it should be followed by '=...')")
+}
+
+// SetWithVal (called when a value follows the parameter) checks the named
+// file against the Expectation and that it could be opened with the
+// Mode. Only if these checks pass is the Value set. The file is
+// not opened until it is first written to.
+func (s WriterSetter) SetWithVal(_ string, paramVal string) error {
+	var wc io.WriteCloser
+	if paramVal == StdStreamName {
+		wc = stdoutWriteCloser{Writer: os.Stdout}
+	} else {
+		pathname, err := fileparser.FixFileName(paramVal)
+		if err != nil {
+			return err
+		}
+		err = s.Expectation.StatusCheck(pathname)
+		if err != nil {
+			return err
+		}
+		err = checkWritable(pathname, s.Mode)
+		if err != nil {
+			return err
+		}
+		perm := s.Perm
+		if perm == 0 {
+			perm = DfltWriterPerm
+		}
+		wc = &lazyFile{name: pathname, flags: s.Mode.flags(), perm: perm}
+	}
+
+	err := s.Cleanup()
+	if err != nil {
+		wc.Close()
+		return err
+	}
+	*s.Value = wc
+	return nil
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s WriterSetter) AllowedValues() string {
+	rval := "a pathname or '" + StdStreamName + "' for the standard output." +
+		" An existing file will be " + s.Mode.String()
+
+	extras := s.Expectation.String()
+	if extras != "" {
+		rval += ". " + extras
+	}
+
+	return rval
+}
+
+// CurrentValue returns the name of the file being written
+func (s WriterSetter) CurrentValue() string {
+	return streamName(*s.Value)
+}
+
+// Cleanup closes the writer, if any, and sets the Value to nil
+func (s WriterSetter) Cleanup() error {
+	if *s.Value == nil {
+		return nil
+	}
+	err := (*s.Value).Close()
+	*s.Value = nil
+	return err
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil or the Mode is not valid.
+func (s WriterSetter) CheckSetter(name string) {
+	if s.Value == nil {
+		panic(name + ": WriterSetter Check failed: the Value to be set is nil")
+	}
+	if s.Mode < WriteTruncate || s.Mode > WriteCreate {
+		panic(fmt.Sprintf("%s: WriterSetter Check failed: bad Mode: %d",
+			name, s.Mode))
+	}
+}
@@ -55,3 +55,28 @@ type Setter interface {
 	CurrentValue() string
 	CheckSetter(name string)
 }
+
+// Cleaner is an optional interface for a Setter which holds resources,
+// such as open files, that should be released once the program has
+// finished with the parameter values. If the Setter of a parameter
+// implements it then its Cleanup method is added to the cleanup functions
+// of the ParamSet when the parameter is added. See ParamSet.Cleanup.
+type Cleaner interface {
+	Setter
+	Cleanup() error
+}
+
+// ListEditor is an optional interface for a Setter whose value is a list
+// (or a map) that can be edited rather than replaced. If the Setter of a
+// parameter implements it then the parameter name may be followed by '+'
+// or '-' to add values to the list or to remove values from it. For
+// instance, "-tags+=x" on the command line or "tags += x" in a
+// configuration file is passed to the Setter as the value "+=x". See the
+// psetter package for the setters which implement this.
+//
+// EditsList returns true if the Setter accepts these edits; a Setter
+// which only accepts them when so configured can return false otherwise
+type ListEditor interface {
+	Setter
+	EditsList() bool
+}
@@ -0,0 +1,56 @@
+package param_test
+
+import (
+	"github.com/nickwells/golem/param/paramset"
+	"github.com/nickwells/golem/param/psetter"
+	"github.com/nickwells/golem/testhelper"
+	"testing"
+)
+
+func TestUnicodeParams(t *testing.T) {
+	var größe string
+	var farbe string
+
+	ps, err := paramset.NewNoHelpNoExitNoErrRpt()
+	if err != nil {
+		t.Fatal("couldn't construct the ParamSet: ", err)
+	}
+	ps.Add("größe", psetter.StringSetter{Value: &größe}, "the size")
+	ps.Add("farbe",
+		psetter.EnumSetter{
+			Value: &farbe,
+			AllowedVals: psetter.AValMap{
+				"grün": "green",
+				"blau": "blue",
+			},
+			AValMatch: psetter.AValMatch{IgnoreCase: true},
+		},
+		"the colour")
+
+	errs := ps.Parse([]string{
+		"-größe", "groß",
+		"-farbe", "GRÜN",
+		"-grösse=klein",
+	})
+
+	if größe != "groß" {
+		t.Errorf("größe should be %q but is %q", "groß", größe)
+	}
+	if farbe != "grün" {
+		t.Errorf("farbe should be %q but is %q", "grün", farbe)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("there should be 1 error but there were %d: %v",
+			len(errs), errs)
+	}
+	pErrs, ok := errs["grösse"]
+	if !ok || len(pErrs) != 1 {
+		t.Fatalf("there should be an error for 'grösse', got: %v", errs)
+	}
+	testhelper.ShouldContain(t, "misspelt parameter", "error",
+		pErrs[0].Error(), []string{
+			"this is not a parameter of this program",
+			"Did you mean: größe ?",
+		})
+}
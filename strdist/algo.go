@@ -0,0 +1,123 @@
+package strdist
+
+import "sync"
+
+// strQuery is a Query for those algorithms which only need the target
+// string with the CaseMod applied
+type strQuery struct {
+	target string
+	cm     CaseMod
+	dist   func(a, b string) float64
+}
+
+// newStrQuery returns a strQuery for the target string (s) which will use
+// the dist func to calculate the distances
+func newStrQuery(s string, cm CaseMod, dist func(a, b string) float64) strQuery {
+	return strQuery{
+		target: cm.Apply(s),
+		cm:     cm,
+		dist:   dist,
+	}
+}
+
+// Dist for a strQuery will calculate the distance from the target string
+func (q strQuery) Dist(s string) float64 {
+	return q.dist(q.target, q.cm.Apply(s))
+}
+
+// intDist converts a distance func returning an int into one returning a
+// float64
+func intDist(dist func(a, b string) int) func(a, b string) float64 {
+	return func(a, b string) float64 {
+		return float64(dist(a, b))
+	}
+}
+
+// AdaptDistAlgo returns an Algo which uses the DistAlgo to calculate the
+// distances. As the DistAlgo records details of the target string, the
+// Queries returned by the Algo take turns to use it: it is prepared again
+// with the target string whenever a different Query uses it. This means
+// that the Algo is safe for concurrent use but distances will not be
+// calculated in parallel; in particular FindLikeParallel will be no faster
+// than FindLike. Use AdaptDistAlgoFunc if the distances should be
+// calculated in parallel.
+func AdaptDistAlgo(a DistAlgo) Algo {
+	return &distAlgoAdapter{a: a}
+}
+
+// distAlgoAdapter is an Algo using a DistAlgo
+type distAlgoAdapter struct {
+	mu       sync.Mutex
+	a        DistAlgo
+	prepared *distAlgoQuery
+}
+
+// distAlgoQuery is the Query returned by a distAlgoAdapter
+type distAlgoQuery struct {
+	ad *distAlgoAdapter
+	s  string
+	cm CaseMod
+}
+
+// PrepQuery for a distAlgoAdapter records the target string
+func (ad *distAlgoAdapter) PrepQuery(s string, cm CaseMod) Query {
+	return &distAlgoQuery{ad: ad, s: s, cm: cm}
+}
+
+// Dist for a distAlgoQuery will calculate the distance from the target
+// string using the DistAlgo, preparing it first if it was last prepared by
+// another Query
+func (q *distAlgoQuery) Dist(s string) float64 {
+	ad := q.ad
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+
+	if ad.prepared != q {
+		ad.a.Prep(q.s, q.cm)
+		ad.prepared = q
+	}
+	return ad.a.Dist(q.s, s, q.cm)
+}
+
+// AdaptDistAlgoFunc returns an Algo which uses DistAlgos made by the newAlgo
+// func to calculate the distances. Each Query returned by the Algo has its
+// own DistAlgos, prepared with its target string, and each goroutine
+// calculating distances from the Query uses a different one. This means
+// that, unlike an Algo returned by AdaptDistAlgo, the distances can be
+// calculated in parallel, for instance by FindLikeParallel.
+func AdaptDistAlgoFunc(newAlgo func() DistAlgo) Algo {
+	return distAlgoFuncAdapter{newAlgo: newAlgo}
+}
+
+// distAlgoFuncAdapter is an Algo using DistAlgos made by the newAlgo func
+type distAlgoFuncAdapter struct {
+	newAlgo func() DistAlgo
+}
+
+// distAlgoPoolQuery is the Query returned by a distAlgoFuncAdapter. The
+// pool holds DistAlgos prepared with the target string which are not in use
+type distAlgoPoolQuery struct {
+	pool sync.Pool
+	s    string
+	cm   CaseMod
+}
+
+// PrepQuery for a distAlgoFuncAdapter returns a Query which will make new
+// DistAlgos, prepared with the target string, as they are needed
+func (ad distAlgoFuncAdapter) PrepQuery(s string, cm CaseMod) Query {
+	q := &distAlgoPoolQuery{s: s, cm: cm}
+	q.pool.New = func() any {
+		a := ad.newAlgo()
+		a.Prep(s, cm)
+		return a
+	}
+	return q
+}
+
+// Dist for a distAlgoPoolQuery will calculate the distance from the target
+// string using a DistAlgo which is not being used by any other goroutine
+func (q *distAlgoPoolQuery) Dist(s string) float64 {
+	a := q.pool.Get().(DistAlgo)
+	defer q.pool.Put(a)
+	return a.Dist(q.s, s, q.cm)
+}
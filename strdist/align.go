@@ -0,0 +1,240 @@
+package strdist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickwells/golem/mathutil"
+)
+
+// EditOp describes a single step in transforming one string into another
+type EditOp int
+
+// These are the steps in an edit script
+const (
+	// Match is where the two values are the same
+	Match EditOp = iota
+	// Insert is where a value is only in the second string
+	Insert
+	// Delete is where a value is only in the first string
+	Delete
+	// Substitute is where a value in the first string is replaced by a
+	// value in the second
+	Substitute
+)
+
+// String returns a string form of the EditOp
+func (op EditOp) String() string {
+	switch op {
+	case Match:
+		return "Match"
+	case Insert:
+		return "Insert"
+	case Delete:
+		return "Delete"
+	case Substitute:
+		return "Substitute"
+	}
+	return fmt.Sprintf("EditOp(%d)", int(op))
+}
+
+// AlignOp records one step of an alignment between two values. A is the
+// part of the first value and B the part of the second value that the step
+// covers; A is empty for an Insert and B is empty for a Delete.
+type AlignOp struct {
+	Op   EditOp
+	A, B string
+}
+
+// String returns a string form of the AlignOp
+func (ao AlignOp) String() string {
+	return fmt.Sprintf("%s: %q, %q", ao.Op, ao.A, ao.B)
+}
+
+// Align returns the edit script transforming string a into string b. It
+// gives one AlignOp for each rune and the number of non-Match steps is the
+// LevenshteinDistance between the strings. Where there is a choice of
+// equally short scripts, substitutions are preferred and any deletions come
+// before insertions.
+func Align(a, b string) []AlignOp {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	return align(len(aRunes), len(bRunes),
+		func(i, j int) bool { return aRunes[i] == bRunes[j] },
+		true,
+		func(i int) string { return string(aRunes[i]) },
+		func(j int) string { return string(bRunes[j]) })
+}
+
+// AlignLines returns the edit script transforming the lines in a into the
+// lines in b. Lines are either matched, deleted or inserted, never
+// substituted, so the matched lines are a longest common subsequence of
+// the two slices. This is the form of the script usually shown by diff
+// programs.
+func AlignLines(a, b []string) []AlignOp {
+	return align(len(a), len(b),
+		func(i, j int) bool { return a[i] == b[j] },
+		false,
+		func(i int) string { return a[i] },
+		func(j int) string { return b[j] })
+}
+
+// align calculates the shortest edit script between two sequences of aLen
+// and bLen values. The eq func reports whether the i'th value of the first
+// sequence equals the j'th value of the second. If allowSubs is false a
+// pair of unequal values is treated as a Delete and an Insert. The aVal and
+// bVal funcs give the string form of the values. The script is built from
+// the end so Inserts are chosen before Deletes in order that, read forwards,
+// the Deletes come first.
+func align(aLen, bLen int,
+	eq func(i, j int) bool,
+	allowSubs bool,
+	aVal, bVal func(int) string,
+) []AlignOp {
+	subsCost := 1
+	if !allowSubs {
+		subsCost = 2
+	}
+
+	d := make([][]int, aLen+1)
+	for i := range d {
+		d[i] = make([]int, bLen+1)
+		d[i][0] = i
+	}
+	for j := 1; j <= bLen; j++ {
+		d[0][j] = j
+	}
+	for i := 1; i <= aLen; i++ {
+		for j := 1; j <= bLen; j++ {
+			sub := d[i-1][j-1]
+			if !eq(i-1, j-1) {
+				sub += subsCost
+			}
+			d[i][j] = mathutil.MinOfInt(sub, d[i-1][j]+1, d[i][j-1]+1)
+		}
+	}
+
+	ops := make([]AlignOp, 0, mathutil.MaxOfInt(aLen, bLen))
+	i, j := aLen, bLen
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && eq(i-1, j-1) && d[i][j] == d[i-1][j-1]:
+			i--
+			j--
+			ops = append(ops, AlignOp{Op: Match, A: aVal(i), B: bVal(j)})
+		case i > 0 && j > 0 && allowSubs && d[i][j] == d[i-1][j-1]+1:
+			i--
+			j--
+			ops = append(ops, AlignOp{Op: Substitute, A: aVal(i), B: bVal(j)})
+		case j > 0 && d[i][j] == d[i][j-1]+1:
+			j--
+			ops = append(ops, AlignOp{Op: Insert, B: bVal(j)})
+		default:
+			i--
+			ops = append(ops, AlignOp{Op: Delete, A: aVal(i)})
+		}
+	}
+
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	return ops
+}
+
+// FormatAlign returns the alignment (as given by Align) as a single string
+// with the differences marked. Matching parts are shown unchanged and each
+// run of non-matching steps is shown in square brackets as the text from
+// the first string, an arrow and the text from the second string. For
+// instance, aligning "parms" with "params" gives "par[→a]ms" and aligning
+// "parms" with "pams" gives "pa[r→]ms".
+func FormatAlign(ops []AlignOp) string {
+	var s strings.Builder
+	var aPart, bPart strings.Builder
+	flush := func() {
+		if aPart.Len() == 0 && bPart.Len() == 0 {
+			return
+		}
+		s.WriteString("[" + aPart.String() + "→" + bPart.String() + "]")
+		aPart.Reset()
+		bPart.Reset()
+	}
+
+	for _, op := range ops {
+		if op.Op == Match {
+			flush()
+			s.WriteString(op.A)
+			continue
+		}
+		aPart.WriteString(op.A)
+		bPart.WriteString(op.B)
+	}
+	flush()
+
+	return s.String()
+}
+
+// FormatLineAlign returns the alignment (as given by AlignLines) as lines
+// in the style of a diff. Each line is prefixed with a marker: two spaces
+// for a Match, "- " for a Delete (a line only in the first slice) and "+ "
+// for an Insert (a line only in the second). A Substitute is shown as a
+// Delete followed by an Insert. If context is >= 0 then only that many
+// matching lines either side of a difference are shown and any others are
+// replaced by a single line reporting how many lines were skipped.
+func FormatLineAlign(ops []AlignOp, context int) []string {
+	show := make([]bool, len(ops))
+	for i, op := range ops {
+		if context < 0 || op.Op != Match {
+			show[i] = true
+			continue
+		}
+		first := mathutil.MaxOfInt(0, i-context)
+		last := mathutil.MinOfInt(len(ops)-1, i+context)
+		for k := first; k <= last; k++ {
+			if ops[k].Op != Match {
+				show[i] = true
+				break
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(ops))
+	skipped := 0
+	reportSkipped := func() {
+		if skipped == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("... %d matching line%s ...",
+			skipped, plural(skipped)))
+		skipped = 0
+	}
+
+	for i, op := range ops {
+		if !show[i] {
+			skipped++
+			continue
+		}
+		reportSkipped()
+
+		switch op.Op {
+		case Match:
+			lines = append(lines, "  "+op.A)
+		case Delete:
+			lines = append(lines, "- "+op.A)
+		case Insert:
+			lines = append(lines, "+ "+op.B)
+		case Substitute:
+			lines = append(lines, "- "+op.A, "+ "+op.B)
+		}
+	}
+	reportSkipped()
+
+	return lines
+}
+
+// plural returns "s" if n is not 1
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
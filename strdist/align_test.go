@@ -0,0 +1,150 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+	"github.com/nickwells/golem/testhelper"
+)
+
+func TestAlign(t *testing.T) {
+	testCases := []struct {
+		name      string
+		a, b      string
+		expFormat string
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name:      "same",
+			a:         "params",
+			b:         "params",
+			expFormat: "params",
+		},
+		{
+			name:      "deletion",
+			a:         "parms",
+			b:         "pams",
+			expFormat: "pa[r→]ms",
+		},
+		{
+			name:      "insertion",
+			a:         "parms",
+			b:         "params",
+			expFormat: "par[→a]ms",
+		},
+		{
+			name:      "substitution",
+			a:         "colour",
+			b:         "color",
+			expFormat: "colo[u→]r",
+		},
+		{
+			name:      "run of changes",
+			a:         "kitten",
+			b:         "sitting",
+			expFormat: "[k→s]itt[e→i]n[→g]",
+		},
+		{
+			name:      "empty first",
+			b:         "abc",
+			expFormat: "[→abc]",
+		},
+		{
+			name:      "multi-byte characters",
+			a:         "café",
+			b:         "cafe",
+			expFormat: "caf[é→e]",
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		ops := strdist.Align(tc.a, tc.b)
+
+		var a, b string
+		edits := 0
+		for _, op := range ops {
+			a += op.A
+			b += op.B
+			if op.Op != strdist.Match {
+				edits++
+			}
+		}
+		if a != tc.a || b != tc.b {
+			t.Log(tcID)
+			t.Errorf("\t: the ops give %q and %q, expected %q and %q",
+				a, b, tc.a, tc.b)
+		}
+		if ld := strdist.LevenshteinDistance(tc.a, tc.b); edits != ld {
+			t.Log(tcID)
+			t.Errorf("\t: there are %d edits, the Levenshtein distance is %d",
+				edits, ld)
+		}
+		if f := strdist.FormatAlign(ops); f != tc.expFormat {
+			t.Log(tcID)
+			t.Errorf("\t: FormatAlign expected: %q got: %q", tc.expFormat, f)
+		}
+	}
+}
+
+func TestAlignLines(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     []string
+		context  int
+		expLines []string
+	}{
+		{
+			name:     "empty",
+			context:  -1,
+			expLines: []string{},
+		},
+		{
+			name:     "no substitutions",
+			a:        []string{"a", "b", "c"},
+			b:        []string{"a", "x", "c"},
+			context:  -1,
+			expLines: []string{"  a", "- b", "+ x", "  c"},
+		},
+		{
+			name:     "reordered",
+			a:        []string{"a", "b", "c", "d"},
+			b:        []string{"b", "c", "a", "d"},
+			context:  -1,
+			expLines: []string{"- a", "  b", "  c", "+ a", "  d"},
+		},
+		{
+			name:    "limited context",
+			a:       []string{"1", "2", "3", "4", "5", "6", "7"},
+			b:       []string{"1", "2", "3", "x", "5", "6", "7"},
+			context: 1,
+			expLines: []string{
+				"... 2 matching lines ...",
+				"  3", "- 4", "+ x", "  5",
+				"... 2 matching lines ...",
+			},
+		},
+		{
+			name:     "no context",
+			a:        []string{"1", "2", "3"},
+			b:        []string{"1", "2"},
+			context:  0,
+			expLines: []string{"... 2 matching lines ...", "- 3"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		lines := strdist.FormatLineAlign(
+			strdist.AlignLines(tc.a, tc.b), tc.context)
+		if testhelper.StringSliceDiff(lines, tc.expLines) {
+			t.Log(tcID)
+			t.Logf("\t: expected: %q", tc.expLines)
+			t.Logf("\t:      got: %q", lines)
+			t.Errorf("\t: unexpected formatted line alignment")
+		}
+	}
+}
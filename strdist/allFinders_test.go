@@ -53,6 +53,58 @@ func TestAllFinders(t *testing.T) {
 	if err != nil {
 		t.Fatal("couldn't create the ScaledLev Finder: ", err)
 	}
+	osaFinder, err :=
+		strdist.NewOSAFinder(0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the OSA Finder: ", err)
+	}
+	damerauLevenshteinFinder, err :=
+		strdist.NewDamerauLevenshteinFinder(0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the DamerauLevenshtein Finder: ", err)
+	}
+	jaroFinder, err :=
+		strdist.NewJaroFinder(0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the Jaro Finder: ", err)
+	}
+	jaroWinklerFinder, err :=
+		strdist.NewJaroWinklerFinder(0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the JaroWinkler Finder: ", err)
+	}
+	lcsFinder, err :=
+		strdist.NewLCSFinder(0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the LCS Finder: ", err)
+	}
+	keyboardCosts := strdist.KeyboardEditCosts(strdist.QWERTY, 0.5)
+	weightedEditFinder, err := strdist.NewWeightedEditFinder(
+		keyboardCosts, 0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the WeightedEdit Finder: ", err)
+	}
+	phoneticFinder, err := strdist.NewPhoneticFinder(
+		strdist.DoubleMetaphoneEncoder, 0.25, 0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the Phonetic Finder: ", err)
+	}
+	tokenSortFinder, err := strdist.NewTokenSortFinder(
+		strdist.DfltTokeniser, 0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the TokenSort Finder: ", err)
+	}
+	tokenSetFinder, err := strdist.NewTokenSetFinder(
+		strdist.DfltTokeniser, 0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the TokenSet Finder: ", err)
+	}
+	mongeElkanFinder, err := strdist.NewMongeElkanFinder(
+		strdist.DfltTokeniser, &strdist.JaroWinklerAlgo{},
+		0, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the MongeElkan Finder: ", err)
+	}
 
 	testCases := []struct {
 		name     string
@@ -116,6 +168,74 @@ func TestAllFinders(t *testing.T) {
 				return float64(strdist.HammingDistance(s1, s2))
 			},
 		},
+		{
+			name:   "osa",
+			finder: osaFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return float64(strdist.OSADistance(s1, s2))
+			},
+		},
+		{
+			name:   "damerauLevenshtein",
+			finder: damerauLevenshteinFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return float64(strdist.DamerauLevenshteinDistance(s1, s2))
+			},
+		},
+		{
+			name:     "jaro",
+			finder:   jaroFinder,
+			distFunc: strdist.JaroDistance,
+		},
+		{
+			name:     "jaroWinkler",
+			finder:   jaroWinklerFinder,
+			distFunc: strdist.JaroWinklerDistance,
+		},
+		{
+			name:   "lcs",
+			finder: lcsFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return float64(strdist.LCSDistance(s1, s2))
+			},
+		},
+		{
+			name:   "weightedEdit",
+			finder: weightedEditFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return strdist.WeightedEditDistance(s1, s2, keyboardCosts)
+			},
+		},
+		{
+			name:   "phonetic",
+			finder: phoneticFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return strdist.PhoneticDistance(s1, s2,
+					strdist.DoubleMetaphoneEncoder, 0.25)
+			},
+		},
+		{
+			name:   "tokenSort",
+			finder: tokenSortFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return strdist.TokenSortDistance(s1, s2, strdist.DfltTokeniser)
+			},
+		},
+		{
+			name:   "tokenSet",
+			finder: tokenSetFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return strdist.TokenSetDistance(s1, s2, strdist.DfltTokeniser)
+			},
+		},
+		{
+			name:   "mongeElkan",
+			finder: mongeElkanFinder,
+			distFunc: func(s1, s2 string) float64 {
+				return strdist.MongeElkanDistance(s1, s2,
+					strdist.DfltTokeniser, &strdist.JaroWinklerAlgo{})
+			},
+		},
 	}
 
 	for i, tc := range testCases {
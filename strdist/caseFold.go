@@ -0,0 +1,250 @@
+package strdist
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldString returns the string with full Unicode case folding applied, so
+// that, for instance, "ß" becomes "ss" and both "Σ" and "ς" become "σ"
+func foldString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if f, ok := fullCaseFolds[r]; ok {
+			b.WriteString(f)
+			continue
+		}
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+// foldRune returns the simple case folding of the rune. Runes which have no
+// other case (such as the dotless i, "ı") are left unchanged. Otherwise
+// the lower case of the upper case form is used so that, for instance, the
+// long s ("ſ") and the Kelvin sign ("K") fold to "s" and "k"
+func foldRune(r rune) rune {
+	if unicode.SimpleFold(r) == r {
+		return r
+	}
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// removeAccents returns the string with any non-spacing marks (such as
+// combining accents) removed and with any accented Latin, Greek or
+// Cyrillic letter replaced by the letter without its accents
+func removeAccents(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if u, ok := unaccentedRunes[r]; ok {
+			r = u
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unaccentedRunes maps each accented letter to the letter without accents
+var unaccentedRunes = func() map[rune]rune {
+	from, to := []rune(accented), []rune(unaccented)
+	if len(from) != len(to) {
+		panic("strdist: the accented and unaccented letters do not match")
+	}
+	m := make(map[rune]rune, len(from))
+	for i, r := range from {
+		m[r] = to[i]
+	}
+	return m
+}()
+
+// These give the letters which have accents removed by removeAccents.
+// Each rune in accented is the Unicode canonical composition of the
+// corresponding rune in unaccented with one or more non-spacing marks.
+const (
+	// accented holds the accented letters
+	accented = "" +
+		"ÀÁÂÃÄÅÇÈÉÊËÌÍÎÏÑÒÓÔÕÖÙÚÛ" +
+		"ÜÝàáâãäåçèéêëìíîïñòóôõöù" +
+		"úûüýÿĀāĂăĄąĆćĈĉĊċČčĎďĒēĔ" +
+		"ĕĖėĘęĚěĜĝĞğĠġĢģĤĥĨĩĪīĬĭĮ" +
+		"įİĴĵĶķĹĺĻļĽľŃńŅņŇňŌōŎŏŐő" +
+		"ŔŕŖŗŘřŚśŜŝŞşŠšŢţŤťŨũŪūŬŭ" +
+		"ŮůŰűŲųŴŵŶŷŸŹźŻżŽžƠơƯưǍǎǏ" +
+		"ǐǑǒǓǔǕǖǗǘǙǚǛǜǞǟǠǡǢǣǦǧǨǩǪ" +
+		"ǫǬǭǮǯǰǴǵǸǹǺǻǼǽǾǿȀȁȂȃȄȅȆȇ" +
+		"ȈȉȊȋȌȍȎȏȐȑȒȓȔȕȖȗȘșȚțȞȟȦȧ" +
+		"ȨȩȪȫȬȭȮȯȰȱȲȳ΅ΆΈΉΊΌΎΏΐΪΫά" +
+		"έήίΰϊϋόύώϓϔЀЁЃЇЌЍЎЙйѐёѓї" +
+		"ќѝўѶѷӁӂӐӑӒӓӖӗӚӛӜӝӞӟӢӣӤӥӦ" +
+		"ӧӪӫӬӭӮӯӰӱӲӳӴӵӸӹḀḁḂḃḄḅḆḇḈ" +
+		"ḉḊḋḌḍḎḏḐḑḒḓḔḕḖḗḘḙḚḛḜḝḞḟḠ" +
+		"ḡḢḣḤḥḦḧḨḩḪḫḬḭḮḯḰḱḲḳḴḵḶḷḸ" +
+		"ḹḺḻḼḽḾḿṀṁṂṃṄṅṆṇṈṉṊṋṌṍṎṏṐ" +
+		"ṑṒṓṔṕṖṗṘṙṚṛṜṝṞṟṠṡṢṣṤṥṦṧṨ" +
+		"ṩṪṫṬṭṮṯṰṱṲṳṴṵṶṷṸṹṺṻṼṽṾṿẀ" +
+		"ẁẂẃẄẅẆẇẈẉẊẋẌẍẎẏẐẑẒẓẔẕẖẗẘ" +
+		"ẙẛẠạẢảẤấẦầẨẩẪẫẬậẮắẰằẲẳẴẵ" +
+		"ẶặẸẹẺẻẼẽẾếỀềỂểỄễỆệỈỉỊịỌọ" +
+		"ỎỏỐốỒồỔổỖỗỘộỚớỜờỞởỠỡỢợỤụ" +
+		"ỦủỨứỪừỬửỮữỰựỲỳỴỵỶỷỸỹἀἁἂἃ" +
+		"ἄἅἆἇἈἉἊἋἌἍἎἏἐἑἒἓἔἕἘἙἚἛἜἝ" +
+		"ἠἡἢἣἤἥἦἧἨἩἪἫἬἭἮἯἰἱἲἳἴἵἶἷ" +
+		"ἸἹἺἻἼἽἾἿὀὁὂὃὄὅὈὉὊὋὌὍὐὑὒὓ" +
+		"ὔὕὖὗὙὛὝὟὠὡὢὣὤὥὦὧὨὩὪὫὬὭὮὯ" +
+		"ὰάὲέὴήὶίὸόὺύὼώᾀᾁᾂᾃᾄᾅᾆᾇᾈᾉ" +
+		"ᾊᾋᾌᾍᾎᾏᾐᾑᾒᾓᾔᾕᾖᾗᾘᾙᾚᾛᾜᾝᾞᾟᾠᾡ" +
+		"ᾢᾣᾤᾥᾦᾧᾨᾩᾪᾫᾬᾭᾮᾯᾰᾱᾲᾳᾴᾶᾷᾸᾹᾺ" +
+		"Άᾼ῁ῂῃῄῆῇῈΈῊΉῌ῍῎῏ῐῑῒΐῖῗῘῙ" +
+		"ῚΊ῝῞῟ῠῡῢΰῤῥῦῧῨῩῪΎῬ῭΅ῲῳῴῶ" +
+		"ῷῸΌῺΏῼ"
+	// unaccented holds the letters in accented without their accents
+	unaccented = "" +
+		"AAAAAACEEEEIIIINOOOOOUUU" +
+		"UYaaaaaaceeeeiiiinooooou" +
+		"uuuyyAaAaAaCcCcCcCcDdEeE" +
+		"eEeEeEeGgGgGgGgHhIiIiIiI" +
+		"iIJjKkLlLlLlNnNnNnOoOoOo" +
+		"RrRrRrSsSsSsSsTtTtUuUuUu" +
+		"UuUuUuWwYyYZzZzZzOoUuAaI" +
+		"iOoUuUuUuUuUuAaAaÆæGgKkO" +
+		"oOoƷʒjGgNnAaÆæØøAaAaEeEe" +
+		"IiIiOoOoRrRrUuUuSsTtHhAa" +
+		"EeOoOoOoOoYy¨ΑΕΗΙΟΥΩιΙΥα" +
+		"εηιυιυουωϒϒЕЕГІКИУИиеегі" +
+		"киуѴѵЖжАаАаЕеӘәЖжЗзИиИиО" +
+		"оӨөЭэУуУуУуЧчЫыAaBbBbBbC" +
+		"cDdDdDdDdDdEeEeEeEeEeFfG" +
+		"gHhHhHhHhHhIiIiKkKkKkLlL" +
+		"lLlLlMmMmMmNnNnNnNnOoOoO" +
+		"oOoPpPpRrRrRrRrSsSsSsSsS" +
+		"sTtTtTtTtUuUuUuUuUuVvVvW" +
+		"wWwWwWwWwXxXxYyZzZzZzhtw" +
+		"yſAaAaAaAaAaAaAaAaAaAaAa" +
+		"AaEeEeEeEeEeEeEeEeIiIiOo" +
+		"OoOoOoOoOoOoOoOoOoOoOoUu" +
+		"UuUuUuUuUuUuYyYyYyYyαααα" +
+		"ααααΑΑΑΑΑΑΑΑεεεεεεΕΕΕΕΕΕ" +
+		"ηηηηηηηηΗΗΗΗΗΗΗΗιιιιιιιι" +
+		"ΙΙΙΙΙΙΙΙοοοοοοΟΟΟΟΟΟυυυυ" +
+		"υυυυΥΥΥΥωωωωωωωωΩΩΩΩΩΩΩΩ" +
+		"ααεεηηιιοουυωωααααααααΑΑ" +
+		"ΑΑΑΑΑΑηηηηηηηηΗΗΗΗΗΗΗΗωω" +
+		"ωωωωωωΩΩΩΩΩΩΩΩαααααααΑΑΑ" +
+		"ΑΑ¨ηηηηηΕΕΗΗΗ᾿᾿᾿ιιιιιιΙΙ" +
+		"ΙΙ῾῾῾υυυυρρυυΥΥΥΥΡ¨¨ωωωω" +
+		"ωΟΟΩΩΩ"
+)
+
+// fullCaseFolds maps those runes whose Unicode case folding is more than a
+// single rune to their folded form
+var fullCaseFolds = map[rune]string{
+	'\u00df': "ss",            // ß
+	'\u0130': "i\u0307",       // İ
+	'\u0149': "\u02bcn",       // ŉ
+	'\u01f0': "j\u030c",       // ǰ
+	'\u0390': "ι\u0308\u0301", // ΐ
+	'\u03b0': "υ\u0308\u0301", // ΰ
+	'\u0587': "եւ",            // և
+	'\u1e96': "h\u0331",       // ẖ
+	'\u1e97': "t\u0308",       // ẗ
+	'\u1e98': "w\u030a",       // ẘ
+	'\u1e99': "y\u030a",       // ẙ
+	'\u1e9a': "a\u02be",       // ẚ
+	'\u1e9e': "ss",            // ẞ
+	'\u1f50': "υ\u0313",       // ὐ
+	'\u1f52': "υ\u0313\u0300", // ὒ
+	'\u1f54': "υ\u0313\u0301", // ὔ
+	'\u1f56': "υ\u0313\u0342", // ὖ
+	'\u1f80': "ἀι",            // ᾀ
+	'\u1f81': "ἁι",            // ᾁ
+	'\u1f82': "ἂι",            // ᾂ
+	'\u1f83': "ἃι",            // ᾃ
+	'\u1f84': "ἄι",            // ᾄ
+	'\u1f85': "ἅι",            // ᾅ
+	'\u1f86': "ἆι",            // ᾆ
+	'\u1f87': "ἇι",            // ᾇ
+	'\u1f88': "ἀι",            // ᾈ
+	'\u1f89': "ἁι",            // ᾉ
+	'\u1f8a': "ἂι",            // ᾊ
+	'\u1f8b': "ἃι",            // ᾋ
+	'\u1f8c': "ἄι",            // ᾌ
+	'\u1f8d': "ἅι",            // ᾍ
+	'\u1f8e': "ἆι",            // ᾎ
+	'\u1f8f': "ἇι",            // ᾏ
+	'\u1f90': "ἠι",            // ᾐ
+	'\u1f91': "ἡι",            // ᾑ
+	'\u1f92': "ἢι",            // ᾒ
+	'\u1f93': "ἣι",            // ᾓ
+	'\u1f94': "ἤι",            // ᾔ
+	'\u1f95': "ἥι",            // ᾕ
+	'\u1f96': "ἦι",            // ᾖ
+	'\u1f97': "ἧι",            // ᾗ
+	'\u1f98': "ἠι",            // ᾘ
+	'\u1f99': "ἡι",            // ᾙ
+	'\u1f9a': "ἢι",            // ᾚ
+	'\u1f9b': "ἣι",            // ᾛ
+	'\u1f9c': "ἤι",            // ᾜ
+	'\u1f9d': "ἥι",            // ᾝ
+	'\u1f9e': "ἦι",            // ᾞ
+	'\u1f9f': "ἧι",            // ᾟ
+	'\u1fa0': "ὠι",            // ᾠ
+	'\u1fa1': "ὡι",            // ᾡ
+	'\u1fa2': "ὢι",            // ᾢ
+	'\u1fa3': "ὣι",            // ᾣ
+	'\u1fa4': "ὤι",            // ᾤ
+	'\u1fa5': "ὥι",            // ᾥ
+	'\u1fa6': "ὦι",            // ᾦ
+	'\u1fa7': "ὧι",            // ᾧ
+	'\u1fa8': "ὠι",            // ᾨ
+	'\u1fa9': "ὡι",            // ᾩ
+	'\u1faa': "ὢι",            // ᾪ
+	'\u1fab': "ὣι",            // ᾫ
+	'\u1fac': "ὤι",            // ᾬ
+	'\u1fad': "ὥι",            // ᾭ
+	'\u1fae': "ὦι",            // ᾮ
+	'\u1faf': "ὧι",            // ᾯ
+	'\u1fb2': "ὰι",            // ᾲ
+	'\u1fb3': "αι",            // ᾳ
+	'\u1fb4': "άι",            // ᾴ
+	'\u1fb6': "α\u0342",       // ᾶ
+	'\u1fb7': "α\u0342ι",      // ᾷ
+	'\u1fbc': "αι",            // ᾼ
+	'\u1fc2': "ὴι",            // ῂ
+	'\u1fc3': "ηι",            // ῃ
+	'\u1fc4': "ήι",            // ῄ
+	'\u1fc6': "η\u0342",       // ῆ
+	'\u1fc7': "η\u0342ι",      // ῇ
+	'\u1fcc': "ηι",            // ῌ
+	'\u1fd2': "ι\u0308\u0300", // ῒ
+	'\u1fd3': "ι\u0308\u0301", // ΐ
+	'\u1fd6': "ι\u0342",       // ῖ
+	'\u1fd7': "ι\u0308\u0342", // ῗ
+	'\u1fe2': "υ\u0308\u0300", // ῢ
+	'\u1fe3': "υ\u0308\u0301", // ΰ
+	'\u1fe4': "ρ\u0313",       // ῤ
+	'\u1fe6': "υ\u0342",       // ῦ
+	'\u1fe7': "υ\u0308\u0342", // ῧ
+	'\u1ff2': "ὼι",            // ῲ
+	'\u1ff3': "ωι",            // ῳ
+	'\u1ff4': "ώι",            // ῴ
+	'\u1ff6': "ω\u0342",       // ῶ
+	'\u1ff7': "ω\u0342ι",      // ῷ
+	'\u1ffc': "ωι",            // ῼ
+	'\ufb00': "ff",            // ﬀ
+	'\ufb01': "fi",            // ﬁ
+	'\ufb02': "fl",            // ﬂ
+	'\ufb03': "ffi",           // ﬃ
+	'\ufb04': "ffl",           // ﬄ
+	'\ufb05': "st",            // ﬅ
+	'\ufb06': "st",            // ﬆ
+	'\ufb13': "մն",            // ﬓ
+	'\ufb14': "մե",            // ﬔ
+	'\ufb15': "մի",            // ﬕ
+	'\ufb16': "վն",            // ﬖ
+	'\ufb17': "մխ",            // ﬗ
+}
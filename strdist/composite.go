@@ -0,0 +1,272 @@
+package strdist
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DfltCompositeThreshold is a default value for deciding whether a blended
+// distance between two strings is sufficiently small for them to be
+// considered similar
+const DfltCompositeThreshold = 0.4
+
+// DfltPrefixBoost and DfltSubstringBoost are default amounts by which the
+// blended distance is reduced if the target string is a prefix or a
+// substring of the other string
+const (
+	DfltPrefixBoost    = 0.3
+	DfltSubstringBoost = 0.15
+)
+
+// DfltCompositeFinder is a CompositeFinder with some default values already
+// set. It blends the scaled keyboard-weighted edit distance, the cosine
+// distance (with n-grams of length 2) and the Jaro-Winkler distance.
+var DfltCompositeFinder *CompositeFinder
+
+// CaseBlindCompositeFinder is a CompositeFinder with some default values
+// already set (see DfltCompositeFinder). CaseMod is set to ForceToLower.
+var CaseBlindCompositeFinder *CompositeFinder
+
+func init() {
+	var err error
+	DfltCompositeFinder, err = NewCompositeFinder(
+		DfltMinStrLen, DfltCompositeThreshold, NoCaseChange,
+		dfltCompositeAlgos()...)
+	if err != nil {
+		panic("Cannot construct the default CompositeFinder: " + err.Error())
+	}
+	CaseBlindCompositeFinder, err = NewCompositeFinder(
+		DfltMinStrLen, DfltCompositeThreshold, ForceToLower,
+		dfltCompositeAlgos()...)
+	if err != nil {
+		panic("Cannot construct the case-blind CompositeFinder: " +
+			err.Error())
+	}
+}
+
+// dfltCompositeAlgos returns the algorithms used by the default
+// CompositeFinders
+func dfltCompositeAlgos() []WeightedAlgo {
+	return []WeightedAlgo{
+		{
+			Algo: &ScaledAlgo{
+				Algo: &WeightedEditAlgo{
+					Costs: KeyboardEditCosts(QWERTY, DfltAdjacentKeyCost),
+				},
+			},
+			Weight: 1,
+		},
+		{Algo: &CosineAlgo{N: 2}, Weight: 1},
+		{Algo: &JaroWinklerAlgo{}, Weight: 1},
+	}
+}
+
+// ScaledAlgo wraps an Algo giving an absolute distance (such as a
+// Levenshtein distance) and scales the distance by dividing it by the length
+// of the longer of the two strings. This gives a distance which is
+// comparable with those of other algorithms. Two zero-length strings are
+// taken as identical (with a zero distance between them)
+type ScaledAlgo struct {
+	Algo Algo
+}
+
+// scaledQuery is the Query returned by a ScaledAlgo
+type scaledQuery struct {
+	q         Query
+	cm        CaseMod
+	targetLen int
+}
+
+// PrepQuery for a ScaledAlgo prepares the wrapped algorithm. The length of
+// the target is that of the string after the CaseMod has been applied as
+// this is the string the wrapped algorithm compares
+func (a *ScaledAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return scaledQuery{
+		q:         a.Algo.PrepQuery(s, cm),
+		cm:        cm,
+		targetLen: utf8.RuneCountInString(cm.Apply(s)),
+	}
+}
+
+// Dist for a scaledQuery will calculate the distance given by the wrapped
+// algorithm scaled by the length of the longer string. As for the target,
+// the length of the string is measured after the CaseMod has been applied
+func (q scaledQuery) Dist(s string) float64 {
+	maxLen := q.targetLen
+	if l := utf8.RuneCountInString(q.cm.Apply(s)); l > maxLen {
+		maxLen = l
+	}
+	if maxLen == 0 {
+		return 0.0
+	}
+	return q.q.Dist(s) / float64(maxLen)
+}
+
+// WeightedAlgo records an Algo and the weight it is given when
+// calculating the blended distance in a CompositeFinder. The algorithm
+// should give distances between 0 and 1; any distance greater than 1 is
+// taken as 1.
+type WeightedAlgo struct {
+	Algo   Algo
+	Weight float64
+}
+
+// CompositeFinder records the parameters of a finder which blends the
+// distances from several algorithms. The blended distance is the weighted
+// average of the distances from each of the algorithms. This is then
+// reduced by the PrefixBoost if the target string is a prefix of the other
+// string or else by the SubstringBoost if the target string is a substring
+// of the other string. The blended distance is never less than 0.
+//
+// Target strings which are shorter than the MinStrLen are treated
+// differently. For these short targets the threshold is not used; instead a
+// string is similar if the target is a prefix of it or, if the target has
+// more than one character, if it can be reached from the target by a single
+// edit (see OSADistance). Similar strings are still ranked by their blended
+// distance.
+type CompositeFinder struct {
+	// MinStrLen records the minimum length (in runes) of string to be
+	// matched using the threshold
+	MinStrLen int
+	// T is the threshold for similarity for this finder
+	T float64
+	// CM gives any changes to be made to the strings (such as converting
+	// them to lower case) before generating the distance
+	CM CaseMod
+	// Algos are the algorithms whose distances are blended
+	Algos []WeightedAlgo
+
+	// PrefixBoost is the amount by which the blended distance is reduced
+	// if the target is a prefix of the other string
+	PrefixBoost float64
+	// SubstringBoost is the amount by which the blended distance is reduced
+	// if the target is a substring, but not a prefix, of the other string
+	SubstringBoost float64
+}
+
+// NewCompositeFinder checks that the parameters are valid and creates a new
+// CompositeFinder if they are. The minStrLen and threshold must each be >=
+// 0, there must be at least one algorithm and every algorithm must have a
+// weight > 0. The PrefixBoost and SubstringBoost are set to their default
+// values.
+func NewCompositeFinder(minStrLen int, threshold float64, cm CaseMod, algos ...WeightedAlgo) (*CompositeFinder, error) {
+	if minStrLen < 0 {
+		return nil,
+			fmt.Errorf("bad minimum string length (%d) - it should be >= 0",
+				minStrLen)
+	}
+	if threshold < 0.0 {
+		return nil,
+			fmt.Errorf("bad threshold (%f) - it should be >= 0.0", threshold)
+	}
+	if len(algos) == 0 {
+		return nil, fmt.Errorf("no algorithms have been given")
+	}
+	for i, wa := range algos {
+		if wa.Algo == nil {
+			return nil, fmt.Errorf("algorithm %d is nil", i)
+		}
+		if wa.Weight <= 0.0 {
+			return nil,
+				fmt.Errorf("bad weight (%f) for algorithm %d"+
+					" - it should be > 0.0", wa.Weight, i)
+		}
+	}
+
+	return &CompositeFinder{
+		MinStrLen:      minStrLen,
+		T:              threshold,
+		CM:             cm,
+		Algos:          algos,
+		PrefixBoost:    DfltPrefixBoost,
+		SubstringBoost: DfltSubstringBoost,
+	}, nil
+}
+
+// dist returns the blended distance between the target string and the
+// other string (p). The queries are those prepared from the target by each
+// of the algorithms and the modTarget is the target with the CaseMod applied
+func (f *CompositeFinder) dist(queries []Query, modTarget, p string) float64 {
+	var d, totWeight float64
+	for i, wa := range f.Algos {
+		d += wa.Weight * math.Min(queries[i].Dist(p), 1.0)
+		totWeight += wa.Weight
+	}
+	d /= totWeight
+
+	modP := f.CM.Apply(p)
+	if strings.HasPrefix(modP, modTarget) {
+		d -= f.PrefixBoost
+	} else if strings.Contains(modP, modTarget) {
+		d -= f.SubstringBoost
+	}
+
+	return math.Max(d, 0.0)
+}
+
+// FindLike returns StrDists for those strings in the population (pop) which
+// are similar to the string (s). See the description of the CompositeFinder
+// for how similarity is decided
+func (f *CompositeFinder) FindLike(s string, pop ...string) []StrDist {
+	lp := len(pop)
+	if lp == 0 || s == "" {
+		return []StrDist{}
+	}
+
+	dists := make([]StrDist, 0, lp)
+
+	queries := make([]Query, 0, len(f.Algos))
+	for _, wa := range f.Algos {
+		queries = append(queries, wa.Algo.PrepQuery(s, f.CM))
+	}
+	modTarget := f.CM.Apply(s)
+	short := utf8.RuneCountInString(s) < f.MinStrLen
+
+	for _, p := range pop {
+		if short {
+			if !shortMatch(modTarget, f.CM.Apply(p)) {
+				continue
+			}
+		} else if utf8.RuneCountInString(p) < f.MinStrLen {
+			continue
+		}
+
+		d := f.dist(queries, modTarget, p)
+		if !short && d > f.T {
+			continue
+		}
+
+		dists = append(dists, StrDist{
+			Str:  p,
+			Dist: d,
+		})
+	}
+
+	sort.Slice(dists, func(i, j int) bool { return SDSlice(dists).Cmp(i, j) })
+	return dists
+}
+
+// shortMatch returns true if the short target is a prefix of the string or
+// if the target has more than one character and the string is a single edit
+// away from it
+func shortMatch(target, s string) bool {
+	if strings.HasPrefix(s, target) {
+		return true
+	}
+	return utf8.RuneCountInString(target) > 1 && OSADistance(target, s) <= 1
+}
+
+// FindStrLike returns those strings in the population (pop) which are
+// similar to the string (s). Similarity is as for the FindLike method.
+func (f *CompositeFinder) FindStrLike(s string, pop ...string) []string {
+	return convertStrDist(f.FindLike(s, pop...))
+}
+
+// FindNStrLike returns the first n strings in the population (pop) which are
+// similar to the string (s). Similarity is as for the FindLike method.
+func (f *CompositeFinder) FindNStrLike(n int, s string, pop ...string) []string {
+	return convertStrDistN(n, f.FindLike(s, pop...))
+}
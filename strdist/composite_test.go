@@ -0,0 +1,196 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+	"github.com/nickwells/golem/testhelper"
+)
+
+func TestCompositeFinder(t *testing.T) {
+	pop := []string{
+		"verbose",
+		"version",
+		"Verbosity",
+		"params",
+		"value",
+		"v",
+		"vx",
+		"name",
+		"rename",
+		"x",
+	}
+	testCases := []struct {
+		name   string
+		finder *strdist.CompositeFinder
+		target string
+		expect []string
+	}{
+		{
+			name:   "prefix",
+			finder: strdist.DfltCompositeFinder,
+			target: "verb",
+			expect: []string{"verbose"},
+		},
+		{
+			name:   "prefix, case-blind",
+			finder: strdist.CaseBlindCompositeFinder,
+			target: "verb",
+			expect: []string{"verbose", "Verbosity"},
+		},
+		{
+			name:   "transposition",
+			finder: strdist.DfltCompositeFinder,
+			target: "parmas",
+			expect: []string{"params"},
+		},
+		{
+			name:   "adjacent key",
+			finder: strdist.DfltCompositeFinder,
+			target: "vakue",
+			expect: []string{"value"},
+		},
+		{
+			name:   "substring",
+			finder: strdist.DfltCompositeFinder,
+			target: "name",
+			expect: []string{"name", "rename"},
+		},
+		{
+			name:   "short target - prefix",
+			finder: strdist.DfltCompositeFinder,
+			target: "ver",
+			expect: []string{"verbose", "version"},
+		},
+		{
+			name:   "short target - single edit",
+			finder: strdist.DfltCompositeFinder,
+			target: "xv",
+			expect: []string{"x", "v", "vx"},
+		},
+		{
+			name:   "short target - single char",
+			finder: strdist.DfltCompositeFinder,
+			target: "x",
+			expect: []string{"x"},
+		},
+		{
+			name:   "empty target",
+			finder: strdist.DfltCompositeFinder,
+			target: "",
+			expect: []string{},
+		},
+		{
+			name:   "no match",
+			finder: strdist.DfltCompositeFinder,
+			target: "xyzzy",
+			expect: []string{},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		results := tc.finder.FindStrLike(tc.target, pop...)
+		if testhelper.StringSliceDiff(tc.expect, results) {
+			t.Log(tcID)
+			t.Logf("\t: target: %q", tc.target)
+			t.Logf("\t: expected: %v", tc.expect)
+			t.Logf("\t:      got: %v", results)
+			t.Errorf("\t: results are unexpected\n")
+		}
+	}
+}
+
+func TestNewCompositeFinder(t *testing.T) {
+	goodAlgo := strdist.WeightedAlgo{Algo: &strdist.JaroAlgo{}, Weight: 1}
+	testCases := []struct {
+		name      string
+		minStrLen int
+		threshold float64
+		algos     []strdist.WeightedAlgo
+		expErrStr string
+	}{
+		{
+			name:      "good",
+			minStrLen: 4,
+			threshold: 0.5,
+			algos:     []strdist.WeightedAlgo{goodAlgo},
+		},
+		{
+			name:      "bad min length",
+			minStrLen: -1,
+			algos:     []strdist.WeightedAlgo{goodAlgo},
+			expErrStr: "bad minimum string length (-1) - it should be >= 0",
+		},
+		{
+			name:      "bad threshold",
+			threshold: -1,
+			algos:     []strdist.WeightedAlgo{goodAlgo},
+			expErrStr: "bad threshold (-1.000000) - it should be >= 0.0",
+		},
+		{
+			name:      "no algos",
+			expErrStr: "no algorithms have been given",
+		},
+		{
+			name:      "nil algo",
+			algos:     []strdist.WeightedAlgo{goodAlgo, {Weight: 1}},
+			expErrStr: "algorithm 1 is nil",
+		},
+		{
+			name: "bad weight",
+			algos: []strdist.WeightedAlgo{
+				{Algo: &strdist.JaroAlgo{}},
+			},
+			expErrStr: "bad weight (0.000000) for algorithm 0" +
+				" - it should be > 0.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		_, err := strdist.NewCompositeFinder(
+			tc.minStrLen, tc.threshold, strdist.NoCaseChange, tc.algos...)
+		if tc.expErrStr == "" {
+			if err != nil {
+				t.Log(tcID)
+				t.Errorf("\t: unexpected error: %s", err)
+			}
+		} else if err == nil {
+			t.Log(tcID)
+			t.Errorf("\t: an error was expected but none was returned")
+		} else if err.Error() != tc.expErrStr {
+			t.Log(tcID)
+			t.Logf("\t: expected: %s", tc.expErrStr)
+			t.Logf("\t:      got: %s", err)
+			t.Errorf("\t: unexpected error")
+		}
+	}
+}
+
+func TestScaledAlgo(t *testing.T) {
+	testCases := []struct {
+		name    string
+		target  string
+		s       string
+		cm      strdist.CaseMod
+		expDist float64
+	}{
+		{name: "no change", target: "abcd", s: "abce", cm: strdist.NoCaseChange, expDist: 0.25},
+		{name: "empty strings", target: "", s: "", cm: strdist.NoCaseChange, expDist: 0},
+		{name: "folded target", target: "ß", s: "x", cm: strdist.UnicodeFold, expDist: 1},
+		{name: "folded string", target: "x", s: "ß", cm: strdist.UnicodeFold, expDist: 1},
+		{name: "folded, equal", target: "ß", s: "SS", cm: strdist.UnicodeFold, expDist: 0},
+	}
+
+	a := &strdist.ScaledAlgo{Algo: &strdist.DamerauLevenshteinAlgo{}}
+	for i, tc := range testCases {
+		testID := fmt.Sprintf("test %d: %s", i, tc.name)
+		d := a.PrepQuery(tc.target, tc.cm).Dist(tc.s)
+		if d != tc.expDist {
+			t.Log(testID)
+			t.Errorf("\t: the distance should be %g, not %g", tc.expDist, d)
+		}
+	}
+}
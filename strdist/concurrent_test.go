@@ -0,0 +1,255 @@
+package strdist_test
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+// oldLevAlgo is a DistAlgo in the original style, caching the target string
+// in the algorithm itself
+type oldLevAlgo struct {
+	target string
+}
+
+func (a *oldLevAlgo) Prep(s string, cm strdist.CaseMod) {
+	a.target = cm.Apply(s)
+}
+
+func (a *oldLevAlgo) Dist(_, s string, cm strdist.CaseMod) float64 {
+	return float64(strdist.LevenshteinDistance(a.target, cm.Apply(s)))
+}
+
+// newOldLevAlgo returns a new oldLevAlgo as a DistAlgo
+func newOldLevAlgo() strdist.DistAlgo { return &oldLevAlgo{} }
+
+// sdDiff returns true if the StrDists differ
+func sdDiff(a, b []strdist.StrDist) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for i := range a {
+		if a[i].Str != b[i].Str || math.Abs(a[i].Dist-b[i].Dist) > 1e-9 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAdaptDistAlgo(t *testing.T) {
+	f, err := strdist.NewFinder(strdist.DfltMinStrLen,
+		strdist.DfltLevenshteinThreshold, strdist.NoCaseChange,
+		&oldLevAlgo{})
+	if err != nil {
+		t.Fatal("couldn't create the Finder: ", err)
+	}
+	pop := []string{"hello", "world", "help", "held", "hollow", "yellow"}
+
+	for i, s := range []string{"hello", "word", "hold"} {
+		tcID := fmt.Sprintf("test %d: %s", i, s)
+		exp := strdist.DfltLevenshteinFinder.FindLike(s, pop...)
+		got := f.FindLike(s, pop...)
+		if sdDiff(got, exp) {
+			t.Log(tcID)
+			t.Logf("\t: expected: %v", exp)
+			t.Logf("\t:      got: %v", got)
+			t.Errorf("\t: the adapted DistAlgo gave unexpected results")
+		}
+	}
+
+	// Queries from the same adapted algo used alternately must each keep
+	// their own target
+	a := strdist.AdaptDistAlgo(&oldLevAlgo{})
+	q1 := a.PrepQuery("hello", strdist.NoCaseChange)
+	q2 := a.PrepQuery("world", strdist.NoCaseChange)
+	for i := 0; i < 2; i++ {
+		if d := q1.Dist("hello"); d != 0 {
+			t.Errorf("pass %d: q1.Dist(\"hello\") should be 0, got: %f", i, d)
+		}
+		if d := q2.Dist("world"); d != 0 {
+			t.Errorf("pass %d: q2.Dist(\"world\") should be 0, got: %f", i, d)
+		}
+	}
+}
+
+func TestDistAlgoCompatibility(t *testing.T) {
+	algos := []struct {
+		name string
+		a    interface {
+			strdist.Algo
+			strdist.DistAlgo
+		}
+	}{
+		{name: "Levenshtein", a: &strdist.LevenshteinAlgo{}},
+		{name: "OSA", a: &strdist.OSAAlgo{}},
+		{name: "Jaro", a: &strdist.JaroAlgo{}},
+		{name: "LCS", a: &strdist.LCSAlgo{}},
+		{name: "Hamming", a: &strdist.HammingAlgo{}},
+		{name: "TokenSort", a: &strdist.TokenSortAlgo{}},
+	}
+	pairs := [][2]string{
+		{"hello", "hallo"},
+		{"Hello", "hello"},
+		{"world", "word"},
+	}
+
+	for i, tc := range algos {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		for _, p := range pairs {
+			exp := tc.a.PrepQuery(p[0], strdist.ForceToLower).Dist(p[1])
+			tc.a.Prep(p[0], strdist.ForceToLower)
+			got := tc.a.Dist(p[0], p[1], strdist.ForceToLower)
+			if got != exp {
+				t.Log(tcID)
+				t.Errorf("\t: Dist(%q, %q) should be %f, got %f",
+					p[0], p[1], exp, got)
+			}
+		}
+	}
+}
+
+func TestConcurrentFinders(t *testing.T) {
+	pop := append(makePop(300, 2, "abcdeilmnorstuAEI-", 2, 12),
+		"test", "Test", "tset", "test-case", "case-test", "testing")
+	targets := []string{"test", "Test", "test-case", "estimates", "mission"}
+
+	adapted, err := strdist.NewAlgoFinder(strdist.DfltMinStrLen,
+		strdist.DfltLevenshteinThreshold, strdist.NoCaseChange,
+		strdist.AdaptDistAlgo(&oldLevAlgo{}))
+	if err != nil {
+		t.Fatal("couldn't create the Finder: ", err)
+	}
+
+	adaptedFunc, err := strdist.NewAlgoFinder(strdist.DfltMinStrLen,
+		strdist.DfltLevenshteinThreshold, strdist.NoCaseChange,
+		strdist.AdaptDistAlgoFunc(newOldLevAlgo))
+	if err != nil {
+		t.Fatal("couldn't create the Finder: ", err)
+	}
+
+	finders := []struct {
+		name string
+		find func(s string) []strdist.StrDist
+	}{
+		{"cosine", func(s string) []strdist.StrDist {
+			return strdist.DfltCosineFinder.FindLike(s, pop...)
+		}},
+		{"jaccard", func(s string) []strdist.StrDist {
+			return strdist.CaseBlindJaccardFinder.FindLike(s, pop...)
+		}},
+		{"levenshtein", func(s string) []strdist.StrDist {
+			return strdist.DfltLevenshteinFinder.FindLike(s, pop...)
+		}},
+		{"phonetic", func(s string) []strdist.StrDist {
+			return strdist.DfltPhoneticFinder.FindLike(s, pop...)
+		}},
+		{"tokenSet", func(s string) []strdist.StrDist {
+			return strdist.CaseBlindTokenSetFinder.FindLike(s, pop...)
+		}},
+		{"mongeElkan", func(s string) []strdist.StrDist {
+			return strdist.DfltMongeElkanFinder.FindLike(s, pop...)
+		}},
+		{"composite", func(s string) []strdist.StrDist {
+			return strdist.CaseBlindCompositeFinder.FindLike(s, pop...)
+		}},
+		{"index", func() func(s string) []strdist.StrDist {
+			idx := strdist.NewIndex(strdist.DfltLevenshteinFinder, pop...)
+			return idx.FindLike
+		}()},
+		{"adapted", func(s string) []strdist.StrDist {
+			return adapted.FindLike(s, pop...)
+		}},
+		{"adaptedFunc", func(s string) []strdist.StrDist {
+			return adaptedFunc.FindLike(s, pop...)
+		}},
+	}
+
+	for _, f := range finders {
+		exp := make(map[string][]strdist.StrDist)
+		for _, s := range targets {
+			exp[s] = f.find(s)
+		}
+
+		const goroutines = 8
+		errs := make(chan string, goroutines*len(targets))
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := range targets {
+					s := targets[(i+g)%len(targets)]
+					if sdDiff(f.find(s), exp[s]) {
+						errs <- s
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		close(errs)
+
+		for s := range errs {
+			t.Log(f.name)
+			t.Errorf("\t: concurrent results for %q differ from sequential ones",
+				s)
+		}
+	}
+}
+
+func TestFindLikeParallel(t *testing.T) {
+	pop := append(makePop(1000, 3, "abcdeilmnorstuAEI", 2, 9),
+		"test", "Test", "tset", "testing")
+
+	finders := []struct {
+		name string
+		f    *strdist.Finder
+	}{
+		{"levenshtein", strdist.DfltLevenshteinFinder},
+		{"jaccard", strdist.CaseBlindJaccardFinder},
+		{"phonetic", strdist.DfltPhoneticFinder},
+		{"mongeElkan", strdist.DfltMongeElkanFinder},
+		{"adaptedFunc", &strdist.Finder{
+			MinStrLen: strdist.DfltMinStrLen,
+			T:         strdist.DfltLevenshteinThreshold,
+			Algo:      strdist.AdaptDistAlgoFunc(newOldLevAlgo),
+		}},
+	}
+
+	for _, fc := range finders {
+		for _, s := range []string{"test", "Test", "mission", "t"} {
+			exp := fc.f.FindLike(s, pop...)
+			for _, workers := range []int{-1, 0, 1, 3, 7, len(pop) + 5} {
+				tcID := fmt.Sprintf("%s: %q with %d workers",
+					fc.name, s, workers)
+				got := fc.f.FindLikeParallel(s, workers, pop...)
+				if sdDiff(got, exp) {
+					t.Log(tcID)
+					t.Logf("\t: expected: %v", exp)
+					t.Logf("\t:      got: %v", got)
+					t.Errorf("\t: FindLikeParallel differs from FindLike")
+				}
+			}
+		}
+	}
+
+	if got := strdist.DfltLevenshteinFinder.FindLikeParallel("test", 4); len(got) != 0 {
+		t.Errorf("an empty population should give no results, got: %v", got)
+	}
+}
+
+func BenchmarkFindLike(b *testing.B) {
+	pop := makePop(20000, 4, "abcdeilmnorstu", 3, 12)
+	for i := 0; i < b.N; i++ {
+		strdist.DfltMongeElkanFinder.FindLike("estimates", pop...)
+	}
+}
+
+func BenchmarkFindLikeParallel(b *testing.B) {
+	pop := makePop(20000, 4, "abcdeilmnorstu", 3, 12)
+	for i := 0; i < b.N; i++ {
+		strdist.DfltMongeElkanFinder.FindLikeParallel("estimates", 0, pop...)
+	}
+}
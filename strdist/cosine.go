@@ -3,7 +3,6 @@ package strdist
 import (
 	"fmt"
 	"math"
-	"strings"
 )
 
 const DfltCosineThreshold = 0.33
@@ -32,7 +31,13 @@ func init() {
 
 // CosineAlgo encapsulates the details needed to provide the cosine distance.
 type CosineAlgo struct {
-	N         int
+	N int
+}
+
+// cosineQuery is the Query returned by a CosineAlgo
+type cosineQuery struct {
+	n         int
+	cm        CaseMod
 	ngsTarget NGramSet
 	lenTarget float64
 }
@@ -52,27 +57,22 @@ func NewCosineFinder(ngLen, minStrLen int, threshold float64, cm CaseMod) (*Find
 	return NewFinder(minStrLen, threshold, cm, algo)
 }
 
-// Prep for a CosineAlgo will pre-calculate the n-gram set for the target string
-func (a *CosineAlgo) Prep(s string, cm CaseMod) {
-	switch cm {
-	case ForceToLower:
-		a.ngsTarget, _ = NGrams(strings.ToLower(s), a.N)
-	default:
-		a.ngsTarget, _ = NGrams(s, a.N)
+// PrepQuery for a CosineAlgo will pre-calculate the n-gram set for the
+// target string
+func (a *CosineAlgo) PrepQuery(s string, cm CaseMod) Query {
+	ngs, _ := NGrams(cm.Apply(s), a.N)
+	return cosineQuery{
+		n:         a.N,
+		cm:        cm,
+		ngsTarget: ngs,
+		lenTarget: ngs.Length(),
 	}
-	a.lenTarget = a.ngsTarget.Length()
 }
 
-// Dist for a CosineAlgo will calculate the distance from the target string
-func (a *CosineAlgo) Dist(_, s string, cm CaseMod) float64 {
-	var ngs NGramSet
-	switch cm {
-	case ForceToLower:
-		ngs, _ = NGrams(strings.ToLower(s), a.N)
-	default:
-		ngs, _ = NGrams(s, a.N)
-	}
-	return a.ngsTarget.cosineDistance(a.lenTarget, ngs)
+// Dist for a cosineQuery will calculate the distance from the target string
+func (q cosineQuery) Dist(s string) float64 {
+	ngs, _ := NGrams(q.cm.Apply(s), q.n)
+	return q.ngsTarget.cosineDistance(q.lenTarget, ngs)
 }
 
 // (ngs NGramSet)cosineDistance works out the cosine distance for a string
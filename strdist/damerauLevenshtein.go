@@ -0,0 +1,196 @@
+package strdist
+
+import (
+	"github.com/nickwells/golem/mathutil"
+)
+
+// DfltOSAFinder is a Finder with some suitable default values suitable for
+// an Optimal String Alignment algorithm already set.
+var DfltOSAFinder *Finder
+
+// CaseBlindOSAFinder is a Finder with some suitable default values suitable
+// for an Optimal String Alignment algorithm already set. CaseMod is set to
+// ForceToLower.
+var CaseBlindOSAFinder *Finder
+
+// DfltDamerauLevenshteinFinder is a Finder with some suitable default values
+// suitable for a Damerau-Levenshtein algorithm already set.
+var DfltDamerauLevenshteinFinder *Finder
+
+// CaseBlindDamerauLevenshteinFinder is a Finder with some suitable default
+// values suitable for a Damerau-Levenshtein algorithm already set. CaseMod
+// is set to ForceToLower.
+var CaseBlindDamerauLevenshteinFinder *Finder
+
+func init() {
+	var err error
+	DfltOSAFinder, err =
+		NewOSAFinder(DfltMinStrLen, DfltDamerauLevenshteinThreshold,
+			NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default OSAFinder: " + err.Error())
+	}
+	CaseBlindOSAFinder, err =
+		NewOSAFinder(DfltMinStrLen, DfltDamerauLevenshteinThreshold,
+			ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind OSAFinder: " + err.Error())
+	}
+	DfltDamerauLevenshteinFinder, err =
+		NewDamerauLevenshteinFinder(
+			DfltMinStrLen, DfltDamerauLevenshteinThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default DamerauLevenshteinFinder: " +
+			err.Error())
+	}
+	CaseBlindDamerauLevenshteinFinder, err =
+		NewDamerauLevenshteinFinder(
+			DfltMinStrLen, DfltDamerauLevenshteinThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind DamerauLevenshteinFinder: " +
+			err.Error())
+	}
+}
+
+// DfltDamerauLevenshteinThreshold is a default value for deciding whether a
+// distance between two strings is sufficiently small for them to be
+// considered similar. It is used for both the Optimal String Alignment and
+// the Damerau-Levenshtein finders
+const DfltDamerauLevenshteinThreshold = 5.0
+
+// OSAAlgo encapsulates the details needed to provide the Optimal String
+// Alignment distance.
+type OSAAlgo struct{}
+
+// NewOSAFinder returns a new Finder having an Optimal String Alignment algo
+// and an error which will be non-nil if the parameters are invalid - see
+// NewFinder for details.
+func NewOSAFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&OSAAlgo{})
+}
+
+// PrepQuery for an OSAAlgo will apply the CaseMod to the target string and
+// return a Query giving the Optimal String Alignment distance from it
+func (a *OSAAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, intDist(OSADistance))
+}
+
+// DamerauLevenshteinAlgo encapsulates the details needed to provide the
+// Damerau-Levenshtein distance.
+type DamerauLevenshteinAlgo struct{}
+
+// NewDamerauLevenshteinFinder returns a new Finder having a
+// Damerau-Levenshtein algo and an error which will be non-nil if the
+// parameters are invalid - see NewFinder for details.
+func NewDamerauLevenshteinFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&DamerauLevenshteinAlgo{})
+}
+
+// PrepQuery for a DamerauLevenshteinAlgo will apply the CaseMod to the target
+// string and return a Query giving the Damerau-Levenshtein distance from it
+func (a *DamerauLevenshteinAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, intDist(DamerauLevenshteinDistance))
+}
+
+// OSADistance calculates the Optimal String Alignment distance between
+// strings a and b. This is the Levenshtein distance but also allowing the
+// transposition of two adjacent characters as a single edit. Unlike the
+// true Damerau-Levenshtein distance no substring may be edited more than
+// once so, for instance, the distance between "ca" and "abc" is 3 rather
+// than 2.
+func OSADistance(a, b string) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	aLen := len(aRunes)
+	bLen := len(bRunes)
+
+	d := make([][]int, aLen+1)
+	for i := range d {
+		d[i] = make([]int, bLen+1)
+		d[i][0] = i
+	}
+
+	for j := 1; j <= bLen; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= aLen; i++ {
+		for j := 1; j <= bLen; j++ {
+			var subsCost int
+			if aRunes[i-1] != bRunes[j-1] {
+				subsCost = 1
+			}
+
+			del := d[i-1][j] + 1
+			ins := d[i][j-1] + 1
+			sub := d[i-1][j-1] + subsCost
+
+			d[i][j] = mathutil.MinOfInt(del, ins, sub)
+
+			if i > 1 && j > 1 &&
+				aRunes[i-1] == bRunes[j-2] &&
+				aRunes[i-2] == bRunes[j-1] {
+				d[i][j] = mathutil.MinOfInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[aLen][bLen]
+}
+
+// DamerauLevenshteinDistance calculates the Damerau-Levenshtein distance
+// between strings a and b. This is the minimum number of insertions,
+// deletions, substitutions and transpositions of two adjacent characters
+// needed to change one string into the other.
+func DamerauLevenshteinDistance(a, b string) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	aLen := len(aRunes)
+	bLen := len(bRunes)
+
+	// the last row in a in which each rune was seen
+	lastRow := make(map[rune]int)
+
+	// the matrix is offset by one from the usual Levenshtein matrix so
+	// that the first row and column can hold a value greater than any
+	// possible distance
+	maxDist := aLen + bLen
+	d := make([][]int, aLen+2)
+	for i := range d {
+		d[i] = make([]int, bLen+2)
+		d[i][0] = maxDist
+		if i > 0 {
+			d[i][1] = i - 1
+		}
+	}
+	for j := 1; j <= bLen+1; j++ {
+		d[0][j] = maxDist
+		d[1][j] = j - 1
+	}
+
+	for i := 1; i <= aLen; i++ {
+		lastMatchCol := 0
+		for j := 1; j <= bLen; j++ {
+			k := lastRow[bRunes[j-1]]
+			l := lastMatchCol
+
+			subsCost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				subsCost = 0
+				lastMatchCol = j
+			}
+
+			del := d[i][j+1] + 1
+			ins := d[i+1][j] + 1
+			sub := d[i][j] + subsCost
+			trans := d[k][l] + (i - k - 1) + 1 + (j - l - 1)
+
+			d[i+1][j+1] = mathutil.MinOfInt(del, ins, sub, trans)
+		}
+		lastRow[aRunes[i-1]] = i
+	}
+
+	return d[aLen+1][bLen+1]
+}
@@ -0,0 +1,134 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+func TestDamerauLevenshtein(t *testing.T) {
+	testCases := []struct {
+		name       string
+		a, b       string
+		expOSADist int
+		expDLDist  int
+	}{
+		{
+			name: "zero char same",
+		},
+		{
+			name:       "one empty",
+			a:          "abc",
+			expOSADist: 3,
+			expDLDist:  3,
+		},
+		{
+			name: "single char same",
+			a:    "a",
+			b:    "a",
+		},
+		{
+			name:       "single char differ",
+			a:          "a",
+			b:          "b",
+			expOSADist: 1,
+			expDLDist:  1,
+		},
+		{
+			name:       "transposition",
+			a:          "ab",
+			b:          "ba",
+			expOSADist: 1,
+			expDLDist:  1,
+		},
+		{
+			name:       "transposition and insertion",
+			a:          "ca",
+			b:          "abc",
+			expOSADist: 3,
+			expDLDist:  2,
+		},
+		{
+			name:       "typo",
+			a:          "receive",
+			b:          "recieve",
+			expOSADist: 1,
+			expDLDist:  1,
+		},
+		{
+			name:       "Kitten/Sitting",
+			a:          "Kitten",
+			b:          "Sitting",
+			expOSADist: 3,
+			expDLDist:  3,
+		},
+		{
+			name:       "multi-byte characters",
+			a:          "héllo",
+			b:          "hlélo",
+			expOSADist: 1,
+			expDLDist:  1,
+		},
+	}
+
+	for i, tc := range testCases {
+		for _, order := range []string{"a,b", "b,a"} {
+			a, b := tc.a, tc.b
+			if order == "b,a" {
+				a, b = b, a
+			}
+			tcID := fmt.Sprintf("test %d: %s (%s)", i, tc.name, order)
+			dist := strdist.OSADistance(a, b)
+			if dist != tc.expOSADist {
+				t.Log(tcID)
+				t.Errorf("\t: OSADistance(%q, %q) expected: %d got: %d",
+					a, b, tc.expOSADist, dist)
+			}
+			dist = strdist.DamerauLevenshteinDistance(a, b)
+			if dist != tc.expDLDist {
+				t.Log(tcID)
+				t.Errorf("\t: DamerauLevenshteinDistance(%q, %q)"+
+					" expected: %d got: %d",
+					a, b, tc.expDLDist, dist)
+			}
+		}
+	}
+}
+
+func TestDamerauLevenshteinFinder(t *testing.T) {
+	pop := []string{"TEHN", "then", "hten", "other"}
+	testCases := []struct {
+		name   string
+		finder *strdist.Finder
+		expect []string
+	}{
+		{
+			name:   "dflt OSA",
+			finder: strdist.DfltOSAFinder,
+			expect: []string{"then", "hten", "other", "TEHN"},
+		},
+		{
+			name:   "case-blind OSA",
+			finder: strdist.CaseBlindOSAFinder,
+			expect: []string{"then", "TEHN", "hten", "other"},
+		},
+		{
+			name:   "dflt Damerau-Levenshtein",
+			finder: strdist.DfltDamerauLevenshteinFinder,
+			expect: []string{"then", "hten", "other", "TEHN"},
+		},
+		{
+			name:   "case-blind Damerau-Levenshtein",
+			finder: strdist.CaseBlindDamerauLevenshteinFinder,
+			expect: []string{"then", "TEHN", "hten", "other"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		finderChecker(t, tcID, "", "then", pop, tc.finder, tc.expect)
+		finderCheckerMaxN(t, tcID, "", "then", pop, 2, tc.finder,
+			tc.expect[:2])
+	}
+}
@@ -0,0 +1,148 @@
+package strdist
+
+// The methods in this file allow the algorithms in this package to satisfy
+// the DistAlgo interface so that code written for the original interface
+// still works. The algorithms no longer record the target string and so
+// Prep does nothing and Dist prepares the first string each time it is
+// called. Use PrepQuery to compare a target string with several others.
+
+// distFrom returns the distance between s1 and s2 calculated by the Algo
+func distFrom(a Algo, s1, s2 string, cm CaseMod) float64 {
+	return a.PrepQuery(s1, cm).Dist(s2)
+}
+
+// Prep for a CosineAlgo does nothing
+func (a *CosineAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a CosineAlgo returns the distance between the two strings
+func (a *CosineAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for an OSAAlgo does nothing
+func (a *OSAAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for an OSAAlgo returns the distance between the two strings
+func (a *OSAAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a DamerauLevenshteinAlgo does nothing
+func (a *DamerauLevenshteinAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a DamerauLevenshteinAlgo returns the distance between the two strings
+func (a *DamerauLevenshteinAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a HammingAlgo does nothing
+func (a *HammingAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a HammingAlgo returns the distance between the two strings
+func (a *HammingAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a JaccardAlgo does nothing
+func (a *JaccardAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a JaccardAlgo returns the distance between the two strings
+func (a *JaccardAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a JaroAlgo does nothing
+func (a *JaroAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a JaroAlgo returns the distance between the two strings
+func (a *JaroAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a JaroWinklerAlgo does nothing
+func (a *JaroWinklerAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a JaroWinklerAlgo returns the distance between the two strings
+func (a *JaroWinklerAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for an LCSAlgo does nothing
+func (a *LCSAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for an LCSAlgo returns the distance between the two strings
+func (a *LCSAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a LevenshteinAlgo does nothing
+func (a *LevenshteinAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a LevenshteinAlgo returns the distance between the two strings
+func (a *LevenshteinAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a PhoneticAlgo does nothing
+func (a *PhoneticAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a PhoneticAlgo returns the distance between the two strings
+func (a *PhoneticAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a ScaledLevAlgo does nothing
+func (a *ScaledLevAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a ScaledLevAlgo returns the distance between the two strings
+func (a *ScaledLevAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a ScaledAlgo does nothing
+func (a *ScaledAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a ScaledAlgo returns the distance between the two strings
+func (a *ScaledAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a TokenSortAlgo does nothing
+func (a *TokenSortAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a TokenSortAlgo returns the distance between the two strings
+func (a *TokenSortAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a TokenSetAlgo does nothing
+func (a *TokenSetAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a TokenSetAlgo returns the distance between the two strings
+func (a *TokenSetAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a MongeElkanAlgo does nothing
+func (a *MongeElkanAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a MongeElkanAlgo returns the distance between the two strings
+func (a *MongeElkanAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a WeightedEditAlgo does nothing
+func (a *WeightedEditAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a WeightedEditAlgo returns the distance between the two strings
+func (a *WeightedEditAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
+
+// Prep for a WeightedJaccardAlgo does nothing
+func (a *WeightedJaccardAlgo) Prep(_ string, _ CaseMod) {}
+
+// Dist for a WeightedJaccardAlgo returns the distance between the two strings
+func (a *WeightedJaccardAlgo) Dist(s1, s2 string, cm CaseMod) float64 {
+	return distFrom(a, s1, s2, cm)
+}
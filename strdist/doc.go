@@ -0,0 +1,32 @@
+/*
+Package strdist provides ways of measuring the distance between strings and
+of finding those strings in a population which are similar to a target
+string.
+
+A Finder uses an Algo to calculate the distances. The Algo prepares the
+target string once, returning a Query from which the distance to each
+string in the population is calculated. An Algo and the Queries it returns
+do not change once they have been made and so the Finders can be used from
+several goroutines at once.
+
+# The DistAlgo interface
+
+The algorithms used to satisfy only the DistAlgo interface, with a Prep
+method which recorded the target string in the algorithm itself and a Dist
+method taking both strings. They now also satisfy the Algo interface, whose
+PrepQuery method returns a Query holding the prepared target string:
+
+	q := algo.PrepQuery(target, cm)
+	d := q.Dist(s)
+
+The DistAlgo methods are kept so existing code still compiles, but Prep
+now does nothing and Dist prepares the target string afresh on each call.
+
+NewFinder still takes a DistAlgo. If it is not also an Algo it is
+converted with AdaptDistAlgo; an Algo which is not a DistAlgo can be used
+with NewAlgoFinder. The Algo fields of the Finder, ScaledAlgo and
+WeightedAlgo now hold an Algo, so a DistAlgo of your own must be
+converted with AdaptDistAlgo or, if the distances should be calculated in
+parallel, with AdaptDistAlgoFunc before it is set there.
+*/
+package strdist
@@ -0,0 +1,632 @@
+package strdist
+
+import "strings"
+
+// doubleMetaphoneLen is the maximum length of a Double Metaphone code
+const doubleMetaphoneLen = 4
+
+// dmEncoder holds the state of a Double Metaphone encoding
+type dmEncoder struct {
+	// w holds the word being encoded padded with spaces so that checks for
+	// the characters following the end of the word will find a space
+	w              []rune
+	length         int
+	last           int
+	slavoGermanic  bool
+	primary, alter strings.Builder
+}
+
+// DoubleMetaphone returns the primary and alternate codes for the string
+// given by Lawrence Philips' Double Metaphone algorithm. This improves on
+// Metaphone by taking account of the pronunciation of words and names from
+// many languages other than English. Where a word might be pronounced in
+// two ways the alternate code will differ from the primary code, otherwise
+// they will be the same. The codes are at most four characters long.
+//
+// Only the letters A to Z are encoded (after any accents have been removed)
+// and case is ignored. A string with no such letters has empty codes.
+func DoubleMetaphone(s string) (primary, alternate string) {
+	letters := phoneticLetters(s, true)
+	if len(letters) == 0 {
+		return "", ""
+	}
+
+	e := &dmEncoder{
+		w:      append(letters, []rune("     ")...),
+		length: len(letters),
+		last:   len(letters) - 1,
+	}
+	word := string(letters)
+	e.slavoGermanic = strings.ContainsAny(word, "WK") ||
+		strings.Contains(word, "CZ") ||
+		strings.Contains(word, "WITZ")
+
+	e.encode()
+
+	primary = e.primary.String()
+	if len(primary) > doubleMetaphoneLen {
+		primary = primary[:doubleMetaphoneLen]
+	}
+	alternate = e.alter.String()
+	if len(alternate) > doubleMetaphoneLen {
+		alternate = alternate[:doubleMetaphoneLen]
+	}
+	return primary, alternate
+}
+
+// at returns the character at position i or 0 if i is out of range
+func (e *dmEncoder) at(i int) rune {
+	if i < 0 || i >= len(e.w) {
+		return 0
+	}
+	return e.w[i]
+}
+
+// isVowel returns true if the character at position i is a vowel
+func (e *dmEncoder) isVowel(i int) bool {
+	if i < 0 || i >= e.length {
+		return false
+	}
+	return strings.ContainsRune("AEIOUY", e.w[i])
+}
+
+// stringAt returns true if the n characters starting at position start
+// match any of the options
+func (e *dmEncoder) stringAt(start, n int, options ...string) bool {
+	if start < 0 || start >= len(e.w) {
+		return false
+	}
+	end := start + n
+	if end > len(e.w) {
+		end = len(e.w)
+	}
+	sub := string(e.w[start:end])
+	for _, o := range options {
+		if sub == o {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds the code to both the primary and alternate codes
+func (e *dmEncoder) add(code string) {
+	e.add2(code, code)
+}
+
+// add2 adds separate codes to the primary and alternate codes
+func (e *dmEncoder) add2(primary, alternate string) {
+	e.primary.WriteString(primary)
+	e.alter.WriteString(alternate)
+}
+
+// isGermanic returns true if the word has a Germanic or Dutch prefix
+func (e *dmEncoder) isGermanic() bool {
+	return e.stringAt(0, 4, "VAN ", "VON ") || e.stringAt(0, 3, "SCH")
+}
+
+// skipIfNext returns the number of characters to advance by: 2 if the next
+// character is c and 1 otherwise
+func (e *dmEncoder) skipIfNext(cur int, c rune) int {
+	if e.at(cur+1) == c {
+		return 2
+	}
+	return 1
+}
+
+// encode generates the primary and alternate codes
+func (e *dmEncoder) encode() {
+	cur := 0
+	if e.stringAt(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		cur++
+	}
+	if e.at(0) == 'X' {
+		e.add("S")
+		cur++
+	}
+
+	for cur < e.length &&
+		(e.primary.Len() < doubleMetaphoneLen ||
+			e.alter.Len() < doubleMetaphoneLen) {
+		switch e.at(cur) {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if cur == 0 {
+				e.add("A")
+			}
+			cur++
+		case 'B':
+			e.add("P")
+			cur += e.skipIfNext(cur, 'B')
+		case 'C':
+			cur = e.encodeC(cur)
+		case 'D':
+			cur = e.encodeD(cur)
+		case 'F':
+			e.add("F")
+			cur += e.skipIfNext(cur, 'F')
+		case 'G':
+			cur = e.encodeG(cur)
+		case 'H':
+			if (cur == 0 || e.isVowel(cur-1)) && e.isVowel(cur+1) {
+				e.add("H")
+				cur += 2
+			} else {
+				cur++
+			}
+		case 'J':
+			cur = e.encodeJ(cur)
+		case 'K':
+			e.add("K")
+			cur += e.skipIfNext(cur, 'K')
+		case 'L':
+			cur = e.encodeL(cur)
+		case 'M':
+			if (e.stringAt(cur-1, 3, "UMB") &&
+				(cur+1 == e.last || e.stringAt(cur+2, 2, "ER"))) ||
+				e.at(cur+1) == 'M' {
+				cur += 2
+			} else {
+				cur++
+			}
+			e.add("M")
+		case 'N':
+			e.add("N")
+			cur += e.skipIfNext(cur, 'N')
+		case 'P':
+			if e.at(cur+1) == 'H' {
+				e.add("F")
+				cur += 2
+				break
+			}
+			if e.stringAt(cur+1, 1, "P", "B") {
+				cur += 2
+			} else {
+				cur++
+			}
+			e.add("P")
+		case 'Q':
+			e.add("K")
+			cur += e.skipIfNext(cur, 'Q')
+		case 'R':
+			if cur == e.last && !e.slavoGermanic &&
+				e.stringAt(cur-2, 2, "IE") &&
+				!e.stringAt(cur-4, 2, "ME", "MA") {
+				e.add2("", "R")
+			} else {
+				e.add("R")
+			}
+			cur += e.skipIfNext(cur, 'R')
+		case 'S':
+			cur = e.encodeS(cur)
+		case 'T':
+			cur = e.encodeT(cur)
+		case 'V':
+			e.add("F")
+			cur += e.skipIfNext(cur, 'V')
+		case 'W':
+			cur = e.encodeW(cur)
+		case 'X':
+			if !(cur == e.last &&
+				(e.stringAt(cur-3, 3, "IAU", "EAU") ||
+					e.stringAt(cur-2, 2, "AU", "OU"))) {
+				e.add("KS")
+			}
+			if e.stringAt(cur+1, 1, "C", "X") {
+				cur += 2
+			} else {
+				cur++
+			}
+		case 'Z':
+			cur = e.encodeZ(cur)
+		default:
+			cur++
+		}
+	}
+}
+
+// encodeC encodes a 'C' at position cur and returns the next position
+func (e *dmEncoder) encodeC(cur int) int {
+	// various Germanic
+	if cur > 1 && !e.isVowel(cur-2) &&
+		e.stringAt(cur-1, 3, "ACH") &&
+		e.at(cur+2) != 'I' &&
+		(e.at(cur+2) != 'E' || e.stringAt(cur-2, 6, "BACHER", "MACHER")) {
+		e.add("K")
+		return cur + 2
+	}
+	if cur == 0 && e.stringAt(cur, 6, "CAESAR") {
+		e.add("S")
+		return cur + 2
+	}
+	// Italian 'chianti'
+	if e.stringAt(cur, 4, "CHIA") {
+		e.add("K")
+		return cur + 2
+	}
+	if e.stringAt(cur, 2, "CH") {
+		// 'michael'
+		if cur > 0 && e.stringAt(cur, 4, "CHAE") {
+			e.add2("K", "X")
+			return cur + 2
+		}
+		// Greek roots such as 'chemistry', 'chorus'
+		if cur == 0 &&
+			(e.stringAt(cur+1, 5, "HARAC", "HARIS") ||
+				e.stringAt(cur+1, 3, "HOR", "HYM", "HIA", "HEM")) &&
+			!e.stringAt(0, 5, "CHORE") {
+			e.add("K")
+			return cur + 2
+		}
+		// Germanic, Greek, or otherwise 'ch' for 'kh' sound
+		if e.isGermanic() ||
+			e.stringAt(cur-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+			e.stringAt(cur+2, 1, "T", "S") ||
+			((e.stringAt(cur-1, 1, "A", "O", "U", "E") || cur == 0) &&
+				e.stringAt(cur+2, 1,
+					"L", "R", "N", "M", "B", "H", "F", "V", "W", " ")) {
+			e.add("K")
+		} else if cur > 0 {
+			if e.stringAt(0, 2, "MC") {
+				e.add("K")
+			} else {
+				e.add2("X", "K")
+			}
+		} else {
+			e.add("X")
+		}
+		return cur + 2
+	}
+	// 'czerny'
+	if e.stringAt(cur, 2, "CZ") && !e.stringAt(cur-2, 4, "WICZ") {
+		e.add2("S", "X")
+		return cur + 2
+	}
+	// 'focaccia'
+	if e.stringAt(cur+1, 3, "CIA") {
+		e.add("X")
+		return cur + 3
+	}
+	// double 'C', but not as in 'McClellan'
+	if e.stringAt(cur, 2, "CC") && !(cur == 1 && e.at(0) == 'M') {
+		// 'bellocchio' but not 'bacchus'
+		if e.stringAt(cur+2, 1, "I", "E", "H") &&
+			!e.stringAt(cur+2, 2, "HU") {
+			// 'accident', 'accede', 'succeed'
+			if (cur == 1 && e.at(cur-1) == 'A') ||
+				e.stringAt(cur-1, 5, "UCCEE", "UCCES") {
+				e.add("KS")
+			} else {
+				// 'bacci', 'bertucci'
+				e.add("X")
+			}
+			return cur + 3
+		}
+		// Pierce's rule
+		e.add("K")
+		return cur + 2
+	}
+	if e.stringAt(cur, 2, "CK", "CG", "CQ") {
+		e.add("K")
+		return cur + 2
+	}
+	if e.stringAt(cur, 2, "CI", "CE", "CY") {
+		// Italian or English
+		if e.stringAt(cur, 3, "CIO", "CIE", "CIA") {
+			e.add2("S", "X")
+		} else {
+			e.add("S")
+		}
+		return cur + 2
+	}
+
+	e.add("K")
+	// 'mac caffrey', 'mac gregor'
+	if e.stringAt(cur+1, 2, " C", " Q", " G") {
+		return cur + 3
+	}
+	if e.stringAt(cur+1, 1, "C", "K", "Q") &&
+		!e.stringAt(cur+1, 2, "CE", "CI") {
+		return cur + 2
+	}
+	return cur + 1
+}
+
+// encodeD encodes a 'D' at position cur and returns the next position
+func (e *dmEncoder) encodeD(cur int) int {
+	if e.stringAt(cur, 2, "DG") {
+		if e.stringAt(cur+2, 1, "I", "E", "Y") {
+			// 'edge'
+			e.add("J")
+			return cur + 3
+		}
+		// 'edgar'
+		e.add("TK")
+		return cur + 2
+	}
+	e.add("T")
+	if e.stringAt(cur, 2, "DT", "DD") {
+		return cur + 2
+	}
+	return cur + 1
+}
+
+// encodeG encodes a 'G' at position cur and returns the next position
+func (e *dmEncoder) encodeG(cur int) int {
+	if e.at(cur+1) == 'H' {
+		if cur > 0 && !e.isVowel(cur-1) {
+			e.add("K")
+			return cur + 2
+		}
+		// 'ghislane', 'ghiradelli'
+		if cur == 0 {
+			if e.at(cur+2) == 'I' {
+				e.add("J")
+			} else {
+				e.add("K")
+			}
+			return cur + 2
+		}
+		// Parker's rule (with some further refinements) - 'hugh', 'bough',
+		// 'broughton'
+		if (cur > 1 && e.stringAt(cur-2, 1, "B", "H", "D")) ||
+			(cur > 2 && e.stringAt(cur-3, 1, "B", "H", "D")) ||
+			(cur > 3 && e.stringAt(cur-4, 1, "B", "H")) {
+			return cur + 2
+		}
+		// 'laugh', 'McLaughlin', 'cough', 'gough', 'rough', 'tough'
+		if cur > 2 && e.at(cur-1) == 'U' &&
+			e.stringAt(cur-3, 1, "C", "G", "L", "R", "T") {
+			e.add("F")
+		} else if cur > 0 && e.at(cur-1) != 'I' {
+			e.add("K")
+		}
+		return cur + 2
+	}
+
+	if e.at(cur+1) == 'N' {
+		if cur == 1 && e.isVowel(0) && !e.slavoGermanic {
+			e.add2("KN", "N")
+		} else if !e.stringAt(cur+2, 2, "EY") &&
+			e.at(cur+1) != 'Y' && !e.slavoGermanic {
+			// not 'cagney'
+			e.add2("N", "KN")
+		} else {
+			e.add("KN")
+		}
+		return cur + 2
+	}
+	// 'tagliaro'
+	if e.stringAt(cur+1, 2, "LI") && !e.slavoGermanic {
+		e.add2("KL", "L")
+		return cur + 2
+	}
+	// -ges-, -gep-, -gel-, -gie- at the beginning
+	if cur == 0 &&
+		(e.at(cur+1) == 'Y' ||
+			e.stringAt(cur+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL",
+				"IN", "IE", "EI", "ER")) {
+		e.add2("K", "J")
+		return cur + 2
+	}
+	// -ger-, -gy-
+	if (e.stringAt(cur+1, 2, "ER") || e.at(cur+1) == 'Y') &&
+		!e.stringAt(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!e.stringAt(cur-1, 1, "E", "I") &&
+		!e.stringAt(cur-1, 3, "RGY", "OGY") {
+		e.add2("K", "J")
+		return cur + 2
+	}
+	// Italian such as 'biaggi'
+	if e.stringAt(cur+1, 1, "E", "I", "Y") ||
+		e.stringAt(cur-1, 4, "AGGI", "OGGI") {
+		if e.isGermanic() || e.stringAt(cur+1, 2, "ET") {
+			e.add("K")
+		} else if e.stringAt(cur+1, 4, "IER ") {
+			// always soft if French ending
+			e.add("J")
+		} else {
+			e.add2("J", "K")
+		}
+		return cur + 2
+	}
+
+	e.add("K")
+	return cur + e.skipIfNext(cur, 'G')
+}
+
+// encodeJ encodes a 'J' at position cur and returns the next position
+func (e *dmEncoder) encodeJ(cur int) int {
+	// obviously Spanish, 'jose', 'san jacinto'
+	if e.stringAt(cur, 4, "JOSE") || e.stringAt(0, 4, "SAN ") {
+		if (cur == 0 && e.at(cur+4) == ' ') || e.stringAt(0, 4, "SAN ") {
+			e.add("H")
+		} else {
+			e.add2("J", "H")
+		}
+		return cur + 1
+	}
+
+	switch {
+	case cur == 0:
+		// 'Yankelovich' or 'Jankelowicz'
+		e.add2("J", "A")
+	case e.isVowel(cur-1) && !e.slavoGermanic &&
+		(e.at(cur+1) == 'A' || e.at(cur+1) == 'O'):
+		// Spanish pronunciation of, for instance, 'bajador'
+		e.add2("J", "H")
+	case cur == e.last:
+		e.add2("J", "")
+	case !e.stringAt(cur+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") &&
+		!e.stringAt(cur-1, 1, "S", "K", "L"):
+		e.add("J")
+	}
+	return cur + e.skipIfNext(cur, 'J')
+}
+
+// encodeL encodes an 'L' at position cur and returns the next position
+func (e *dmEncoder) encodeL(cur int) int {
+	if e.at(cur+1) == 'L' {
+		// Spanish such as 'cabrillo', 'gallegos'
+		if (cur == e.length-3 &&
+			e.stringAt(cur-1, 4, "ILLO", "ILLA", "ALLE")) ||
+			((e.stringAt(e.last-1, 2, "AS", "OS") ||
+				e.stringAt(e.last, 1, "A", "O")) &&
+				e.stringAt(cur-1, 4, "ALLE")) {
+			e.add2("L", "")
+			return cur + 2
+		}
+		e.add("L")
+		return cur + 2
+	}
+	e.add("L")
+	return cur + 1
+}
+
+// encodeS encodes an 'S' at position cur and returns the next position
+func (e *dmEncoder) encodeS(cur int) int {
+	// 'island', 'isle', 'carlisle', 'carlysle'
+	if e.stringAt(cur-1, 3, "ISL", "YSL") {
+		return cur + 1
+	}
+	// 'sugar-'
+	if cur == 0 && e.stringAt(cur, 5, "SUGAR") {
+		e.add2("X", "S")
+		return cur + 1
+	}
+	if e.stringAt(cur, 2, "SH") {
+		// Germanic
+		if e.stringAt(cur+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			e.add("S")
+		} else {
+			e.add("X")
+		}
+		return cur + 2
+	}
+	// Italian and Armenian
+	if e.stringAt(cur, 3, "SIO", "SIA") || e.stringAt(cur, 4, "SIAN") {
+		if e.slavoGermanic {
+			e.add("S")
+		} else {
+			e.add2("S", "X")
+		}
+		return cur + 3
+	}
+	// German and anglicisations, 'smith' matching 'schmidt', 'snider'
+	// matching 'schneider'; also -sz- in Slavic languages although in
+	// Hungarian it is pronounced 's'
+	if (cur == 0 && e.stringAt(cur+1, 1, "M", "N", "L", "W")) ||
+		e.stringAt(cur+1, 1, "Z") {
+		e.add2("S", "X")
+		return cur + e.skipIfNext(cur, 'Z')
+	}
+	if e.stringAt(cur, 2, "SC") {
+		// Schlesinger's rule
+		if e.at(cur+2) == 'H' {
+			// Dutch origin such as 'school', 'schooner'
+			if e.stringAt(cur+3, 2, "OO", "ER", "EN", "UY", "ED", "EM") {
+				// 'schermerhorn', 'schenker'
+				if e.stringAt(cur+3, 2, "ER", "EN") {
+					e.add2("X", "SK")
+				} else {
+					e.add("SK")
+				}
+				return cur + 3
+			}
+			if cur == 0 && !e.isVowel(3) && e.at(3) != 'W' {
+				e.add2("X", "S")
+			} else {
+				e.add("X")
+			}
+			return cur + 3
+		}
+		if e.stringAt(cur+2, 1, "I", "E", "Y") {
+			e.add("S")
+			return cur + 3
+		}
+		e.add("SK")
+		return cur + 3
+	}
+
+	// French such as 'resnais', 'artois'
+	if cur == e.last && e.stringAt(cur-2, 2, "AI", "OI") {
+		e.add2("", "S")
+	} else {
+		e.add("S")
+	}
+	if e.stringAt(cur+1, 1, "S", "Z") {
+		return cur + 2
+	}
+	return cur + 1
+}
+
+// encodeT encodes a 'T' at position cur and returns the next position
+func (e *dmEncoder) encodeT(cur int) int {
+	if e.stringAt(cur, 4, "TION") {
+		e.add("X")
+		return cur + 3
+	}
+	if e.stringAt(cur, 3, "TIA", "TCH") {
+		e.add("X")
+		return cur + 3
+	}
+	if e.stringAt(cur, 2, "TH") || e.stringAt(cur, 3, "TTH") {
+		// 'thomas', 'thames' or Germanic
+		if e.stringAt(cur+2, 2, "OM", "AM") || e.isGermanic() {
+			e.add("T")
+		} else {
+			e.add2("0", "T")
+		}
+		return cur + 2
+	}
+	e.add("T")
+	if e.stringAt(cur+1, 1, "T", "D") {
+		return cur + 2
+	}
+	return cur + 1
+}
+
+// encodeW encodes a 'W' at position cur and returns the next position
+func (e *dmEncoder) encodeW(cur int) int {
+	if e.stringAt(cur, 2, "WR") {
+		e.add("R")
+		return cur + 2
+	}
+	if cur == 0 && (e.isVowel(cur+1) || e.stringAt(cur, 2, "WH")) {
+		if e.isVowel(cur + 1) {
+			// 'Wasserman' should match 'Vasserman'
+			e.add2("A", "F")
+		} else {
+			// 'Uomo' should match 'Womo'
+			e.add("A")
+		}
+	}
+	// 'Arnow' should match 'Arnoff'
+	if (cur == e.last && e.isVowel(cur-1)) ||
+		e.stringAt(cur-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		e.stringAt(0, 3, "SCH") {
+		e.add2("", "F")
+		return cur + 1
+	}
+	// Polish such as 'filipowicz'
+	if e.stringAt(cur, 4, "WICZ", "WITZ") {
+		e.add2("TS", "FX")
+		return cur + 4
+	}
+	return cur + 1
+}
+
+// encodeZ encodes a 'Z' at position cur and returns the next position
+func (e *dmEncoder) encodeZ(cur int) int {
+	// Chinese pinyin such as 'zhao'
+	if e.at(cur+1) == 'H' {
+		e.add("J")
+		return cur + 2
+	}
+	if e.stringAt(cur+1, 2, "ZO", "ZI", "ZA") ||
+		(e.slavoGermanic && cur > 0 && e.at(cur-1) != 'T') {
+		e.add2("S", "TS")
+	} else {
+		e.add("S")
+	}
+	return cur + e.skipIfNext(cur, 'Z')
+}
@@ -2,7 +2,11 @@ package strdist
 
 import (
 	"fmt"
+	"runtime"
 	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 // CaseMod represents the different behaviours with regards to case
@@ -15,13 +19,76 @@ const (
 	// ForceToLower indicates that the case should be forced to lower case
 	// when calculating distances
 	ForceToLower
+	// UnicodeFold indicates that full Unicode case folding should be
+	// applied when calculating distances. This differs from ForceToLower in
+	// that, for instance, the German sharp s ("ß") will match "ss" and the
+	// final Greek sigma ("ς") will match the normal sigma ("σ")
+	UnicodeFold
+	// AccentBlind indicates that strings should be case folded (as for
+	// UnicodeFold) and any accents removed when calculating distances so
+	// that, for instance, "café" will match "CAFE". Accents are removed by
+	// discarding any non-spacing marks (such as combining accents) and by
+	// replacing accented Latin, Greek and Cyrillic letters with the letter
+	// without its accents
+	AccentBlind
 )
 
-// DistAlgo describes the algorithm which the Finder will use to
-// distance. There is a Prep func provided which will allow some common tasks
-// to be performed before the distance is calculated - some algorithms can
-// cache some intermediate results to save time when calculating the
-// string-to-string distance.
+// Apply returns the string with the CaseMod applied
+func (cm CaseMod) Apply(s string) string {
+	switch cm {
+	case ForceToLower:
+		return strings.ToLower(s)
+	case UnicodeFold:
+		return foldString(s)
+	case AccentBlind:
+		return foldString(removeAccents(s))
+	}
+	return s
+}
+
+// String returns a string form of the CaseMod
+func (cm CaseMod) String() string {
+	switch cm {
+	case NoCaseChange:
+		return "NoCaseChange"
+	case ForceToLower:
+		return "ForceToLower"
+	case UnicodeFold:
+		return "UnicodeFold"
+	case AccentBlind:
+		return "AccentBlind"
+	}
+	return fmt.Sprintf("CaseMod(%d)", int(cm))
+}
+
+// Algo describes the algorithm which the Finder will use to calculate
+// distances. The PrepQuery func prepares the target string, performing any
+// common tasks (such as applying the CaseMod or generating the n-grams) once
+// rather than for every string it is compared with, and returns a Query
+// from which the distances are calculated.
+//
+// PrepQuery must not change the Algo and a Query must not change once it
+// has been returned. This allows an Algo, and the Finders using it, to be
+// used from several goroutines at once.
+type Algo interface {
+	PrepQuery(s string, cm CaseMod) Query
+}
+
+// Query holds a target string prepared by an Algo. The Dist func returns
+// the distance from the target string to the string (s) after the CaseMod
+// given to PrepQuery has been applied to s.
+type Query interface {
+	Dist(s string) float64
+}
+
+// DistAlgo describes the original form of the algorithm used to calculate
+// distances. The Prep func caches intermediate results for the target
+// string in the DistAlgo itself which are then used by the Dist func. This
+// means that a DistAlgo cannot, in general, be used from more than one
+// goroutine at a time. The algorithms in this package satisfy both the
+// DistAlgo and the Algo interfaces. NewFinder will convert a DistAlgo
+// which is not also an Algo with AdaptDistAlgo; use AdaptDistAlgoFunc if
+// the distances should be calculated in parallel.
 type DistAlgo interface {
 	Prep(s string, cm CaseMod)
 	Dist(s1, s2 string, cm CaseMod) float64
@@ -35,24 +102,39 @@ type DistAlgo interface {
 // particularly helpful.
 const DfltMinStrLen = 4
 
-// Finder records the parameters of the finding algorithm
+// Finder records the parameters of the finding algorithm. A Finder may be
+// used from several goroutines at once provided that its fields are not
+// changed while it is in use.
 type Finder struct {
-	// MinStrLen records the minimum length of string to be matched
+	// MinStrLen records the minimum length of string to be matched. The
+	// length is measured in runes rather than bytes
 	MinStrLen int
 	// T is the threshold for similarity for this finder
 	T float64
-	// CH, if set to ForceToLower, will convert all strings to lower case
-	// before generating the distance
+	// CM gives any changes to be made to the strings (such as converting
+	// them to lower case) before generating the distance
 	CM CaseMod
 	// Algo is the algorithm with which to calculate the distance between two
 	// strings
-	Algo DistAlgo
+	Algo Algo
 }
 
 // NewFinder checks that the parameters are valid and creates a new
 // Finder if they are. The minStrLen and threshold must each be >=
-// 0. A zero threshold wil require an exact match.
+// 0. A zero threshold wil require an exact match. If the DistAlgo is not
+// also an Algo it is converted to one with AdaptDistAlgo.
 func NewFinder(minStrLen int, threshold float64, cm CaseMod, a DistAlgo) (*Finder, error) {
+	algo, ok := a.(Algo)
+	if !ok {
+		algo = AdaptDistAlgo(a)
+	}
+	return NewAlgoFinder(minStrLen, threshold, cm, algo)
+}
+
+// NewAlgoFinder checks that the parameters are valid and creates a new
+// Finder using the Algo if they are. The parameters are checked as for
+// NewFinder.
+func NewAlgoFinder(minStrLen int, threshold float64, cm CaseMod, a Algo) (*Finder, error) {
 	if minStrLen < 0 {
 		return nil,
 			fmt.Errorf("bad minimum string length (%d) - it should be >= 0",
@@ -77,20 +159,78 @@ func NewFinder(minStrLen int, threshold float64, cm CaseMod, a DistAlgo) (*Finde
 // NGram finder's threshold value
 func (f *Finder) FindLike(s string, pop ...string) []StrDist {
 	lp := len(pop)
-	if lp == 0 || len(s) < f.MinStrLen {
+	if lp == 0 || utf8.RuneCountInString(s) < f.MinStrLen {
 		return []StrDist{}
 	}
 
-	dists := make([]StrDist, 0, lp)
+	dists := f.calcDists(f.Algo.PrepQuery(s, f.CM), pop)
 
-	f.Algo.Prep(s, f.CM)
+	sort.Slice(dists, func(i, j int) bool { return SDSlice(dists).Cmp(i, j) })
+	return dists
+}
 
+// FindLikeParallel returns the same StrDists as the FindLike func but the
+// distances are calculated by a number of goroutines (workers), each taking
+// a share of the population. If workers is <= 0 then GOMAXPROCS goroutines
+// are used. This will only be faster than FindLike for a large population
+// or an expensive algorithm. Note that all the goroutines use the same
+// Query and so if the Query serialises the calculations, as those from an
+// Algo made by AdaptDistAlgo do, this will be no faster than FindLike.
+func (f *Finder) FindLikeParallel(s string, workers int, pop ...string) []StrDist {
+	lp := len(pop)
+	if lp == 0 || utf8.RuneCountInString(s) < f.MinStrLen {
+		return []StrDist{}
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > lp {
+		workers = lp
+	}
+
+	q := f.Algo.PrepQuery(s, f.CM)
+	shareSize := (lp + workers - 1) / workers
+	results := make([][]StrDist, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * shareSize
+		if start >= lp {
+			break
+		}
+		end := start + shareSize
+		if end > lp {
+			end = lp
+		}
+
+		wg.Add(1)
+		go func(w int, share []string) {
+			defer wg.Done()
+			results[w] = f.calcDists(q, share)
+		}(w, pop[start:end])
+	}
+	wg.Wait()
+
+	dists := make([]StrDist, 0)
+	for _, r := range results {
+		dists = append(dists, r...)
+	}
+
+	sort.Slice(dists, func(i, j int) bool { return SDSlice(dists).Cmp(i, j) })
+	return dists
+}
+
+// calcDists returns the StrDists for those strings in pop which are not
+// shorter than the Finder's MinStrLen and whose distance from the Query's
+// target is within the Finder's threshold.
+func (f *Finder) calcDists(q Query, pop []string) []StrDist {
+	dists := make([]StrDist, 0)
 	for _, p := range pop {
-		if len(p) < f.MinStrLen {
+		if utf8.RuneCountInString(p) < f.MinStrLen {
 			continue
 		}
 
-		d := f.Algo.Dist(s, p, f.CM)
+		d := q.Dist(p)
 		if d > f.T {
 			continue
 		}
@@ -100,8 +240,6 @@ func (f *Finder) FindLike(s string, pop ...string) []StrDist {
 			Dist: d,
 		})
 	}
-
-	sort.Slice(dists, func(i, j int) bool { return SDSlice(dists).Cmp(i, j) })
 	return dists
 }
 
@@ -1,7 +1,6 @@
 package strdist
 
 import (
-	"strings"
 	"unicode/utf8"
 )
 
@@ -34,9 +33,7 @@ func init() {
 const DfltHammingThreshold = 5.0
 
 // HammingAlgo encapsulates the details needed to provide the Hamming distance.
-type HammingAlgo struct {
-	s string
-}
+type HammingAlgo struct{}
 
 // NewHammingFinder returns a new Finder having a Hamming algo and an
 // error which will be non-nil if the parameters are invalid - see
@@ -46,24 +43,10 @@ func NewHammingFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder, er
 		&HammingAlgo{})
 }
 
-// Prep for a HammingAlgo will pre-calculate the lower-case equivalent for
-// the target string if the caseMod is set to ForceToLower
-func (a *HammingAlgo) Prep(s string, cm CaseMod) {
-	if cm == ForceToLower {
-		a.s = strings.ToLower(s)
-		return
-	}
-	a.s = s
-}
-
-// Dist for a HammingAlgo will calculate the Hamming distance between the two
-// strings
-func (a *HammingAlgo) Dist(_, s string, cm CaseMod) float64 {
-	if cm == ForceToLower {
-		return HammingDistance(a.s, strings.ToLower(s))
-	}
-
-	return HammingDistance(a.s, s)
+// PrepQuery for a HammingAlgo will apply the CaseMod to the target string and
+// return a Query giving the Hamming distance from it
+func (a *HammingAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, HammingDistance)
 }
 
 // HammingDistance returns the Hamming distance of the two strings. if the
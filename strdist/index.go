@@ -0,0 +1,273 @@
+package strdist
+
+import (
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// Index holds a population of strings, prepared so that strings similar to
+// a target can be found without calculating the distance to every member of
+// the population. It is built once and can then be queried many times; it
+// gives the same results, in the same order, as the Finder from which it
+// was built.
+//
+// How the population is indexed depends on the Finder's algorithm. For the
+// n-gram based algorithms (Jaccard, WeightedJaccard and Cosine) an inverted
+// index from each n-gram to the strings containing it is used and only those
+// strings having an n-gram in common with the target are considered. For
+// the Levenshtein, Damerau-Levenshtein and LCS algorithms, whose distances
+// are metrics, a BK-tree is used; this is most effective when the threshold
+// is small compared with the lengths of the strings. For any other algorithm
+// the whole population is searched, as for the Finder.
+//
+// Like the Finder, an Index may be used from several goroutines at once.
+type Index struct {
+	f       *Finder
+	entries []string
+
+	ngIdx *ngramIndex
+	bkt   *bkTree
+}
+
+// NewIndex returns a new Index built from the Finder and the population of
+// strings. Strings shorter than the Finder's MinStrLen are not indexed.
+//
+// The Finder's Algo is only indexed if it is one of the algorithms listed
+// in the description of the Index. Any other Algo, including one which
+// wraps an indexable algorithm such as a ScaledAlgo or an AdaptDistAlgo, is
+// silently handled by searching the whole population each time; the
+// results are the same but there is no gain in speed.
+func NewIndex(f *Finder, pop ...string) *Index {
+	idx := &Index{
+		f:       f,
+		entries: make([]string, 0, len(pop)),
+	}
+	for _, p := range pop {
+		if utf8.RuneCountInString(p) < f.MinStrLen {
+			continue
+		}
+		idx.entries = append(idx.entries, p)
+	}
+
+	switch a := f.Algo.(type) {
+	case *JaccardAlgo:
+		idx.ngIdx = newNGramIndex(a.N, f.CM, idx.entries)
+	case *WeightedJaccardAlgo:
+		idx.ngIdx = newNGramIndex(a.N, f.CM, idx.entries)
+	case *CosineAlgo:
+		idx.ngIdx = newNGramIndex(a.N, f.CM, idx.entries)
+	case *LevenshteinAlgo:
+		idx.bkt = newBKTree(LevenshteinDistance, f.CM, idx.entries)
+	case *DamerauLevenshteinAlgo:
+		idx.bkt = newBKTree(DamerauLevenshteinDistance, f.CM, idx.entries)
+	case *LCSAlgo:
+		idx.bkt = newBKTree(LCSDistance, f.CM, idx.entries)
+	}
+
+	return idx
+}
+
+// Len returns the number of strings in the index. This will not include any
+// strings from the population which were too short to be indexed
+func (idx *Index) Len() int {
+	return len(idx.entries)
+}
+
+// FindLike returns StrDists for those strings in the index which are similar
+// to the string (s). Similarity is as for the Finder's FindLike method.
+func (idx *Index) FindLike(s string) []StrDist {
+	f := idx.f
+	if len(idx.entries) == 0 || utf8.RuneCountInString(s) < f.MinStrLen {
+		return []StrDist{}
+	}
+
+	var dists []StrDist
+	switch {
+	case idx.bkt != nil:
+		key := f.CM.Apply(s)
+		dists = idx.bkt.find(key, idx.bkt.threshold(key, f.T))
+	case idx.ngIdx != nil && f.T < 1.0:
+		dists = f.calcDists(f.Algo.PrepQuery(s, f.CM), idx.ngIdx.candidates(s))
+	default:
+		dists = f.calcDists(f.Algo.PrepQuery(s, f.CM), idx.entries)
+	}
+
+	sort.Slice(dists, func(i, j int) bool { return SDSlice(dists).Cmp(i, j) })
+	return dists
+}
+
+// FindStrLike returns those strings in the index which are similar to the
+// string (s). Similarity is as for the Finder's FindLike method.
+func (idx *Index) FindStrLike(s string) []string {
+	return convertStrDist(idx.FindLike(s))
+}
+
+// FindNStrLike returns the first n strings in the index which are similar to
+// the string (s). Similarity is as for the Finder's FindLike method.
+func (idx *Index) FindNStrLike(n int, s string) []string {
+	return convertStrDistN(n, idx.FindLike(s))
+}
+
+// ngramIndex maps each n-gram to the indexes of the entries containing it
+type ngramIndex struct {
+	n        int
+	cm       CaseMod
+	entries  []string
+	postings map[string][]int
+	// noNGrams holds the indexes of those entries which are too short to
+	// have any n-grams
+	noNGrams []int
+}
+
+// newNGramIndex returns an ngramIndex for the entries
+func newNGramIndex(n int, cm CaseMod, entries []string) *ngramIndex {
+	ngi := &ngramIndex{
+		n:        n,
+		cm:       cm,
+		entries:  entries,
+		postings: make(map[string][]int),
+	}
+	for i, e := range entries {
+		ngs, _ := NGrams(cm.Apply(e), n)
+		if len(ngs) == 0 {
+			ngi.noNGrams = append(ngi.noNGrams, i)
+			continue
+		}
+		for ng := range ngs {
+			ngi.postings[ng] = append(ngi.postings[ng], i)
+		}
+	}
+	return ngi
+}
+
+// candidates returns those entries which have at least one n-gram in common
+// with the string (s) together with any entries which have no n-grams. Any
+// other entry will be at the maximum distance (1.0) from s for all the
+// n-gram based algorithms.
+func (ngi *ngramIndex) candidates(s string) []string {
+	ngs, _ := NGrams(ngi.cm.Apply(s), ngi.n)
+	seen := make(map[int]bool)
+	cands := make([]string, 0)
+	for ng := range ngs {
+		for _, i := range ngi.postings[ng] {
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			cands = append(cands, ngi.entries[i])
+		}
+	}
+	for _, i := range ngi.noNGrams {
+		cands = append(cands, ngi.entries[i])
+	}
+	return cands
+}
+
+// bkNode is a node in a BK-tree. The key is the entry after any CaseMod has
+// been applied and strs holds all the entries having that key. The children
+// are indexed by their distance from the node
+type bkNode struct {
+	key      string
+	strs     []string
+	children []*bkNode
+}
+
+// bkTree is a Burkhard-Keller tree. Each child of a node is at a distinct
+// distance from the node and all the entries below that child are at that
+// same distance from the node. Then, because the distance is a metric, only
+// those children whose distance from the node is within the threshold of
+// the distance between the node and the target need to be searched.
+type bkTree struct {
+	dist   func(a, b string) int
+	root   *bkNode
+	maxLen int // the length in runes of the longest key
+}
+
+// newBKTree returns a bkTree holding the entries
+func newBKTree(dist func(a, b string) int, cm CaseMod, entries []string) *bkTree {
+	t := &bkTree{dist: dist}
+	for _, e := range entries {
+		t.add(cm.Apply(e), e)
+	}
+	return t
+}
+
+// add adds the string (s) to the tree with the given key
+func (t *bkTree) add(key, s string) {
+	if l := utf8.RuneCountInString(key); l > t.maxLen {
+		t.maxLen = l
+	}
+	if t.root == nil {
+		t.root = &bkNode{key: key, strs: []string{s}}
+		return
+	}
+
+	n := t.root
+	for {
+		d := t.dist(key, n.key)
+		if d == 0 {
+			n.strs = append(n.strs, s)
+			return
+		}
+		if d >= len(n.children) {
+			n.children = append(n.children,
+				make([]*bkNode, d-len(n.children)+1)...)
+		}
+		if n.children[d] == nil {
+			n.children[d] = &bkNode{key: key, strs: []string{s}}
+			return
+		}
+		n = n.children[d]
+	}
+}
+
+// threshold converts the Finder's threshold into the integer threshold to
+// be used when finding strings near the key. Since the distances are
+// integers the threshold can be rounded down. No distance can be greater
+// than the sum of the lengths of the key and the longest key in the tree
+// and so the threshold is clamped to this value; this also means that an
+// infinite or very large threshold is converted correctly. A threshold
+// which is negative or not a number gives -1 so that nothing is found.
+func (t *bkTree) threshold(key string, ft float64) int {
+	if !(ft >= 0) {
+		return -1
+	}
+	maxDist := utf8.RuneCountInString(key) + t.maxLen
+	if ft >= float64(maxDist) {
+		return maxDist
+	}
+	return int(math.Floor(ft))
+}
+
+// find returns StrDists for all the strings in the tree whose keys are no
+// further than the threshold from the key
+func (t *bkTree) find(key string, threshold int) []StrDist {
+	dists := make([]StrDist, 0)
+	if t.root == nil {
+		return dists
+	}
+
+	toVisit := []*bkNode{t.root}
+	for len(toVisit) > 0 {
+		n := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+
+		d := t.dist(key, n.key)
+		if d <= threshold {
+			for _, s := range n.strs {
+				dists = append(dists, StrDist{Str: s, Dist: float64(d)})
+			}
+		}
+		lo := d - threshold
+		if lo < 0 {
+			lo = 0
+		}
+		for cd := lo; cd <= d+threshold && cd < len(n.children); cd++ {
+			if n.children[cd] != nil {
+				toVisit = append(toVisit, n.children[cd])
+			}
+		}
+	}
+	return dists
+}
@@ -0,0 +1,223 @@
+package strdist_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+	"github.com/nickwells/golem/testhelper"
+)
+
+// makePop returns a population of n pseudo-random words of between minLen
+// and maxLen characters made from the letters
+func makePop(n int, seed int64, letters string, minLen, maxLen int) []string {
+	r := rand.New(rand.NewSource(seed))
+	alphabet := []rune(letters)
+
+	pop := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		word := make([]rune, minLen+r.Intn(maxLen-minLen+1))
+		for j := range word {
+			word[j] = alphabet[r.Intn(len(alphabet))]
+		}
+		pop = append(pop, string(word))
+	}
+	return pop
+}
+
+func TestIndex(t *testing.T) {
+	// a small alphabet and short words so that there are plenty of
+	// similar words
+	pop := append(makePop(2000, 1, "abcdeilmnorstuAEIé", 2, 9),
+		"test", "test", "Test", "TEST", "tset", "tést", "te", "", "testing")
+	targets := []string{"test", "Test", "tést", "", "t", "me", "mission",
+		"abcdeilmnor", "estimates"}
+
+	mkFinder := func(f *strdist.Finder, err error) *strdist.Finder {
+		t.Helper()
+		if err != nil {
+			t.Fatal("couldn't create the Finder: ", err)
+		}
+		return f
+	}
+	withT := func(f *strdist.Finder, threshold float64) *strdist.Finder {
+		f.T = threshold
+		return f
+	}
+	testCases := []struct {
+		name   string
+		finder *strdist.Finder
+	}{
+		{
+			name: "levenshtein",
+			finder: mkFinder(
+				strdist.NewLevenshteinFinder(0, 2.0, strdist.NoCaseChange)),
+		},
+		{
+			name: "levenshtein, case-blind, min length 3",
+			finder: mkFinder(
+				strdist.NewLevenshteinFinder(3, 1.0, strdist.ForceToLower)),
+		},
+		{
+			name: "levenshtein, accent-blind, large threshold",
+			finder: mkFinder(
+				strdist.NewLevenshteinFinder(0, 20.0, strdist.AccentBlind)),
+		},
+		{
+			name: "damerau-levenshtein",
+			finder: mkFinder(strdist.NewDamerauLevenshteinFinder(
+				2, 2.5, strdist.NoCaseChange)),
+		},
+		{
+			name: "levenshtein, infinite threshold",
+			finder: mkFinder(strdist.NewLevenshteinFinder(
+				0, math.Inf(1), strdist.NoCaseChange)),
+		},
+		{
+			name: "levenshtein, negative threshold",
+			finder: withT(mkFinder(strdist.NewLevenshteinFinder(
+				0, 0, strdist.NoCaseChange)), -1),
+		},
+		{
+			name:   "lcs",
+			finder: mkFinder(strdist.NewLCSFinder(0, 3, strdist.NoCaseChange)),
+		},
+		{
+			name: "lcs, very large threshold",
+			finder: mkFinder(
+				strdist.NewLCSFinder(0, 1e300, strdist.NoCaseChange)),
+		},
+		{
+			name: "jaccard",
+			finder: mkFinder(
+				strdist.NewJaccardFinder(2, 0, 0.5, strdist.NoCaseChange)),
+		},
+		{
+			name: "jaccard, threshold 1",
+			finder: mkFinder(
+				strdist.NewJaccardFinder(2, 0, 1.0, strdist.NoCaseChange)),
+		},
+		{
+			name: "weighted jaccard, case-blind",
+			finder: mkFinder(strdist.NewWeightedJaccardFinder(
+				3, 0, 0.6, strdist.ForceToLower)),
+		},
+		{
+			name: "cosine",
+			finder: mkFinder(
+				strdist.NewCosineFinder(2, 4, 0.4, strdist.NoCaseChange)),
+		},
+		{
+			name: "jaro-winkler - not indexed",
+			finder: mkFinder(strdist.NewJaroWinklerFinder(
+				0, 0.1, strdist.NoCaseChange)),
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		idx := strdist.NewIndex(tc.finder, pop...)
+		for _, target := range targets {
+			expect := tc.finder.FindStrLike(target, pop...)
+			results := idx.FindStrLike(target)
+			if testhelper.StringSliceDiff(expect, results) {
+				t.Log(tcID)
+				t.Logf("\t: target: %q", target)
+				t.Logf("\t: Finder: %v", expect)
+				t.Logf("\t:  Index: %v", results)
+				t.Errorf("\t: the Index and the Finder results differ\n")
+			}
+
+			expect = tc.finder.FindNStrLike(3, target, pop...)
+			results = idx.FindNStrLike(3, target)
+			if testhelper.StringSliceDiff(expect, results) {
+				t.Log(tcID)
+				t.Logf("\t: target: %q", target)
+				t.Logf("\t: Finder: %v", expect)
+				t.Logf("\t:  Index: %v", results)
+				t.Errorf("\t: the Index and the Finder results differ" +
+					" (max 3 values)\n")
+			}
+		}
+	}
+}
+
+func TestIndexLen(t *testing.T) {
+	f, err := strdist.NewLevenshteinFinder(3, 1.0, strdist.NoCaseChange)
+	if err != nil {
+		t.Fatal("couldn't create the Levenshtein Finder: ", err)
+	}
+	idx := strdist.NewIndex(f, "a", "ab", "abc", "日本語", "abcd")
+	if idx.Len() != 3 {
+		t.Errorf("the Index should hold 3 strings, not %d", idx.Len())
+	}
+
+	idx = strdist.NewIndex(f)
+	if res := idx.FindStrLike("abc"); len(res) != 0 {
+		t.Errorf("an empty Index should find nothing, not %v", res)
+	}
+}
+
+var benchPop = makePop(100000, 42, "abcdefghijklmnopqrstuvwxyzéè-_", 4, 16)
+
+var benchTargets = []string{"mission", "emitters", "ratio", "stone"}
+
+// benchmarkFinder measures the time taken to search the population with the
+// Finder
+func benchmarkFinder(b *testing.B, f *strdist.Finder) {
+	b.Helper()
+	for i := 0; i < b.N; i++ {
+		for _, target := range benchTargets {
+			f.FindLike(target, benchPop...)
+		}
+	}
+}
+
+// benchmarkIndex measures the time taken to search the population with an
+// Index built from the Finder
+func benchmarkIndex(b *testing.B, f *strdist.Finder) {
+	b.Helper()
+	idx := strdist.NewIndex(f, benchPop...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, target := range benchTargets {
+			idx.FindLike(target)
+		}
+	}
+}
+
+func BenchmarkFinderLevenshtein(b *testing.B) {
+	benchmarkFinder(b, strdist.DfltLevenshteinFinder)
+}
+
+func BenchmarkIndexLevenshtein(b *testing.B) {
+	benchmarkIndex(b, strdist.DfltLevenshteinFinder)
+}
+
+func BenchmarkFinderLevenshteinThreshold2(b *testing.B) {
+	f, _ := strdist.NewLevenshteinFinder(4, 2.0, strdist.NoCaseChange)
+	benchmarkFinder(b, f)
+}
+
+func BenchmarkIndexLevenshteinThreshold2(b *testing.B) {
+	f, _ := strdist.NewLevenshteinFinder(4, 2.0, strdist.NoCaseChange)
+	benchmarkIndex(b, f)
+}
+
+func BenchmarkFinderJaccard(b *testing.B) {
+	benchmarkFinder(b, strdist.DfltJaccardFinder)
+}
+
+func BenchmarkIndexJaccard(b *testing.B) {
+	benchmarkIndex(b, strdist.DfltJaccardFinder)
+}
+
+func BenchmarkFinderCosine(b *testing.B) {
+	benchmarkFinder(b, strdist.DfltCosineFinder)
+}
+
+func BenchmarkIndexCosine(b *testing.B) {
+	benchmarkIndex(b, strdist.DfltCosineFinder)
+}
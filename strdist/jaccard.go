@@ -2,7 +2,6 @@ package strdist
 
 import (
 	"fmt"
-	"strings"
 )
 
 const DfltJaccardThreshold = 0.33
@@ -31,7 +30,13 @@ func init() {
 
 // JaccardAlgo encapsulates the details needed to provide the cosine distance.
 type JaccardAlgo struct {
-	N         int
+	N int
+}
+
+// jaccardQuery is the Query returned by a JaccardAlgo
+type jaccardQuery struct {
+	n         int
+	cm        CaseMod
 	ngsTarget NGramSet
 }
 
@@ -50,27 +55,17 @@ func NewJaccardFinder(ngLen, minStrLen int, threshold float64, cm CaseMod) (*Fin
 	return NewFinder(minStrLen, threshold, cm, algo)
 }
 
-// Prep for a JaccardAlgo will pre-calculate the n-gram set for the target
-// string
-func (a *JaccardAlgo) Prep(s string, cm CaseMod) {
-	switch cm {
-	case ForceToLower:
-		a.ngsTarget, _ = NGrams(strings.ToLower(s), a.N)
-	default:
-		a.ngsTarget, _ = NGrams(s, a.N)
-	}
+// PrepQuery for a JaccardAlgo will pre-calculate the n-gram set for the
+// target string
+func (a *JaccardAlgo) PrepQuery(s string, cm CaseMod) Query {
+	ngs, _ := NGrams(cm.Apply(s), a.N)
+	return jaccardQuery{n: a.N, cm: cm, ngsTarget: ngs}
 }
 
-// Dist for a JaccardAlgo will calculate the distance from the target string
-func (a *JaccardAlgo) Dist(_, s string, cm CaseMod) float64 {
-	var ngs NGramSet
-	switch cm {
-	case ForceToLower:
-		ngs, _ = NGrams(strings.ToLower(s), a.N)
-	default:
-		ngs, _ = NGrams(s, a.N)
-	}
-	return 1.0 - JaccardIndex(a.ngsTarget, ngs)
+// Dist for a jaccardQuery will calculate the distance from the target string
+func (q jaccardQuery) Dist(s string) float64 {
+	ngs, _ := NGrams(q.cm.Apply(s), q.n)
+	return 1.0 - JaccardIndex(q.ngsTarget, ngs)
 }
 
 // JaccardIndex returns the Jaccard index of the two n-gram sets
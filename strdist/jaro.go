@@ -0,0 +1,197 @@
+package strdist
+
+// DfltJaroThreshold is a default value for deciding whether a distance
+// between two strings is sufficiently small for them to be considered
+// similar. It is used for both the Jaro and the Jaro-Winkler finders
+const DfltJaroThreshold = 0.2
+
+// JaroWinklerPrefixScale is the amount by which the Jaro similarity is
+// scaled up for each character of common prefix when calculating the
+// Jaro-Winkler similarity.
+//
+// JaroWinklerMaxPrefix is the maximum length of common prefix which is
+// taken into account when calculating the Jaro-Winkler similarity.
+const (
+	JaroWinklerPrefixScale = 0.1
+	JaroWinklerMaxPrefix   = 4
+)
+
+// DfltJaroFinder is a Finder with some default values suitable for a Jaro
+// algorithm already set.
+var DfltJaroFinder *Finder
+
+// CaseBlindJaroFinder is a Finder with some default values suitable for a
+// Jaro algorithm already set. CaseMod is set to ForceToLower.
+var CaseBlindJaroFinder *Finder
+
+// DfltJaroWinklerFinder is a Finder with some default values suitable for a
+// Jaro-Winkler algorithm already set.
+var DfltJaroWinklerFinder *Finder
+
+// CaseBlindJaroWinklerFinder is a Finder with some default values suitable
+// for a Jaro-Winkler algorithm already set. CaseMod is set to ForceToLower.
+var CaseBlindJaroWinklerFinder *Finder
+
+func init() {
+	var err error
+	DfltJaroFinder, err =
+		NewJaroFinder(DfltMinStrLen, DfltJaroThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default JaroFinder: " + err.Error())
+	}
+	CaseBlindJaroFinder, err =
+		NewJaroFinder(DfltMinStrLen, DfltJaroThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind JaroFinder: " + err.Error())
+	}
+	DfltJaroWinklerFinder, err =
+		NewJaroWinklerFinder(DfltMinStrLen, DfltJaroThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default JaroWinklerFinder: " +
+			err.Error())
+	}
+	CaseBlindJaroWinklerFinder, err =
+		NewJaroWinklerFinder(DfltMinStrLen, DfltJaroThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind JaroWinklerFinder: " +
+			err.Error())
+	}
+}
+
+// JaroAlgo encapsulates the details needed to provide the Jaro distance.
+type JaroAlgo struct{}
+
+// NewJaroFinder returns a new Finder having a Jaro algo and an error which
+// will be non-nil if the parameters are invalid - see NewFinder for details.
+func NewJaroFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&JaroAlgo{})
+}
+
+// PrepQuery for a JaroAlgo will apply the CaseMod to the target string and
+// return a Query giving the Jaro distance from it
+func (a *JaroAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, JaroDistance)
+}
+
+// JaroWinklerAlgo encapsulates the details needed to provide the
+// Jaro-Winkler distance.
+type JaroWinklerAlgo struct{}
+
+// NewJaroWinklerFinder returns a new Finder having a Jaro-Winkler algo and
+// an error which will be non-nil if the parameters are invalid - see
+// NewFinder for details.
+func NewJaroWinklerFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&JaroWinklerAlgo{})
+}
+
+// PrepQuery for a JaroWinklerAlgo will apply the CaseMod to the target
+// string and return a Query giving the Jaro-Winkler distance from it
+func (a *JaroWinklerAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, JaroWinklerDistance)
+}
+
+// JaroSimilarity returns the Jaro similarity of the two strings. This is
+// calculated from the number of matching characters and the number of
+// transpositions between them. Characters are taken to match if they are
+// the same and are no further apart than half the length of the longer
+// string, less one. The similarity is between 0 (completely different) and
+// 1 (identical). Two zero-length strings are taken as identical.
+func JaroSimilarity(a, b string) float64 {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	aLen := len(aRunes)
+	bLen := len(bRunes)
+
+	if aLen == 0 && bLen == 0 {
+		return 1.0
+	}
+	if aLen == 0 || bLen == 0 {
+		return 0.0
+	}
+
+	matchDist := aLen
+	if bLen > matchDist {
+		matchDist = bLen
+	}
+	matchDist = matchDist/2 - 1
+	if matchDist < 0 {
+		matchDist = 0
+	}
+
+	aMatched := make([]bool, aLen)
+	bMatched := make([]bool, bLen)
+	matches := 0
+	for i, r := range aRunes {
+		lo := i - matchDist
+		if lo < 0 {
+			lo = 0
+		}
+		hi := i + matchDist
+		if hi > bLen-1 {
+			hi = bLen - 1
+		}
+		for j := lo; j <= hi; j++ {
+			if !bMatched[j] && bRunes[j] == r {
+				aMatched[i] = true
+				bMatched[j] = true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0.0
+	}
+
+	halfTranspositions := 0
+	j := 0
+	for i, r := range aRunes {
+		if !aMatched[i] {
+			continue
+		}
+		for !bMatched[j] {
+			j++
+		}
+		if r != bRunes[j] {
+			halfTranspositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	t := float64(halfTranspositions) / 2.0
+	return (m/float64(aLen) + m/float64(bLen) + (m-t)/m) / 3.0
+}
+
+// JaroDistance returns the Jaro distance between the two strings. This is 1
+// minus the JaroSimilarity
+func JaroDistance(a, b string) float64 {
+	return 1.0 - JaroSimilarity(a, b)
+}
+
+// JaroWinklerSimilarity returns the Jaro-Winkler similarity of the two
+// strings. This is the Jaro similarity increased for strings having a
+// common prefix of up to JaroWinklerMaxPrefix characters. This reflects the
+// observation that typing errors are less common at the start of a word.
+func JaroWinklerSimilarity(a, b string) float64 {
+	sim := JaroSimilarity(a, b)
+
+	prefixLen := 0
+	bRunes := []rune(b)
+	for i, r := range []rune(a) {
+		if i >= len(bRunes) || i >= JaroWinklerMaxPrefix || r != bRunes[i] {
+			break
+		}
+		prefixLen++
+	}
+
+	return sim + float64(prefixLen)*JaroWinklerPrefixScale*(1.0-sim)
+}
+
+// JaroWinklerDistance returns the Jaro-Winkler distance between the two
+// strings. This is 1 minus the JaroWinklerSimilarity
+func JaroWinklerDistance(a, b string) float64 {
+	return 1.0 - JaroWinklerSimilarity(a, b)
+}
@@ -0,0 +1,138 @@
+package strdist_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+func TestJaro(t *testing.T) {
+	const epsilon = 0.0005
+	testCases := []struct {
+		name     string
+		a, b     string
+		expJaro  float64
+		expJaroW float64
+	}{
+		{
+			name:     "zero char same",
+			expJaro:  1.0,
+			expJaroW: 1.0,
+		},
+		{
+			name: "one empty",
+			a:    "abc",
+		},
+		{
+			name: "nothing in common",
+			a:    "abc",
+			b:    "xyz",
+		},
+		{
+			name:     "same",
+			a:        "hello",
+			b:        "hello",
+			expJaro:  1.0,
+			expJaroW: 1.0,
+		},
+		{
+			name:     "MARTHA/MARHTA",
+			a:        "MARTHA",
+			b:        "MARHTA",
+			expJaro:  0.944,
+			expJaroW: 0.961,
+		},
+		{
+			name:     "DWAYNE/DUANE",
+			a:        "DWAYNE",
+			b:        "DUANE",
+			expJaro:  0.822,
+			expJaroW: 0.840,
+		},
+		{
+			name:     "DIXON/DICKSONX",
+			a:        "DIXON",
+			b:        "DICKSONX",
+			expJaro:  0.767,
+			expJaroW: 0.813,
+		},
+		{
+			name:     "multi-byte characters",
+			a:        "MÄRTHA",
+			b:        "MÄRHTA",
+			expJaro:  0.944,
+			expJaroW: 0.961,
+		},
+	}
+
+	for i, tc := range testCases {
+		for _, order := range []string{"a,b", "b,a"} {
+			a, b := tc.a, tc.b
+			if order == "b,a" {
+				a, b = b, a
+			}
+			tcID := fmt.Sprintf("test %d: %s (%s)", i, tc.name, order)
+			sim := strdist.JaroSimilarity(a, b)
+			if math.Abs(sim-tc.expJaro) > epsilon {
+				t.Log(tcID)
+				t.Errorf("\t: JaroSimilarity(%q, %q) expected: %.3f got: %.3f",
+					a, b, tc.expJaro, sim)
+			}
+			if d := strdist.JaroDistance(a, b); d != 1.0-sim {
+				t.Log(tcID)
+				t.Errorf("\t: JaroDistance(%q, %q) expected: %.3f got: %.3f",
+					a, b, 1.0-sim, d)
+			}
+			sim = strdist.JaroWinklerSimilarity(a, b)
+			if math.Abs(sim-tc.expJaroW) > epsilon {
+				t.Log(tcID)
+				t.Errorf("\t: JaroWinklerSimilarity(%q, %q)"+
+					" expected: %.3f got: %.3f",
+					a, b, tc.expJaroW, sim)
+			}
+			if d := strdist.JaroWinklerDistance(a, b); d != 1.0-sim {
+				t.Log(tcID)
+				t.Errorf("\t: JaroWinklerDistance(%q, %q)"+
+					" expected: %.3f got: %.3f",
+					a, b, 1.0-sim, d)
+			}
+		}
+	}
+}
+
+func TestJaroFinder(t *testing.T) {
+	pop := []string{"MARHTA", "marhta", "martin", "world"}
+	testCases := []struct {
+		name   string
+		finder *strdist.Finder
+		expect []string
+	}{
+		{
+			name:   "dflt Jaro",
+			finder: strdist.DfltJaroFinder,
+			expect: []string{"MARHTA"},
+		},
+		{
+			name:   "case-blind Jaro",
+			finder: strdist.CaseBlindJaroFinder,
+			expect: []string{"MARHTA", "marhta"},
+		},
+		{
+			name:   "dflt Jaro-Winkler",
+			finder: strdist.DfltJaroWinklerFinder,
+			expect: []string{"MARHTA"},
+		},
+		{
+			name:   "case-blind Jaro-Winkler",
+			finder: strdist.CaseBlindJaroWinklerFinder,
+			expect: []string{"MARHTA", "marhta", "martin"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		finderChecker(t, tcID, "", "MARTHA", pop, tc.finder, tc.expect)
+	}
+}
@@ -0,0 +1,155 @@
+package strdist
+
+import (
+	"math"
+	"unicode"
+)
+
+// DfltAdjacentKeyCost is a suggested cost of substituting a character for
+// one on an adjacent key. It reflects the observation that hitting a
+// neighbouring key is a common typing error
+const DfltAdjacentKeyCost = 0.5
+
+// KeyboardRow describes a row of keys on a keyboard. The Offset gives the
+// horizontal position of the first key in the row, measured in key widths
+// from the left of the keyboard. Keys gives the characters on the row when
+// no modifier key is pressed and Shifted gives the characters when the
+// shift key is pressed. The Shifted characters correspond to the Keys
+// characters by position and may be shorter than Keys or empty.
+type KeyboardRow struct {
+	Offset  float64
+	Keys    string
+	Shifted string
+}
+
+// keyPos records the position of a key on the keyboard
+type keyPos struct {
+	row     int
+	x       float64
+	shifted bool
+}
+
+// KeyboardLayout records the positions of the characters on a keyboard so
+// that the distance between keys can be found.
+type KeyboardLayout struct {
+	name string
+	keys map[rune]keyPos
+}
+
+// QWERTY is the standard US keyboard layout
+var QWERTY = NewKeyboardLayout("QWERTY",
+	KeyboardRow{Offset: 0, Keys: "`1234567890-=", Shifted: "~!@#$%^&*()_+"},
+	KeyboardRow{Offset: 1.5, Keys: `qwertyuiop[]\`, Shifted: "QWERTYUIOP{}|"},
+	KeyboardRow{Offset: 1.75, Keys: "asdfghjkl;'", Shifted: `ASDFGHJKL:"`},
+	KeyboardRow{Offset: 2.25, Keys: "zxcvbnm,./", Shifted: "ZXCVBNM<>?"},
+)
+
+// AZERTY is the standard French keyboard layout
+var AZERTY = NewKeyboardLayout("AZERTY",
+	KeyboardRow{Offset: 0, Keys: "²&é\"'(-è_çà)=", Shifted: " 1234567890°+"},
+	KeyboardRow{Offset: 1.5, Keys: "azertyuiop^$", Shifted: "AZERTYUIOP¨£"},
+	KeyboardRow{Offset: 1.75, Keys: "qsdfghjklmù*", Shifted: "QSDFGHJKLM%µ"},
+	KeyboardRow{Offset: 1.25, Keys: "<wxcvbn,;:!", Shifted: ">WXCVBN?./§"},
+)
+
+// Dvorak is the US Dvorak simplified keyboard layout
+var Dvorak = NewKeyboardLayout("Dvorak",
+	KeyboardRow{Offset: 0, Keys: "`1234567890[]", Shifted: "~!@#$%^&*(){}"},
+	KeyboardRow{Offset: 1.5, Keys: `',.pyfgcrl/=\`, Shifted: `"<>PYFGCRL?+|`},
+	KeyboardRow{Offset: 1.75, Keys: "aoeuidhtns-", Shifted: "AOEUIDHTNS_"},
+	KeyboardRow{Offset: 2.25, Keys: ";qjkxbmwvz", Shifted: ":QJKXBMWVZ"},
+)
+
+// NewKeyboardLayout returns a KeyboardLayout with the given name and rows of
+// keys. The rows are given from the top of the keyboard to the bottom. If a
+// character appears more than once only the first position is used. A space
+// in the Shifted characters is ignored so that keys without a shifted
+// character can be skipped.
+func NewKeyboardLayout(name string, rows ...KeyboardRow) *KeyboardLayout {
+	kl := &KeyboardLayout{
+		name: name,
+		keys: make(map[rune]keyPos),
+	}
+	for i, row := range rows {
+		for j, r := range []rune(row.Keys) {
+			kl.addKey(r, keyPos{row: i, x: row.Offset + float64(j)})
+		}
+		for j, r := range []rune(row.Shifted) {
+			if r == ' ' {
+				continue
+			}
+			kl.addKey(r,
+				keyPos{row: i, x: row.Offset + float64(j), shifted: true})
+		}
+	}
+	return kl
+}
+
+// addKey records the position of the key if it has not already been seen
+func (kl *KeyboardLayout) addKey(r rune, kp keyPos) {
+	if _, ok := kl.keys[r]; !ok {
+		kl.keys[r] = kp
+	}
+}
+
+// Name returns the name of the keyboard layout
+func (kl *KeyboardLayout) Name() string {
+	return kl.name
+}
+
+// pos returns the position of the rune on the keyboard and true if it is
+// on the keyboard, false otherwise. A character which is not on the keyboard
+// but whose lower-case equivalent is will be given that position
+func (kl *KeyboardLayout) pos(r rune) (keyPos, bool) {
+	kp, ok := kl.keys[r]
+	if !ok {
+		kp, ok = kl.keys[unicode.ToLower(r)]
+	}
+	return kp, ok
+}
+
+// Adjacent returns true if the two characters are on neighbouring keys or
+// are on the same key (as for a character and its shifted equivalent). It
+// returns false otherwise including if either character is not on the
+// keyboard
+func (kl *KeyboardLayout) Adjacent(a, b rune) bool {
+	aPos, ok := kl.pos(a)
+	if !ok {
+		return false
+	}
+	bPos, ok := kl.pos(b)
+	if !ok {
+		return false
+	}
+
+	dx := math.Abs(aPos.x - bPos.x)
+	switch aPos.row - bPos.row {
+	case 0:
+		return dx <= 1
+	case -1, 1:
+		return dx < 1
+	}
+	return false
+}
+
+// SubstitutionCost returns a function suitable for use as the Substitute
+// member of an EditCosts. The cost of substituting a character for one on
+// an adjacent key is given by the adjCost; any other substitution has a cost
+// of 1
+func (kl *KeyboardLayout) SubstitutionCost(adjCost float64) func(from, to rune) float64 {
+	return func(from, to rune) float64 {
+		if kl.Adjacent(from, to) {
+			return adjCost
+		}
+		return 1
+	}
+}
+
+// KeyboardEditCosts returns EditCosts with unit costs for insertion,
+// deletion and transposition and with the substitution cost reduced to
+// adjCost for characters on adjacent keys of the keyboard layout
+func KeyboardEditCosts(kl *KeyboardLayout, adjCost float64) EditCosts {
+	ec := UnitEditCosts()
+	ec.Substitute = kl.SubstitutionCost(adjCost)
+	return ec
+}
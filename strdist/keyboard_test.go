@@ -0,0 +1,181 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+func TestKeyboardAdjacent(t *testing.T) {
+	testCases := []struct {
+		name   string
+		kl     *strdist.KeyboardLayout
+		a, b   rune
+		expAdj bool
+	}{
+		{
+			name:   "QWERTY same row",
+			kl:     strdist.QWERTY,
+			a:      'a',
+			b:      's',
+			expAdj: true,
+		},
+		{
+			name:   "QWERTY row above",
+			kl:     strdist.QWERTY,
+			a:      'a',
+			b:      'q',
+			expAdj: true,
+		},
+		{
+			name:   "QWERTY row above right",
+			kl:     strdist.QWERTY,
+			a:      'a',
+			b:      'w',
+			expAdj: true,
+		},
+		{
+			name:   "QWERTY row below",
+			kl:     strdist.QWERTY,
+			a:      'a',
+			b:      'z',
+			expAdj: true,
+		},
+		{
+			name: "QWERTY two apart",
+			kl:   strdist.QWERTY,
+			a:    'a',
+			b:    'd',
+		},
+		{
+			name: "QWERTY too far up",
+			kl:   strdist.QWERTY,
+			a:    's',
+			b:    'q',
+		},
+		{
+			name: "QWERTY two rows",
+			kl:   strdist.QWERTY,
+			a:    'q',
+			b:    'z',
+		},
+		{
+			name:   "QWERTY shifted",
+			kl:     strdist.QWERTY,
+			a:      'a',
+			b:      'S',
+			expAdj: true,
+		},
+		{
+			name:   "QWERTY same key",
+			kl:     strdist.QWERTY,
+			a:      '-',
+			b:      '_',
+			expAdj: true,
+		},
+		{
+			name:   "QWERTY digit",
+			kl:     strdist.QWERTY,
+			a:      'q',
+			b:      '2',
+			expAdj: true,
+		},
+		{
+			name: "QWERTY not on keyboard",
+			kl:   strdist.QWERTY,
+			a:    'a',
+			b:    'é',
+		},
+		{
+			name:   "AZERTY a/z",
+			kl:     strdist.AZERTY,
+			a:      'a',
+			b:      'z',
+			expAdj: true,
+		},
+		{
+			name:   "AZERTY a/q",
+			kl:     strdist.AZERTY,
+			a:      'a',
+			b:      'q',
+			expAdj: true,
+		},
+		{
+			name:   "AZERTY w/x",
+			kl:     strdist.AZERTY,
+			a:      'w',
+			b:      'x',
+			expAdj: true,
+		},
+		{
+			name: "AZERTY a/s",
+			kl:   strdist.AZERTY,
+			a:    'a',
+			b:    's',
+		},
+		{
+			name:   "AZERTY accented",
+			kl:     strdist.AZERTY,
+			a:      'é',
+			b:      'z',
+			expAdj: true,
+		},
+		{
+			name:   "AZERTY q/w",
+			kl:     strdist.AZERTY,
+			a:      'q',
+			b:      'w',
+			expAdj: true,
+		},
+		{
+			name:   "AZERTY m/l",
+			kl:     strdist.AZERTY,
+			a:      'm',
+			b:      'l',
+			expAdj: true,
+		},
+		{
+			name: "AZERTY a/w",
+			kl:   strdist.AZERTY,
+			a:    'a',
+			b:    'w',
+		},
+		{
+			name:   "Dvorak a/o",
+			kl:     strdist.Dvorak,
+			a:      'a',
+			b:      'o',
+			expAdj: true,
+		},
+		{
+			name: "Dvorak a/s",
+			kl:   strdist.Dvorak,
+			a:    'a',
+			b:    's',
+		},
+		{
+			name:   "Dvorak p/u",
+			kl:     strdist.Dvorak,
+			a:      'p',
+			b:      'u',
+			expAdj: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		for _, order := range []string{"a,b", "b,a"} {
+			a, b := tc.a, tc.b
+			if order == "b,a" {
+				a, b = b, a
+			}
+			tcID := fmt.Sprintf("test %d: %s (%s)", i, tc.name, order)
+			adj := tc.kl.Adjacent(a, b)
+			if adj != tc.expAdj {
+				t.Log(tcID)
+				t.Errorf("\t: %s.Adjacent(%q, %q) should be %t",
+					tc.kl.Name(), a, b, tc.expAdj)
+			}
+		}
+	}
+}
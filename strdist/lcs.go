@@ -0,0 +1,84 @@
+package strdist
+
+// DfltLCSFinder is a Finder with some suitable default values suitable for
+// a Longest Common Subsequence algorithm already set.
+var DfltLCSFinder *Finder
+
+// CaseBlindLCSFinder is a Finder with some suitable default values suitable
+// for a Longest Common Subsequence algorithm already set. CaseMod is set to
+// ForceToLower.
+var CaseBlindLCSFinder *Finder
+
+func init() {
+	var err error
+	DfltLCSFinder, err =
+		NewLCSFinder(DfltMinStrLen, DfltLCSThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default LCSFinder: " + err.Error())
+	}
+	CaseBlindLCSFinder, err =
+		NewLCSFinder(DfltMinStrLen, DfltLCSThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind LCSFinder: " + err.Error())
+	}
+}
+
+// DfltLCSThreshold is a default value for deciding whether a distance
+// between two strings is sufficiently small for them to be considered
+// similar
+const DfltLCSThreshold = 5.0
+
+// LCSAlgo encapsulates the details needed to provide the Longest Common
+// Subsequence distance.
+type LCSAlgo struct{}
+
+// NewLCSFinder returns a new Finder having a Longest Common Subsequence algo
+// and an error which will be non-nil if the parameters are invalid - see
+// NewFinder for details.
+func NewLCSFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&LCSAlgo{})
+}
+
+// PrepQuery for an LCSAlgo will apply the CaseMod to the target string and
+// return a Query giving the Longest Common Subsequence distance from it
+func (a *LCSAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, intDist(LCSDistance))
+}
+
+// LCSLength returns the length of the longest common subsequence of the two
+// strings. A subsequence is a sequence of characters from the string in the
+// same order but not necessarily adjacent.
+func LCSLength(a, b string) int {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+
+	prev := make([]int, len(bRunes)+1)
+	curr := make([]int, len(bRunes)+1)
+	for _, aRune := range aRunes {
+		for j, bRune := range bRunes {
+			switch {
+			case aRune == bRune:
+				curr[j+1] = prev[j] + 1
+			case prev[j+1] > curr[j]:
+				curr[j+1] = prev[j+1]
+			default:
+				curr[j+1] = curr[j]
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(bRunes)]
+}
+
+// LCSDistance returns the Longest Common Subsequence distance between the
+// two strings. This is the number of characters in either string which are
+// not in the longest common subsequence; it is the minimum number of
+// insertions and deletions needed to change one string into the other.
+func LCSDistance(a, b string) int {
+	aLen := len([]rune(a))
+	bLen := len([]rune(b))
+
+	return aLen + bLen - 2*LCSLength(a, b)
+}
@@ -0,0 +1,91 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+func TestLCS(t *testing.T) {
+	testCases := []struct {
+		name    string
+		a, b    string
+		expLen  int
+		expDist int
+	}{
+		{
+			name: "zero char same",
+		},
+		{
+			name:    "one empty",
+			a:       "abc",
+			expDist: 3,
+		},
+		{
+			name:    "same",
+			a:       "abc",
+			b:       "abc",
+			expLen:  3,
+			expDist: 0,
+		},
+		{
+			name:    "nothing in common",
+			a:       "abc",
+			b:       "xyz",
+			expDist: 6,
+		},
+		{
+			name:    "ABCBDAB/BDCABA",
+			a:       "ABCBDAB",
+			b:       "BDCABA",
+			expLen:  4,
+			expDist: 5,
+		},
+		{
+			name:    "Kitten/Sitting",
+			a:       "Kitten",
+			b:       "Sitting",
+			expLen:  4,
+			expDist: 5,
+		},
+		{
+			name:    "multi-byte characters",
+			a:       "naïve",
+			b:       "naive",
+			expLen:  4,
+			expDist: 2,
+		},
+	}
+
+	for i, tc := range testCases {
+		for _, order := range []string{"a,b", "b,a"} {
+			a, b := tc.a, tc.b
+			if order == "b,a" {
+				a, b = b, a
+			}
+			tcID := fmt.Sprintf("test %d: %s (%s)", i, tc.name, order)
+			l := strdist.LCSLength(a, b)
+			if l != tc.expLen {
+				t.Log(tcID)
+				t.Errorf("\t: LCSLength(%q, %q) expected: %d got: %d",
+					a, b, tc.expLen, l)
+			}
+			d := strdist.LCSDistance(a, b)
+			if d != tc.expDist {
+				t.Log(tcID)
+				t.Errorf("\t: LCSDistance(%q, %q) expected: %d got: %d",
+					a, b, tc.expDist, d)
+			}
+		}
+	}
+}
+
+func TestLCSFinder(t *testing.T) {
+	pop := []string{"HELLO", "help", "world", "yellow"}
+	finderChecker(t, "dflt LCS", "", "hello", pop,
+		strdist.DfltLCSFinder, []string{"help", "yellow"})
+	finderChecker(t, "case-blind LCS", "", "hello", pop,
+		strdist.CaseBlindLCSFinder,
+		[]string{"HELLO", "help", "yellow"})
+}
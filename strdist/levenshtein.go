@@ -1,9 +1,6 @@
 package strdist
 
 import (
-	"strings"
-	"unicode/utf8"
-
 	"github.com/nickwells/golem/mathutil"
 )
 
@@ -40,9 +37,7 @@ const DfltLevenshteinThreshold = 5.0
 
 // LevenshteinAlgo encapsulates the details needed to provide the Levenshtein
 // distance.
-type LevenshteinAlgo struct {
-	s string
-}
+type LevenshteinAlgo struct{}
 
 // NewLevenshteinFinder returns a new Finder having a Levenshtein algo
 // and an error which will be non-nil if the parameters are invalid - see
@@ -52,31 +47,20 @@ func NewLevenshteinFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder
 		&LevenshteinAlgo{})
 }
 
-// Prep for a LevenshteinAlgo will pre-calculate the lower-case equivalent for
-// the target string if the caseMod is set to ForceToLower
-func (a *LevenshteinAlgo) Prep(s string, cm CaseMod) {
-	if cm == ForceToLower {
-		a.s = strings.ToLower(s)
-		return
-	}
-	a.s = s
-}
-
-// Dist for a LevenshteinAlgo will calculate the Levenshtein distance between
-// the two strings
-func (a *LevenshteinAlgo) Dist(_, s string, cm CaseMod) float64 {
-	if cm == ForceToLower {
-		return float64(LevenshteinDistance(a.s, strings.ToLower(s)))
-	}
-
-	return float64(LevenshteinDistance(a.s, s))
+// PrepQuery for a LevenshteinAlgo will apply the CaseMod to the target
+// string and return a Query giving the Levenshtein distance from it
+func (a *LevenshteinAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, intDist(LevenshteinDistance))
 }
 
 // LevenshteinDistance calculates the Levenshtein distance between strings a
-// and b
+// and b. The strings are compared rune by rune so a multi-byte character
+// counts as a single character
 func LevenshteinDistance(a, b string) int {
-	aLen := utf8.RuneCountInString(a)
-	bLen := utf8.RuneCountInString(b)
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	aLen := len(aRunes)
+	bLen := len(bRunes)
 	d := make([][]int, aLen+1)
 	for i := range d {
 		d[i] = make([]int, bLen+1)
@@ -87,8 +71,8 @@ func LevenshteinDistance(a, b string) int {
 		d[0][i] = i
 	}
 
-	for j, bRune := range b {
-		for i, aRune := range a {
+	for j, bRune := range bRunes {
+		for i, aRune := range aRunes {
 			var subsCost int
 			if aRune != bRune {
 				subsCost = 1
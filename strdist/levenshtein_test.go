@@ -51,6 +51,18 @@ func TestLevenshtein(t *testing.T) {
 			b:       "Sunday",
 			expDist: 3,
 		},
+		{
+			name:    "multi-byte characters",
+			a:       "naïve",
+			b:       "naive",
+			expDist: 1,
+		},
+		{
+			name:    "multi-byte characters, different lengths",
+			a:       "日本語",
+			b:       "日本",
+			expDist: 1,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -0,0 +1,158 @@
+package strdist
+
+import "strings"
+
+// metaphoneVowel returns true if the rune is a vowel for the purposes of the
+// Metaphone algorithm
+func metaphoneVowel(r rune) bool {
+	return strings.ContainsRune("AEIOU", r)
+}
+
+// metaphoneFrontVowel returns true if the rune is one of the vowels which
+// soften a preceding C or G
+func metaphoneFrontVowel(r rune) bool {
+	return strings.ContainsRune("EIY", r)
+}
+
+// Metaphone returns the code for the string given by Lawrence Philips'
+// original Metaphone algorithm. This encodes the string using a set of
+// rules for English pronunciation and is more accurate than Soundex. The
+// code uses the consonants B, F, H, J, K, L, M, N, P, R, S, T, W, X (for
+// 'sh') and Y and '0' (zero, for 'th'); a vowel is only kept if it starts
+// the string. The code is not truncated.
+//
+// Only the letters A to Z are encoded (after any accents have been removed)
+// and case is ignored. A string with no such letters has an empty code.
+func Metaphone(s string) string {
+	w := phoneticLetters(s, false)
+	if len(w) <= 1 {
+		return string(w)
+	}
+
+	switch {
+	case (w[0] == 'K' || w[0] == 'G' || w[0] == 'P') && w[1] == 'N',
+		w[0] == 'A' && w[1] == 'E',
+		w[0] == 'W' && w[1] == 'R':
+		w = w[1:]
+	case w[0] == 'W' && w[1] == 'H':
+		w = w[1:]
+		w[0] = 'W'
+	case w[0] == 'X':
+		w[0] = 'S'
+	}
+
+	l := len(w)
+	at := func(i int) rune {
+		if i < 0 || i >= l {
+			return 0
+		}
+		return w[i]
+	}
+	matches := func(i int, sub string) bool {
+		return i+len(sub) <= l && string(w[i:i+len(sub)]) == sub
+	}
+
+	var code strings.Builder
+	for i := 0; i < l; i++ {
+		c := w[i]
+		if c != 'C' && at(i-1) == c {
+			continue
+		}
+
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				code.WriteRune(c)
+			}
+		case 'B':
+			if !(at(i-1) == 'M' && i == l-1) {
+				code.WriteRune('B')
+			}
+		case 'C':
+			switch {
+			case at(i-1) == 'S' && metaphoneFrontVowel(at(i+1)):
+				// silent in SCI, SCE and SCY
+			case matches(i, "CIA"):
+				code.WriteRune('X')
+			case metaphoneFrontVowel(at(i + 1)):
+				code.WriteRune('S')
+			case at(i-1) == 'S' && at(i+1) == 'H':
+				code.WriteRune('K')
+			case at(i+1) == 'H':
+				if i == 0 && l >= 3 && metaphoneVowel(at(2)) {
+					code.WriteRune('K')
+				} else {
+					code.WriteRune('X')
+				}
+			default:
+				code.WriteRune('K')
+			}
+		case 'D':
+			if at(i+1) == 'G' && metaphoneFrontVowel(at(i+2)) {
+				code.WriteRune('J')
+				i += 2
+			} else {
+				code.WriteRune('T')
+			}
+		case 'G':
+			switch {
+			case at(i+1) == 'H' && i+2 == l,
+				at(i+1) == 'H' && !metaphoneVowel(at(i+2)),
+				i > 0 && (matches(i, "GN") || matches(i, "GNED")):
+				// silent in GH at the end or before a consonant and in GN
+			case metaphoneFrontVowel(at(i+1)) && at(i-1) != 'G':
+				code.WriteRune('J')
+			default:
+				code.WriteRune('K')
+			}
+		case 'H':
+			if i < l-1 &&
+				!strings.ContainsRune("CSPTG", at(i-1)) &&
+				metaphoneVowel(at(i+1)) {
+				code.WriteRune('H')
+			}
+		case 'F', 'J', 'L', 'M', 'N', 'R':
+			code.WriteRune(c)
+		case 'K':
+			if at(i-1) != 'C' {
+				code.WriteRune('K')
+			}
+		case 'P':
+			if at(i+1) == 'H' {
+				code.WriteRune('F')
+			} else {
+				code.WriteRune('P')
+			}
+		case 'Q':
+			code.WriteRune('K')
+		case 'S':
+			if matches(i, "SH") || matches(i, "SIO") || matches(i, "SIA") {
+				code.WriteRune('X')
+			} else {
+				code.WriteRune('S')
+			}
+		case 'T':
+			switch {
+			case matches(i, "TIA"), matches(i, "TIO"):
+				code.WriteRune('X')
+			case matches(i, "TCH"):
+				// silent in TCH
+			case matches(i, "TH"):
+				code.WriteRune('0')
+			default:
+				code.WriteRune('T')
+			}
+		case 'V':
+			code.WriteRune('F')
+		case 'W', 'Y':
+			if metaphoneVowel(at(i + 1)) {
+				code.WriteRune(c)
+			}
+		case 'X':
+			code.WriteString("KS")
+		case 'Z':
+			code.WriteRune('S')
+		}
+	}
+	return code.String()
+}
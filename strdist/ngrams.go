@@ -3,6 +3,7 @@ package strdist
 import (
 	"fmt"
 	"math"
+	"unicode/utf8"
 )
 
 // NGramSet represents a set of n-grams. Each n-gram has an associated weight
@@ -36,19 +37,21 @@ func (ngs NGramSet) Length() float64 {
 // NGrams transforms the string s into a map of n-grams (substrings of s each
 // of length n). The key is the n-gram and the value is the number of
 // occurrences. The length of the n-grams (n) must be greater than zero or an
-// error will be returned
+// error will be returned. The length is measured in runes so a multi-byte
+// character counts as a single character
 func NGrams(s string, n int) (NGramSet, error) {
 	if n <= 0 {
 		return nil, fmt.Errorf("invalid length of the n-gram: %d", n)
 	}
 
 	ngrams := make(NGramSet)
-	if len(s) < n {
+	if utf8.RuneCountInString(s) < n {
 		return ngrams, nil
 	}
 
 	chars := make([]rune, n)
-	for i, r := range s {
+	i := 0
+	for _, r := range s {
 		chars[i%n] = r
 		if i >= n-1 {
 			offset := (i + 1) % n
@@ -58,6 +61,7 @@ func NGrams(s string, n int) (NGramSet, error) {
 			}
 			ngrams[str]++
 		}
+		i++
 	}
 
 	return ngrams, nil
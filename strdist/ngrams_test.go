@@ -3,6 +3,7 @@ package strdist_test
 import (
 	"fmt"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/nickwells/golem/mathutil"
 	"github.com/nickwells/golem/strdist"
@@ -35,6 +36,18 @@ func TestNGrams(t *testing.T) {
 			n:                 4,
 			expDistinctNGrams: 0,
 		},
+		{
+			name:              "multi-byte characters",
+			s:                 "größe",
+			n:                 2,
+			expDistinctNGrams: 4,
+		},
+		{
+			name:              "multi-byte characters, too short string",
+			s:                 "日本語",
+			n:                 4,
+			expDistinctNGrams: 0,
+		},
 		{
 			name:              "bad n - zero",
 			s:                 "hel",
@@ -72,7 +85,7 @@ func TestNGrams(t *testing.T) {
 
 		totNGrams := 0
 		for k, v := range m {
-			if len(k) != tc.n {
+			if utf8.RuneCountInString(k) != tc.n {
 				t.Errorf(testDesc+"some n-grams are not of length %d eg: '%s'",
 					tc.n, k)
 				break
@@ -80,7 +93,7 @@ func TestNGrams(t *testing.T) {
 			totNGrams += v
 		}
 
-		expTotNGrams := len(tc.s) - tc.n + 1
+		expTotNGrams := utf8.RuneCountInString(tc.s) - tc.n + 1
 		if expTotNGrams < 0 {
 			expTotNGrams = 0
 		}
@@ -0,0 +1,108 @@
+package strdist
+
+import "strings"
+
+// nysiisVowel returns true if the rune is a vowel for the purposes of the
+// NYSIIS algorithm
+func nysiisVowel(r rune) bool {
+	return strings.ContainsRune("AEIOU", r)
+}
+
+// NYSIIS returns the code for the string given by the New York State
+// Identification and Intelligence System phonetic algorithm. This is more
+// discriminating than Soundex and the code retains the vowels (as 'A') in
+// their positions so that it is pronounceable. Note that the code is not
+// truncated to six characters as in the original algorithm.
+//
+// Only the letters A to Z are encoded (after any accents have been removed)
+// and case is ignored. A string with no such letters has an empty code.
+func NYSIIS(s string) string {
+	name := string(phoneticLetters(s, false))
+	if name == "" {
+		return ""
+	}
+
+	switch {
+	case strings.HasPrefix(name, "MAC"):
+		name = "MCC" + name[3:]
+	case strings.HasPrefix(name, "KN"):
+		name = name[1:]
+	case strings.HasPrefix(name, "K"):
+		name = "C" + name[1:]
+	case strings.HasPrefix(name, "PH"), strings.HasPrefix(name, "PF"):
+		name = "FF" + name[2:]
+	case strings.HasPrefix(name, "SCH"):
+		name = "SSS" + name[3:]
+	}
+
+	switch {
+	case strings.HasSuffix(name, "EE"), strings.HasSuffix(name, "IE"):
+		name = name[:len(name)-2] + "Y"
+	case strings.HasSuffix(name, "DT"), strings.HasSuffix(name, "RT"),
+		strings.HasSuffix(name, "RD"), strings.HasSuffix(name, "NT"),
+		strings.HasSuffix(name, "ND"):
+		name = name[:len(name)-2] + "D"
+	}
+
+	n := []rune(name)
+	key := []rune{n[0]}
+	for i := 1; i < len(n); i++ {
+		prev := n[i-1]
+		hasNext := i+1 < len(n)
+		var next rune
+		if hasNext {
+			next = n[i+1]
+		}
+
+		var trans string
+		switch c := n[i]; {
+		case c == 'E' && next == 'V':
+			trans = "AF"
+			i++
+		case nysiisVowel(c):
+			trans = "A"
+		case c == 'Q':
+			trans = "G"
+		case c == 'Z':
+			trans = "S"
+		case c == 'M':
+			trans = "N"
+		case c == 'K' && next == 'N':
+			trans = "N"
+		case c == 'K':
+			trans = "C"
+		case c == 'S' && i+2 < len(n) && next == 'C' && n[i+2] == 'H':
+			trans = "SS"
+			i += 2
+		case c == 'P' && next == 'H':
+			trans = "F"
+			i++
+		case c == 'H' && (!nysiisVowel(prev) || !hasNext || !nysiisVowel(next)):
+			trans = string(prev)
+			if nysiisVowel(prev) {
+				trans = "A"
+			}
+		case c == 'W' && nysiisVowel(prev):
+			trans = "A"
+		default:
+			trans = string(c)
+		}
+
+		t := []rune(trans)
+		if t[len(t)-1] != key[len(key)-1] {
+			key = append(key, t...)
+		}
+	}
+
+	code := string(key)
+	if len(code) > 1 && strings.HasSuffix(code, "S") {
+		code = code[:len(code)-1]
+	}
+	if strings.HasSuffix(code, "AY") {
+		code = code[:len(code)-2] + "Y"
+	}
+	if len(code) > 1 && strings.HasSuffix(code, "A") {
+		code = code[:len(code)-1]
+	}
+	return code
+}
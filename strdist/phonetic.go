@@ -0,0 +1,179 @@
+package strdist
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DfltPhoneticThreshold is a default value for deciding whether a distance
+// between two strings is sufficiently small for them to be considered
+// similar
+const DfltPhoneticThreshold = 0.3
+
+// DfltPhoneticLevWeight is a suggested proportion of the phonetic distance
+// to be taken from the ScaledLevDistance between the strings themselves. It
+// allows strings with the same phonetic codes to be ranked by how closely
+// they are spelt
+const DfltPhoneticLevWeight = 0.25
+
+// PhoneticEncoder returns the phonetic codes for a string. Most encodings
+// give a single code but some (such as Double Metaphone) may give more than
+// one
+type PhoneticEncoder func(s string) []string
+
+// SoundexEncoder is a PhoneticEncoder giving the Soundex code
+func SoundexEncoder(s string) []string {
+	return []string{Soundex(s)}
+}
+
+// MetaphoneEncoder is a PhoneticEncoder giving the Metaphone code
+func MetaphoneEncoder(s string) []string {
+	return []string{Metaphone(s)}
+}
+
+// DoubleMetaphoneEncoder is a PhoneticEncoder giving the primary and
+// alternate Double Metaphone codes. Only the primary code is given if the
+// codes are the same
+func DoubleMetaphoneEncoder(s string) []string {
+	primary, alternate := DoubleMetaphone(s)
+	if alternate == primary {
+		return []string{primary}
+	}
+	return []string{primary, alternate}
+}
+
+// NYSIISEncoder is a PhoneticEncoder giving the NYSIIS code
+func NYSIISEncoder(s string) []string {
+	return []string{NYSIIS(s)}
+}
+
+// DfltPhoneticFinder is a Finder with some default values suitable for a
+// Phonetic algorithm using the Double Metaphone encoding already set.
+var DfltPhoneticFinder *Finder
+
+// CaseBlindPhoneticFinder is a Finder with some default values suitable for
+// a Phonetic algorithm using the Double Metaphone encoding already
+// set. CaseMod is set to ForceToLower.
+var CaseBlindPhoneticFinder *Finder
+
+func init() {
+	var err error
+	DfltPhoneticFinder, err = NewPhoneticFinder(
+		DoubleMetaphoneEncoder, DfltPhoneticLevWeight,
+		DfltMinStrLen, DfltPhoneticThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default PhoneticFinder: " + err.Error())
+	}
+	CaseBlindPhoneticFinder, err = NewPhoneticFinder(
+		DoubleMetaphoneEncoder, DfltPhoneticLevWeight,
+		DfltMinStrLen, DfltPhoneticThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind PhoneticFinder: " +
+			err.Error())
+	}
+}
+
+// PhoneticAlgo encapsulates the details needed to provide the phonetic
+// distance.
+type PhoneticAlgo struct {
+	// Encoder gives the phonetic codes of the strings
+	Encoder PhoneticEncoder
+	// LevWeight is the proportion of the distance to be taken from the
+	// ScaledLevDistance between the strings themselves, the remainder is
+	// taken from the distance between their phonetic codes. It should be
+	// between 0 and 1
+	LevWeight float64
+}
+
+// phoneticQuery is the Query returned by a PhoneticAlgo
+type phoneticQuery struct {
+	enc       PhoneticEncoder
+	levWeight float64
+	cm        CaseMod
+	s         string
+	codes     []string
+}
+
+// NewPhoneticFinder returns a new Finder having a Phonetic algo and an
+// error which will be non-nil if the parameters are invalid. The encoder
+// must not be nil and the levWeight must be between 0 and 1; for other
+// invalid parameters see the NewFinder func.
+func NewPhoneticFinder(enc PhoneticEncoder, levWeight float64, minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	if enc == nil {
+		return nil, errors.New("the phonetic encoder must not be nil")
+	}
+	if levWeight < 0.0 || levWeight > 1.0 {
+		return nil,
+			fmt.Errorf("bad Levenshtein weight (%f)"+
+				" - it should be between 0.0 and 1.0", levWeight)
+	}
+
+	return NewFinder(minStrLen, threshold, cm,
+		&PhoneticAlgo{Encoder: enc, LevWeight: levWeight})
+}
+
+// PrepQuery for a PhoneticAlgo will apply the CaseMod to the target string
+// and pre-calculate its phonetic codes
+func (a *PhoneticAlgo) PrepQuery(s string, cm CaseMod) Query {
+	s = cm.Apply(s)
+	return phoneticQuery{
+		enc:       a.Encoder,
+		levWeight: a.LevWeight,
+		cm:        cm,
+		s:         s,
+		codes:     a.Encoder(s),
+	}
+}
+
+// Dist for a phoneticQuery will calculate the phonetic distance from the
+// target string
+func (q phoneticQuery) Dist(s string) float64 {
+	s = q.cm.Apply(s)
+	return phoneticBlend(q.levWeight,
+		phoneticCodeDistance(q.codes, q.enc(s)),
+		ScaledLevDistance(q.s, s))
+}
+
+// phoneticBlend combines the distance between the phonetic codes and the
+// ScaledLevDistance between the strings giving the latter a proportion of
+// levWeight
+func phoneticBlend(levWeight, codeDist, levDist float64) float64 {
+	if levWeight == 0.0 {
+		return codeDist
+	}
+	return (1.0-levWeight)*codeDist + levWeight*levDist
+}
+
+// PhoneticDistance returns the phonetic distance between strings a and
+// b. The strings are encoded using the encoder and the distance between them
+// is the smallest ScaledLevDistance between any code for a and any code for
+// b; strings with the same code are therefore at a zero distance. This is
+// then blended with the ScaledLevDistance between the strings themselves,
+// which is given a proportion of levWeight. Any empty codes are ignored and
+// if either string has no non-empty codes the distance between the codes is
+// taken as 1.
+func PhoneticDistance(a, b string, enc PhoneticEncoder, levWeight float64) float64 {
+	return phoneticBlend(levWeight,
+		phoneticCodeDistance(enc(a), enc(b)),
+		ScaledLevDistance(a, b))
+}
+
+// phoneticCodeDistance returns the smallest ScaledLevDistance between any
+// pair of non-empty codes. It returns 1 if there are no such pairs.
+func phoneticCodeDistance(codes1, codes2 []string) float64 {
+	dist := 1.0
+	for _, c1 := range codes1 {
+		if c1 == "" {
+			continue
+		}
+		for _, c2 := range codes2 {
+			if c2 == "" {
+				continue
+			}
+			if d := ScaledLevDistance(c1, c2); d < dist {
+				dist = d
+			}
+		}
+	}
+	return dist
+}
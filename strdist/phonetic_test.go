@@ -0,0 +1,241 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+func TestSoundex(t *testing.T) {
+	testCases := []struct {
+		s       string
+		expCode string
+	}{
+		{s: ""},
+		{s: "123"},
+		{s: "Robert", expCode: "R163"},
+		{s: "Rupert", expCode: "R163"},
+		{s: "Rubin", expCode: "R150"},
+		{s: "Ashcraft", expCode: "A261"},
+		{s: "Tymczak", expCode: "T522"},
+		{s: "Pfister", expCode: "P236"},
+		{s: "Honeyman", expCode: "H555"},
+		{s: "Lee", expCode: "L000"},
+		{s: "lee", expCode: "L000"},
+		{s: "Müller", expCode: "M460"},
+		{s: "O'Hara", expCode: "O600"},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %q", i, tc.s)
+		if code := strdist.Soundex(tc.s); code != tc.expCode {
+			t.Log(tcID)
+			t.Errorf("\t: expected: %q got: %q", tc.expCode, code)
+		}
+	}
+}
+
+func TestNYSIIS(t *testing.T) {
+	testCases := []struct {
+		s       string
+		expCode string
+	}{
+		{s: ""},
+		{s: "Catherine", expCode: "CATARAN"},
+		{s: "Katherine", expCode: "CATARAN"},
+		{s: "Brian", expCode: "BRAN"},
+		{s: "Bryan", expCode: "BRYAN"},
+		{s: "Worthy", expCode: "WARTY"},
+		{s: "Ogata", expCode: "OGAT"},
+		{s: "Tu", expCode: "T"},
+		{s: "Knight", expCode: "NAGT"},
+		{s: "Macintosh", expCode: "MCANT"},
+		{s: "Schmidt", expCode: "SNAD"},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %q", i, tc.s)
+		if code := strdist.NYSIIS(tc.s); code != tc.expCode {
+			t.Log(tcID)
+			t.Errorf("\t: expected: %q got: %q", tc.expCode, code)
+		}
+	}
+}
+
+func TestMetaphone(t *testing.T) {
+	testCases := []struct {
+		s       string
+		expCode string
+	}{
+		{s: ""},
+		{s: "a", expCode: "A"},
+		{s: "Knight", expCode: "NT"},
+		{s: "Thumb", expCode: "0M"},
+		{s: "Wright", expCode: "RT"},
+		{s: "Phillip", expCode: "FLP"},
+		{s: "Xavier", expCode: "SFR"},
+		{s: "White", expCode: "WT"},
+		{s: "Science", expCode: "SNS"},
+		{s: "Judge", expCode: "JJ"},
+		{s: "Nation", expCode: "NXN"},
+		{s: "Character", expCode: "KRKTR"},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %q", i, tc.s)
+		if code := strdist.Metaphone(tc.s); code != tc.expCode {
+			t.Log(tcID)
+			t.Errorf("\t: expected: %q got: %q", tc.expCode, code)
+		}
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	testCases := []struct {
+		s          string
+		expPrimary string
+		expAlt     string
+	}{
+		{s: ""},
+		{s: "Smith", expPrimary: "SM0", expAlt: "XMT"},
+		{s: "Schmidt", expPrimary: "XMT", expAlt: "SMT"},
+		{s: "Jose", expPrimary: "HS", expAlt: "HS"},
+		{s: "Caesar", expPrimary: "SSR", expAlt: "SSR"},
+		{s: "Thumb", expPrimary: "0M", expAlt: "TM"},
+		{s: "Xavier", expPrimary: "SF", expAlt: "SFR"},
+		{s: "Gallegos", expPrimary: "KLKS", expAlt: "KKS"},
+		{s: "Thomas", expPrimary: "TMS", expAlt: "TMS"},
+		{s: "Knight", expPrimary: "NT", expAlt: "NT"},
+		{s: "Philip", expPrimary: "FLP", expAlt: "FLP"},
+		{s: "Czerny", expPrimary: "SRN", expAlt: "XRN"},
+		{s: "Arnow", expPrimary: "ARN", expAlt: "ARNF"},
+		{s: "Bacchus", expPrimary: "PKS", expAlt: "PKS"},
+		{s: "Edge", expPrimary: "AJ", expAlt: "AJ"},
+		{s: "Laugh", expPrimary: "LF", expAlt: "LF"},
+		{s: "Michael", expPrimary: "MKL", expAlt: "MXL"},
+		{s: "Wasserman", expPrimary: "ASRM", expAlt: "FSRM"},
+		{s: "Zhao", expPrimary: "J", expAlt: "J"},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %q", i, tc.s)
+		primary, alt := strdist.DoubleMetaphone(tc.s)
+		if primary != tc.expPrimary || alt != tc.expAlt {
+			t.Log(tcID)
+			t.Errorf("\t: expected: %q/%q got: %q/%q",
+				tc.expPrimary, tc.expAlt, primary, alt)
+		}
+	}
+}
+
+func TestPhoneticDistance(t *testing.T) {
+	testCases := []struct {
+		name      string
+		a, b      string
+		enc       strdist.PhoneticEncoder
+		levWeight float64
+		expDist   float64
+	}{
+		{
+			name:    "same code, Soundex",
+			a:       "Robert",
+			b:       "Rupert",
+			enc:     strdist.SoundexEncoder,
+			expDist: 0.0,
+		},
+		{
+			name:    "one different digit, Soundex",
+			a:       "Robert",
+			b:       "Rubin",
+			enc:     strdist.SoundexEncoder,
+			expDist: 0.5,
+		},
+		{
+			name:    "alternate codes match, Double Metaphone",
+			a:       "Smith",
+			b:       "Schmidt",
+			enc:     strdist.DoubleMetaphoneEncoder,
+			expDist: 0.0,
+		},
+		{
+			name:      "same code, blended",
+			a:         "Catherine",
+			b:         "Katherine",
+			enc:       strdist.NYSIISEncoder,
+			levWeight: 0.5,
+			expDist:   0.5 * (1.0 / 9.0),
+		},
+		{
+			name:    "no letters",
+			a:       "123",
+			b:       "123",
+			enc:     strdist.MetaphoneEncoder,
+			expDist: 1.0,
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		d := strdist.PhoneticDistance(tc.a, tc.b, tc.enc, tc.levWeight)
+		if d != tc.expDist {
+			t.Log(tcID)
+			t.Errorf("\t: PhoneticDistance(%q, %q) expected: %.5f got: %.5f",
+				tc.a, tc.b, tc.expDist, d)
+		}
+	}
+}
+
+func TestNewPhoneticFinder(t *testing.T) {
+	testCases := []struct {
+		name        string
+		enc         strdist.PhoneticEncoder
+		levWeight   float64
+		errExpected bool
+	}{
+		{
+			name: "good",
+			enc:  strdist.SoundexEncoder,
+		},
+		{
+			name:        "nil encoder",
+			errExpected: true,
+		},
+		{
+			name:        "negative weight",
+			enc:         strdist.SoundexEncoder,
+			levWeight:   -0.1,
+			errExpected: true,
+		},
+		{
+			name:        "weight too big",
+			enc:         strdist.SoundexEncoder,
+			levWeight:   1.1,
+			errExpected: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		_, err := strdist.NewPhoneticFinder(tc.enc, tc.levWeight,
+			strdist.DfltMinStrLen, strdist.DfltPhoneticThreshold,
+			strdist.NoCaseChange)
+		if err == nil && tc.errExpected {
+			t.Log(tcID)
+			t.Errorf("\t: an error was expected but none was returned")
+		} else if err != nil && !tc.errExpected {
+			t.Log(tcID)
+			t.Errorf("\t: unexpected error: %s", err)
+		}
+	}
+}
+
+func TestPhoneticFinder(t *testing.T) {
+	pop := []string{"Schmidt", "Smyth", "Smithers", "Jones", "SMITH"}
+	finderChecker(t, "dflt Phonetic", "", "Smith", pop,
+		strdist.DfltPhoneticFinder,
+		[]string{"Smyth", "Schmidt", "SMITH", "Smithers"})
+	finderChecker(t, "case-blind Phonetic", "", "Smith", pop,
+		strdist.CaseBlindPhoneticFinder,
+		[]string{"SMITH", "Smyth", "Schmidt", "Smithers"})
+}
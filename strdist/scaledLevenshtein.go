@@ -2,10 +2,7 @@ package strdist
 
 import (
 	"math"
-	"strings"
 	"unicode/utf8"
-
-	"github.com/nickwells/golem/mathutil"
 )
 
 // DfltScaledLevFinder is a Finder with some default values suitable
@@ -39,9 +36,7 @@ const DfltScaledLevThreshold = 0.33
 
 // ScaledLevAlgo encapsulates the details needed to provide the ScaledLev
 // distance.
-type ScaledLevAlgo struct {
-	s string
-}
+type ScaledLevAlgo struct{}
 
 // NewScaledLevFinder returns a new Finder having a ScaledLev algo and an
 // error which will be non-nil if the parameters are invalid - see NewFinder
@@ -51,24 +46,10 @@ func NewScaledLevFinder(minStrLen int, threshold float64, cm CaseMod) (*Finder,
 		&ScaledLevAlgo{})
 }
 
-// Prep for a ScaledLevAlgo will pre-calculate the lower-case equivalent for
-// the target string if the caseMod is set to ForceToLower
-func (a *ScaledLevAlgo) Prep(s string, cm CaseMod) {
-	if cm == ForceToLower {
-		a.s = strings.ToLower(s)
-		return
-	}
-	a.s = s
-}
-
-// Dist for a ScaledLevAlgo will calculate the ScaledLev distance between
-// the two strings
-func (a *ScaledLevAlgo) Dist(_, s string, cm CaseMod) float64 {
-	if cm == ForceToLower {
-		return ScaledLevDistance(a.s, strings.ToLower(s))
-	}
-
-	return ScaledLevDistance(a.s, s)
+// PrepQuery for a ScaledLevAlgo will apply the CaseMod to the target string
+// and return a Query giving the ScaledLev distance from it
+func (a *ScaledLevAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return newStrQuery(s, cm, ScaledLevDistance)
 }
 
 // ScaledLevDistance calculates the Scaled Levenshtein distance between
@@ -83,30 +64,6 @@ func ScaledLevDistance(a, b string) float64 {
 		return 0.0
 	}
 
-	d := make([][]int, aLen+1)
-	for i := range d {
-		d[i] = make([]int, bLen+1)
-		d[i][0] = i
-	}
-
-	for i := 1; i <= bLen; i++ {
-		d[0][i] = i
-	}
-
-	for j, bRune := range b {
-		for i, aRune := range a {
-			var subsCost int
-			if aRune != bRune {
-				subsCost = 1
-			}
-
-			del := d[i][j+1] + 1
-			ins := d[i+1][j] + 1
-			sub := d[i][j] + subsCost
-
-			d[i+1][j+1] = mathutil.MinOfInt(del, ins, sub)
-		}
-	}
-
-	return float64(d[aLen][bLen]) / math.Max(float64(aLen), float64(bLen))
+	return float64(LevenshteinDistance(a, b)) /
+		math.Max(float64(aLen), float64(bLen))
 }
@@ -0,0 +1,79 @@
+package strdist
+
+import "unicode"
+
+// soundexLen is the length of a Soundex code
+const soundexLen = 4
+
+// soundexDigits maps each letter to its Soundex digit. Vowels and the
+// letters H, W and Y have no digit; they are given as '0' here
+var soundexDigits = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', '0', '0', '2', '2', '4', '5',
+	'5', '0', '1', '2', '6', '2', '3', '0', '1', '0', '2', '0', '2',
+}
+
+// phoneticLetters returns the string in upper case with any accents removed
+// and with all the characters other than the letters A to Z removed. If
+// keepSpaces is true then any white space is kept, with each run of white
+// space replaced by a single space. This prepares a string for the phonetic
+// encoders which only work with the letters of the English alphabet.
+func phoneticLetters(s string, keepSpaces bool) []rune {
+	letters := make([]rune, 0, len(s))
+	for _, r := range AccentBlind.Apply(s) {
+		r = unicode.ToUpper(r)
+		switch {
+		case r >= 'A' && r <= 'Z':
+			letters = append(letters, r)
+		case keepSpaces && unicode.IsSpace(r):
+			if len(letters) > 0 && letters[len(letters)-1] != ' ' {
+				letters = append(letters, ' ')
+			}
+		}
+	}
+	if len(letters) > 0 && letters[len(letters)-1] == ' ' {
+		letters = letters[:len(letters)-1]
+	}
+	return letters
+}
+
+// Soundex returns the American Soundex code for the string. This is the
+// first letter of the string followed by three digits encoding the
+// consonants which follow. Letters which sound alike are given the same
+// digit and adjacent letters with the same digit are encoded once; letters
+// separated by an H or a W are also treated as adjacent. The code is padded
+// with zeros if there are too few consonants.
+//
+// Only the letters A to Z are encoded (after any accents have been removed)
+// and case is ignored. A string with no such letters has an empty code.
+func Soundex(s string) string {
+	letters := phoneticLetters(s, false)
+	if len(letters) == 0 {
+		return ""
+	}
+
+	code := make([]byte, 1, soundexLen)
+	code[0] = byte(letters[0])
+	prev := soundexDigits[letters[0]-'A']
+
+	for _, r := range letters[1:] {
+		if len(code) == soundexLen {
+			break
+		}
+		d := soundexDigits[r-'A']
+		if d == '0' {
+			if r != 'H' && r != 'W' {
+				prev = d
+			}
+			continue
+		}
+		if d != prev {
+			code = append(code, d)
+		}
+		prev = d
+	}
+
+	for len(code) < soundexLen {
+		code = append(code, '0')
+	}
+	return string(code)
+}
@@ -0,0 +1,407 @@
+package strdist
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DfltTokenThreshold is a default value for deciding whether a token-sort
+// or token-set distance between two strings is sufficiently small for them
+// to be considered similar
+const DfltTokenThreshold = 0.33
+
+// DfltMongeElkanThreshold is a default value for deciding whether a
+// Monge-Elkan distance between two strings is sufficiently small for them
+// to be considered similar
+const DfltMongeElkanThreshold = 0.2
+
+// DfltTokenSeparators are the default characters which separate tokens
+const DfltTokenSeparators = " \t\n-_./:,"
+
+// DfltTokeniser is a Tokeniser splitting strings on the default separators
+// and on camelCase boundaries
+var DfltTokeniser = Tokeniser{
+	Separators:     DfltTokenSeparators,
+	SplitCamelCase: true,
+}
+
+// DfltTokenSortFinder is a Finder with some default values suitable for a
+// TokenSort algorithm already set.
+var DfltTokenSortFinder *Finder
+
+// CaseBlindTokenSortFinder is a Finder with some default values suitable for
+// a TokenSort algorithm already set. CaseMod is set to ForceToLower.
+var CaseBlindTokenSortFinder *Finder
+
+// DfltTokenSetFinder is a Finder with some default values suitable for a
+// TokenSet algorithm already set.
+var DfltTokenSetFinder *Finder
+
+// CaseBlindTokenSetFinder is a Finder with some default values suitable for
+// a TokenSet algorithm already set. CaseMod is set to ForceToLower.
+var CaseBlindTokenSetFinder *Finder
+
+// DfltMongeElkanFinder is a Finder with some default values suitable for a
+// MongeElkan algorithm using the Jaro-Winkler distance between tokens
+// already set.
+var DfltMongeElkanFinder *Finder
+
+// CaseBlindMongeElkanFinder is a Finder with some default values suitable
+// for a MongeElkan algorithm using the Jaro-Winkler distance between tokens
+// already set. CaseMod is set to ForceToLower.
+var CaseBlindMongeElkanFinder *Finder
+
+func init() {
+	var err error
+	DfltTokenSortFinder, err = NewTokenSortFinder(DfltTokeniser,
+		DfltMinStrLen, DfltTokenThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default TokenSortFinder: " + err.Error())
+	}
+	CaseBlindTokenSortFinder, err = NewTokenSortFinder(DfltTokeniser,
+		DfltMinStrLen, DfltTokenThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind TokenSortFinder: " +
+			err.Error())
+	}
+	DfltTokenSetFinder, err = NewTokenSetFinder(DfltTokeniser,
+		DfltMinStrLen, DfltTokenThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default TokenSetFinder: " + err.Error())
+	}
+	CaseBlindTokenSetFinder, err = NewTokenSetFinder(DfltTokeniser,
+		DfltMinStrLen, DfltTokenThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind TokenSetFinder: " +
+			err.Error())
+	}
+	DfltMongeElkanFinder, err = NewMongeElkanFinder(DfltTokeniser,
+		&JaroWinklerAlgo{},
+		DfltMinStrLen, DfltMongeElkanThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default MongeElkanFinder: " +
+			err.Error())
+	}
+	CaseBlindMongeElkanFinder, err = NewMongeElkanFinder(DfltTokeniser,
+		&JaroWinklerAlgo{},
+		DfltMinStrLen, DfltMongeElkanThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind MongeElkanFinder: " +
+			err.Error())
+	}
+}
+
+// Tokeniser records how a string is to be split into tokens
+type Tokeniser struct {
+	// Separators holds the characters which separate tokens. The separators
+	// are not part of any token
+	Separators string
+	// SplitCamelCase, if set, will split a string at camelCase boundaries;
+	// that is, between a lower-case letter or a digit and a following
+	// upper-case letter and between two upper-case letters where the second
+	// is followed by a lower-case letter (so "HTTPServer" gives "HTTP" and
+	// "Server")
+	SplitCamelCase bool
+}
+
+// Tokens returns the tokens in the string. Empty tokens are discarded.
+func (tk Tokeniser) Tokens(s string) []string {
+	tokens := []string{}
+	rs := []rune(s)
+	start := 0
+	for i, r := range rs {
+		if strings.ContainsRune(tk.Separators, r) {
+			if i > start {
+				tokens = append(tokens, string(rs[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if tk.SplitCamelCase && i > start && isCamelBoundary(rs, i) {
+			tokens = append(tokens, string(rs[start:i]))
+			start = i
+		}
+	}
+	if start < len(rs) {
+		tokens = append(tokens, string(rs[start:]))
+	}
+	return tokens
+}
+
+// isCamelBoundary returns true if a new camelCase word starts at position i
+func isCamelBoundary(rs []rune, i int) bool {
+	if !unicode.IsUpper(rs[i]) {
+		return false
+	}
+	prev := rs[i-1]
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(prev) &&
+		i+1 < len(rs) && unicode.IsLower(rs[i+1])
+}
+
+// modTokens returns the tokens in the string with the CaseMod applied to
+// each of them. The string is tokenised before the CaseMod is applied so
+// that camelCase boundaries are not lost
+func (tk Tokeniser) modTokens(s string, cm CaseMod) []string {
+	tokens := tk.Tokens(s)
+	for i, t := range tokens {
+		tokens[i] = cm.Apply(t)
+	}
+	return tokens
+}
+
+// sortedTokens returns the tokens sorted and joined with a single space
+func sortedTokens(tokens []string) string {
+	sorted := append([]string(nil), tokens...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+// tokenSet returns the distinct tokens
+func tokenSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		set[t] = true
+	}
+	return set
+}
+
+// TokenSortAlgo encapsulates the details needed to provide the token-sort
+// distance.
+type TokenSortAlgo struct {
+	Tokeniser Tokeniser
+}
+
+// tokenSortQuery is the Query returned by a TokenSortAlgo
+type tokenSortQuery struct {
+	tk     Tokeniser
+	cm     CaseMod
+	sorted string
+}
+
+// NewTokenSortFinder returns a new Finder having a TokenSort algo and an
+// error which will be non-nil if the parameters are invalid - see NewFinder
+// for details.
+func NewTokenSortFinder(tk Tokeniser, minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&TokenSortAlgo{Tokeniser: tk})
+}
+
+// PrepQuery for a TokenSortAlgo will pre-calculate the sorted tokens of the
+// target string
+func (a *TokenSortAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return tokenSortQuery{
+		tk:     a.Tokeniser,
+		cm:     cm,
+		sorted: sortedTokens(a.Tokeniser.modTokens(s, cm)),
+	}
+}
+
+// Dist for a tokenSortQuery will calculate the token-sort distance from the
+// target string
+func (q tokenSortQuery) Dist(s string) float64 {
+	return ScaledLevDistance(q.sorted, sortedTokens(q.tk.modTokens(s, q.cm)))
+}
+
+// TokenSortDistance returns the token-sort distance between strings a and
+// b. The strings are split into tokens which are then sorted and joined with
+// a single space. The distance is the ScaledLevDistance between the
+// resulting strings. This means that strings having the same tokens in a
+// different order, or with different separators, are at a zero distance.
+func TokenSortDistance(a, b string, tk Tokeniser) float64 {
+	return ScaledLevDistance(
+		sortedTokens(tk.Tokens(a)), sortedTokens(tk.Tokens(b)))
+}
+
+// TokenSetAlgo encapsulates the details needed to provide the token-set
+// distance.
+type TokenSetAlgo struct {
+	Tokeniser Tokeniser
+}
+
+// tokenSetQuery is the Query returned by a TokenSetAlgo
+type tokenSetQuery struct {
+	tk  Tokeniser
+	cm  CaseMod
+	set map[string]bool
+}
+
+// NewTokenSetFinder returns a new Finder having a TokenSet algo and an
+// error which will be non-nil if the parameters are invalid - see NewFinder
+// for details.
+func NewTokenSetFinder(tk Tokeniser, minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	return NewFinder(minStrLen, threshold, cm,
+		&TokenSetAlgo{Tokeniser: tk})
+}
+
+// PrepQuery for a TokenSetAlgo will pre-calculate the set of tokens of the
+// target string
+func (a *TokenSetAlgo) PrepQuery(s string, cm CaseMod) Query {
+	return tokenSetQuery{
+		tk:  a.Tokeniser,
+		cm:  cm,
+		set: tokenSet(a.Tokeniser.modTokens(s, cm)),
+	}
+}
+
+// Dist for a tokenSetQuery will calculate the token-set distance from the
+// target string
+func (q tokenSetQuery) Dist(s string) float64 {
+	return tokenSetDistance(q.set, tokenSet(q.tk.modTokens(s, q.cm)))
+}
+
+// TokenSetDistance returns the token-set distance between strings a and
+// b. The strings are split into tokens and any duplicate tokens are
+// discarded. Three strings are then formed: the tokens common to both
+// strings (sorted and joined with a single space) and that string followed
+// by the sorted tokens unique to each of a and b. The distance is the
+// smallest ScaledLevDistance between any pair of these. This means that if
+// the tokens of one string are a subset of the tokens of the other, the
+// strings are at a zero distance.
+func TokenSetDistance(a, b string, tk Tokeniser) float64 {
+	return tokenSetDistance(tokenSet(tk.Tokens(a)), tokenSet(tk.Tokens(b)))
+}
+
+// tokenSetDistance returns the token-set distance between two sets of tokens
+func tokenSetDistance(setA, setB map[string]bool) float64 {
+	if len(setA) == 0 && len(setB) == 0 {
+		return 0.0
+	}
+	if len(setA) == 0 || len(setB) == 0 {
+		return 1.0
+	}
+
+	var common, onlyA, onlyB []string
+	for t := range setA {
+		if setB[t] {
+			common = append(common, t)
+		} else {
+			onlyA = append(onlyA, t)
+		}
+	}
+	for t := range setB {
+		if !setA[t] {
+			onlyB = append(onlyB, t)
+		}
+	}
+
+	t0 := sortedTokens(common)
+	t1 := strings.TrimSpace(t0 + " " + sortedTokens(onlyA))
+	t2 := strings.TrimSpace(t0 + " " + sortedTokens(onlyB))
+
+	d := ScaledLevDistance(t1, t2)
+	if t0 != "" {
+		d = math.Min(d, ScaledLevDistance(t0, t1))
+		d = math.Min(d, ScaledLevDistance(t0, t2))
+	}
+	return d
+}
+
+// MongeElkanAlgo encapsulates the details needed to provide the Monge-Elkan
+// distance. The Inner algorithm gives the distance between pairs of tokens;
+// it should give distances between 0 and 1, any distance greater than 1 is
+// taken as 1.
+type MongeElkanAlgo struct {
+	Tokeniser Tokeniser
+	Inner     Algo
+}
+
+// mongeElkanQuery is the Query returned by a MongeElkanAlgo. It holds the
+// target tokens each prepared by the inner algorithm
+type mongeElkanQuery struct {
+	tk      Tokeniser
+	inner   Algo
+	cm      CaseMod
+	tokens  []string
+	queries []Query
+}
+
+// NewMongeElkanFinder returns a new Finder having a MongeElkan algo and an
+// error which will be non-nil if the parameters are invalid. The inner
+// algorithm must not be nil; for other invalid parameters see the NewFinder
+// func.
+func NewMongeElkanFinder(tk Tokeniser, inner Algo, minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	if inner == nil {
+		return nil, errors.New("the inner algorithm must not be nil")
+	}
+
+	return NewFinder(minStrLen, threshold, cm,
+		&MongeElkanAlgo{Tokeniser: tk, Inner: inner})
+}
+
+// PrepQuery for a MongeElkanAlgo will pre-calculate the tokens of the target
+// string and prepare each of them with the inner algorithm
+func (a *MongeElkanAlgo) PrepQuery(s string, cm CaseMod) Query {
+	tokens := a.Tokeniser.modTokens(s, cm)
+	return mongeElkanQuery{
+		tk:      a.Tokeniser,
+		inner:   a.Inner,
+		cm:      cm,
+		tokens:  tokens,
+		queries: prepTokens(a.Inner, tokens),
+	}
+}
+
+// Dist for a mongeElkanQuery will calculate the Monge-Elkan distance from
+// the target string
+func (q mongeElkanQuery) Dist(s string) float64 {
+	return mongeElkanDistance(q.tokens, q.queries,
+		q.tk.modTokens(s, q.cm), q.inner)
+}
+
+// MongeElkanDistance returns the Monge-Elkan distance between strings a and
+// b. The strings are split into tokens and, for each token of a, the
+// distance to the closest token of b is found using the inner algorithm;
+// the mean of these distances is the distance from a to b. As this is not
+// symmetric, the distance returned is the mean of the distances from a to b
+// and from b to a. Two strings with no tokens are at a zero distance and a
+// string with no tokens is at a distance of 1 from any string with tokens.
+func MongeElkanDistance(a, b string, tk Tokeniser, inner Algo) float64 {
+	tokensA := tk.Tokens(a)
+	return mongeElkanDistance(tokensA, prepTokens(inner, tokensA),
+		tk.Tokens(b), inner)
+}
+
+// prepTokens returns the tokens each prepared by the algorithm
+func prepTokens(a Algo, tokens []string) []Query {
+	queries := make([]Query, 0, len(tokens))
+	for _, t := range tokens {
+		queries = append(queries, a.PrepQuery(t, NoCaseChange))
+	}
+	return queries
+}
+
+// mongeElkanDistance returns the symmetric Monge-Elkan distance between two
+// lists of tokens. The queries are the first list of tokens prepared by the
+// inner algorithm
+func mongeElkanDistance(tokensA []string, queriesA []Query, tokensB []string, inner Algo) float64 {
+	if len(tokensA) == 0 && len(tokensB) == 0 {
+		return 0.0
+	}
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 1.0
+	}
+	return (mongeElkanDirected(queriesA, tokensB) +
+		mongeElkanDirected(prepTokens(inner, tokensB), tokensA)) / 2
+}
+
+// mongeElkanDirected returns the mean, over the prepared tokens in from, of
+// the distance to the closest token in to
+func mongeElkanDirected(from []Query, to []string) float64 {
+	var total float64
+	for _, q := range from {
+		best := 1.0
+		for _, t := range to {
+			if d := q.Dist(t); d < best {
+				best = d
+			}
+		}
+		total += best
+	}
+	return total / float64(len(from))
+}
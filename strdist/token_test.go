@@ -0,0 +1,189 @@
+package strdist_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+	"github.com/nickwells/golem/testhelper"
+)
+
+func TestTokens(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tk        strdist.Tokeniser
+		s         string
+		expTokens []string
+	}{
+		{
+			name:      "empty",
+			tk:        strdist.DfltTokeniser,
+			expTokens: []string{},
+		},
+		{
+			name:      "only separators",
+			tk:        strdist.DfltTokeniser,
+			s:         "--_ ",
+			expTokens: []string{},
+		},
+		{
+			name:      "mixed separators",
+			tk:        strdist.DfltTokeniser,
+			s:         "us east_1--west",
+			expTokens: []string{"us", "east", "1", "west"},
+		},
+		{
+			name:      "camelCase",
+			tk:        strdist.DfltTokeniser,
+			s:         "showAllParams",
+			expTokens: []string{"show", "All", "Params"},
+		},
+		{
+			name:      "camelCase with acronym and digits",
+			tk:        strdist.DfltTokeniser,
+			s:         "HTTPServer2Go",
+			expTokens: []string{"HTTP", "Server2", "Go"},
+		},
+		{
+			name:      "no camelCase splitting",
+			tk:        strdist.Tokeniser{Separators: "-"},
+			s:         "showAll-params",
+			expTokens: []string{"showAll", "params"},
+		},
+		{
+			name:      "multi-byte characters",
+			tk:        strdist.DfltTokeniser,
+			s:         "café-Über",
+			expTokens: []string{"café", "Über"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		tokens := tc.tk.Tokens(tc.s)
+		if testhelper.StringSliceDiff(tokens, tc.expTokens) {
+			t.Log(tcID)
+			t.Logf("\t: expected: %q", tc.expTokens)
+			t.Logf("\t:      got: %q", tokens)
+			t.Errorf("\t: unexpected tokens")
+		}
+	}
+}
+
+func TestTokenDistances(t *testing.T) {
+	testCases := []struct {
+		name             string
+		a, b             string
+		expSortDist      float64
+		expSetDist       float64
+		expMongeElkanLev float64
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name:             "one empty",
+			a:                "us-east-1",
+			expSortDist:      1,
+			expSetDist:       1,
+			expMongeElkanLev: 1,
+		},
+		{
+			name: "reordered",
+			a:    "us east 1",
+			b:    "east-us-1",
+		},
+		{
+			name:             "subset",
+			a:                "show-params",
+			b:                "show-all-params",
+			expSortDist:      4.0 / 15.0,
+			expSetDist:       0,
+			expMongeElkanLev: 0.5 * (5.0 / 6.0) / 3.0,
+		},
+		{
+			name:             "misspelt token",
+			a:                "east-us",
+			b:                "us-eats",
+			expSortDist:      2.0 / 7.0,
+			expSetDist:       2.0 / 7.0,
+			expMongeElkanLev: 0.25,
+		},
+		{
+			name:             "nothing in common",
+			a:                "abc",
+			b:                "xyz",
+			expSortDist:      1,
+			expSetDist:       1,
+			expMongeElkanLev: 1,
+		},
+	}
+
+	for i, tc := range testCases {
+		for _, order := range []string{"a,b", "b,a"} {
+			a, b := tc.a, tc.b
+			if order == "b,a" {
+				a, b = b, a
+			}
+			tcID := fmt.Sprintf("test %d: %s (%s)", i, tc.name, order)
+			d := strdist.TokenSortDistance(a, b, strdist.DfltTokeniser)
+			if math.Abs(d-tc.expSortDist) > 1e-9 {
+				t.Log(tcID)
+				t.Errorf("\t: TokenSortDistance(%q, %q) expected: %.5f got: %.5f",
+					a, b, tc.expSortDist, d)
+			}
+			d = strdist.TokenSetDistance(a, b, strdist.DfltTokeniser)
+			if math.Abs(d-tc.expSetDist) > 1e-9 {
+				t.Log(tcID)
+				t.Errorf("\t: TokenSetDistance(%q, %q) expected: %.5f got: %.5f",
+					a, b, tc.expSetDist, d)
+			}
+			d = strdist.MongeElkanDistance(a, b, strdist.DfltTokeniser,
+				&strdist.ScaledLevAlgo{})
+			if math.Abs(d-tc.expMongeElkanLev) > 1e-9 {
+				t.Log(tcID)
+				t.Errorf("\t: MongeElkanDistance(%q, %q) expected: %.5f got: %.5f",
+					a, b, tc.expMongeElkanLev, d)
+			}
+		}
+	}
+}
+
+func TestNewMongeElkanFinder(t *testing.T) {
+	_, err := strdist.NewMongeElkanFinder(strdist.DfltTokeniser, nil,
+		strdist.DfltMinStrLen, strdist.DfltMongeElkanThreshold,
+		strdist.NoCaseChange)
+	if err == nil {
+		t.Errorf("a nil inner algorithm should have returned an error")
+	}
+}
+
+func TestTokenFinders(t *testing.T) {
+	pop := []string{
+		"show-all-params",
+		"params-show",
+		"ShowParams",
+		"show-groups",
+		"hide-params",
+		"verbose",
+	}
+	finderChecker(t, "dflt TokenSort", "", "params-show-all", pop,
+		strdist.DfltTokenSortFinder,
+		[]string{"show-all-params", "params-show"})
+	finderChecker(t, "case-blind TokenSort", "", "show-params", pop,
+		strdist.CaseBlindTokenSortFinder,
+		[]string{"ShowParams", "params-show", "show-all-params"})
+	finderChecker(t, "dflt TokenSet", "", "params-show", pop,
+		strdist.DfltTokenSetFinder,
+		[]string{"params-show", "show-all-params", "ShowParams"})
+	finderChecker(t, "case-blind TokenSet", "", "params-show", pop,
+		strdist.CaseBlindTokenSetFinder,
+		[]string{"ShowParams", "params-show", "show-all-params"})
+	finderChecker(t, "dflt MongeElkan", "", "shwo-params", pop,
+		strdist.DfltMongeElkanFinder,
+		[]string{"params-show", "show-all-params", "ShowParams"})
+	finderChecker(t, "case-blind MongeElkan", "", "shwo-params", pop,
+		strdist.CaseBlindMongeElkanFinder,
+		[]string{"ShowParams", "params-show", "show-all-params"})
+}
@@ -0,0 +1,198 @@
+package strdist_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+func TestCaseModApply(t *testing.T) {
+	testCases := []struct {
+		name string
+		cm   strdist.CaseMod
+		s    string
+		exp  string
+	}{
+		{
+			name: "no change",
+			cm:   strdist.NoCaseChange,
+			s:    "Straße",
+			exp:  "Straße",
+		},
+		{
+			name: "lower case",
+			cm:   strdist.ForceToLower,
+			s:    "Straße",
+			exp:  "straße",
+		},
+		{
+			name: "fold - sharp s",
+			cm:   strdist.UnicodeFold,
+			s:    "Straße",
+			exp:  "strasse",
+		},
+		{
+			name: "fold - final sigma",
+			cm:   strdist.UnicodeFold,
+			s:    "ΣΊΣΥΦΟΣ",
+			exp:  "σίσυφοσ",
+		},
+		{
+			name: "fold - long s, Kelvin sign and ligature",
+			cm:   strdist.UnicodeFold,
+			s:    "ſ\u212a\ufb01",
+			exp:  "skfi",
+		},
+		{
+			name: "fold - dotless i is unchanged",
+			cm:   strdist.UnicodeFold,
+			s:    "ıI",
+			exp:  "ıi",
+		},
+		{
+			name: "accent blind - combining marks",
+			cm:   strdist.AccentBlind,
+			s:    "Cafe\u0301 Ω\u0301",
+			exp:  "cafe ω",
+		},
+		{
+			name: "accent blind - Greek and Cyrillic",
+			cm:   strdist.AccentBlind,
+			s:    "Ἀθῆναι Йод",
+			exp:  "αθηναι иод",
+		},
+		{
+			name: "accent blind",
+			cm:   strdist.AccentBlind,
+			s:    "Crème Brûlée",
+			exp:  "creme brulee",
+		},
+		{
+			name: "accent blind - precomposed and combining",
+			cm:   strdist.AccentBlind,
+			s:    "Ångström",
+			exp:  "angstrom",
+		},
+		{
+			name: "accent blind - no accents",
+			cm:   strdist.AccentBlind,
+			s:    "日本語",
+			exp:  "日本語",
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		s := tc.cm.Apply(tc.s)
+		if s != tc.exp {
+			t.Log(tcID)
+			t.Errorf("\t: %s.Apply(%q) expected: %q got: %q",
+				tc.cm, tc.s, tc.exp, s)
+		}
+	}
+}
+
+func TestUnicodeFinders(t *testing.T) {
+	pop := []string{
+		"résumé",
+		"RESUME",
+		"Résumé",
+		"resumes",
+		"rèsumè",
+		"preset",
+	}
+	testCases := []struct {
+		name   string
+		cm     strdist.CaseMod
+		expect []string
+	}{
+		{
+			name:   "no case change",
+			cm:     strdist.NoCaseChange,
+			expect: []string{"résumé"},
+		},
+		{
+			name:   "lower case",
+			cm:     strdist.ForceToLower,
+			expect: []string{"Résumé", "résumé"},
+		},
+		{
+			name:   "accent blind",
+			cm:     strdist.AccentBlind,
+			expect: []string{"RESUME", "Résumé", "rèsumè", "résumé"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		f, err := strdist.NewLevenshteinFinder(4, 0.0, tc.cm)
+		if err != nil {
+			t.Fatal("couldn't create the Levenshtein Finder: ", err)
+		}
+		finderChecker(t, tcID, "Levenshtein", "résumé", pop, f, tc.expect)
+
+		f, err = strdist.NewCosineFinder(2, 4, 0.001, tc.cm)
+		if err != nil {
+			t.Fatal("couldn't create the Cosine Finder: ", err)
+		}
+		finderChecker(t, tcID, "Cosine", "résumé", pop, f, tc.expect)
+	}
+}
+
+func TestUnicodeParamNames(t *testing.T) {
+	params := []string{
+		"größe",
+		"grösse",
+		"naïve",
+		"café-au-lait",
+		"日本語",
+		"size",
+	}
+	testCases := []struct {
+		name   string
+		cm     strdist.CaseMod
+		target string
+		expect []string
+	}{
+		{
+			name:   "transposed multi-byte character",
+			cm:     strdist.NoCaseChange,
+			target: "grßöe",
+			expect: []string{"größe"},
+		},
+		{
+			name:   "case folded",
+			cm:     strdist.UnicodeFold,
+			target: "GRÖSSE",
+			expect: []string{"grösse", "größe"},
+		},
+		{
+			name:   "accents missing",
+			cm:     strdist.AccentBlind,
+			target: "cafe-au-lait",
+			expect: []string{"café-au-lait"},
+		},
+		{
+			name:   "long enough in bytes, short in runes",
+			cm:     strdist.NoCaseChange,
+			target: "日本",
+			expect: []string{},
+		},
+		{
+			name:   "CJK",
+			cm:     strdist.NoCaseChange,
+			target: "日本人",
+			expect: []string{"日本語"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		f, err := strdist.NewDamerauLevenshteinFinder(3, 1.0, tc.cm)
+		if err != nil {
+			t.Fatal("couldn't create the DamerauLevenshtein Finder: ", err)
+		}
+		finderChecker(t, tcID, "", tc.target, params, f, tc.expect)
+	}
+}
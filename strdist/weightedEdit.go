@@ -0,0 +1,165 @@
+package strdist
+
+import (
+	"fmt"
+	"math"
+)
+
+// DfltWeightedEditThreshold is a default value for deciding whether a
+// distance between two strings is sufficiently small for them to be
+// considered similar
+const DfltWeightedEditThreshold = 2.0
+
+// DfltKeyboardFinder is a Finder with some default values suitable for a
+// WeightedEdit algorithm using the QWERTY keyboard costs already set.
+var DfltKeyboardFinder *Finder
+
+// CaseBlindKeyboardFinder is a Finder with some default values suitable for
+// a WeightedEdit algorithm using the QWERTY keyboard costs already
+// set. CaseMod is set to ForceToLower.
+var CaseBlindKeyboardFinder *Finder
+
+func init() {
+	var err error
+	DfltKeyboardFinder, err = NewWeightedEditFinder(
+		KeyboardEditCosts(QWERTY, DfltAdjacentKeyCost),
+		DfltMinStrLen, DfltWeightedEditThreshold, NoCaseChange)
+	if err != nil {
+		panic("Cannot construct the default KeyboardFinder: " + err.Error())
+	}
+	CaseBlindKeyboardFinder, err = NewWeightedEditFinder(
+		KeyboardEditCosts(QWERTY, DfltAdjacentKeyCost),
+		DfltMinStrLen, DfltWeightedEditThreshold, ForceToLower)
+	if err != nil {
+		panic("Cannot construct the case-blind KeyboardFinder: " +
+			err.Error())
+	}
+}
+
+// EditCosts records the costs of each of the edit operations used when
+// calculating a weighted edit distance.
+type EditCosts struct {
+	// Insert is the cost of inserting a character
+	Insert float64
+	// Delete is the cost of deleting a character
+	Delete float64
+	// Transpose is the cost of swapping two adjacent characters
+	Transpose float64
+	// Substitute returns the cost of replacing the first rune with the
+	// second. It is only called for runes which differ. If it is nil then
+	// every substitution has a cost of 1.
+	Substitute func(from, to rune) float64
+}
+
+// UnitEditCosts returns EditCosts where every operation has a cost of
+// 1. With these costs the weighted edit distance is the same as the Optimal
+// String Alignment distance (see OSADistance)
+func UnitEditCosts() EditCosts {
+	return EditCosts{
+		Insert:    1,
+		Delete:    1,
+		Transpose: 1,
+	}
+}
+
+// Check returns a non-nil error if the EditCosts are invalid. The costs of
+// insertion, deletion and transposition must all be greater than zero
+func (ec EditCosts) Check() error {
+	if ec.Insert <= 0 {
+		return fmt.Errorf("bad insertion cost (%f) - it should be > 0.0",
+			ec.Insert)
+	}
+	if ec.Delete <= 0 {
+		return fmt.Errorf("bad deletion cost (%f) - it should be > 0.0",
+			ec.Delete)
+	}
+	if ec.Transpose <= 0 {
+		return fmt.Errorf("bad transposition cost (%f) - it should be > 0.0",
+			ec.Transpose)
+	}
+	return nil
+}
+
+// subsCost returns the cost of substituting rune to for rune from
+func (ec EditCosts) subsCost(from, to rune) float64 {
+	if from == to {
+		return 0
+	}
+	if ec.Substitute == nil {
+		return 1
+	}
+	return ec.Substitute(from, to)
+}
+
+// WeightedEditAlgo encapsulates the details needed to provide the weighted
+// edit distance.
+type WeightedEditAlgo struct {
+	Costs EditCosts
+}
+
+// NewWeightedEditFinder returns a new Finder having a WeightedEdit algo and
+// an error which will be non-nil if the parameters are invalid. The costs
+// must be valid (see EditCosts.Check); for other invalid parameters see the
+// NewFinder func.
+func NewWeightedEditFinder(costs EditCosts, minStrLen int, threshold float64, cm CaseMod) (*Finder, error) {
+	if err := costs.Check(); err != nil {
+		return nil, err
+	}
+
+	return NewFinder(minStrLen, threshold, cm,
+		&WeightedEditAlgo{Costs: costs})
+}
+
+// PrepQuery for a WeightedEditAlgo will apply the CaseMod to the target
+// string and return a Query giving the weighted edit distance from it
+func (a *WeightedEditAlgo) PrepQuery(s string, cm CaseMod) Query {
+	costs := a.Costs
+	return newStrQuery(s, cm, func(a, b string) float64 {
+		return WeightedEditDistance(a, b, costs)
+	})
+}
+
+// WeightedEditDistance calculates the lowest total cost of the edits needed
+// to change string a into string b. The edits are the insertion, deletion
+// and substitution of a single character and the transposition of two
+// adjacent characters and their costs are given by the EditCosts. As for
+// the OSADistance, no substring is edited more than once.
+//
+// Note that if the costs of insertion and deletion differ, or the
+// substitution costs are not symmetric, then the distance from a to b will
+// not be the same as the distance from b to a.
+func WeightedEditDistance(a, b string, costs EditCosts) float64 {
+	aRunes := []rune(a)
+	bRunes := []rune(b)
+	aLen := len(aRunes)
+	bLen := len(bRunes)
+
+	d := make([][]float64, aLen+1)
+	for i := range d {
+		d[i] = make([]float64, bLen+1)
+		d[i][0] = float64(i) * costs.Delete
+	}
+
+	for j := 1; j <= bLen; j++ {
+		d[0][j] = float64(j) * costs.Insert
+	}
+
+	for i := 1; i <= aLen; i++ {
+		for j := 1; j <= bLen; j++ {
+			del := d[i-1][j] + costs.Delete
+			ins := d[i][j-1] + costs.Insert
+			sub := d[i-1][j-1] + costs.subsCost(aRunes[i-1], bRunes[j-1])
+
+			d[i][j] = math.Min(del, math.Min(ins, sub))
+
+			if i > 1 && j > 1 &&
+				aRunes[i-1] != aRunes[i-2] &&
+				aRunes[i-1] == bRunes[j-2] &&
+				aRunes[i-2] == bRunes[j-1] {
+				d[i][j] = math.Min(d[i][j], d[i-2][j-2]+costs.Transpose)
+			}
+		}
+	}
+
+	return d[aLen][bLen]
+}
@@ -0,0 +1,208 @@
+package strdist_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+	"github.com/nickwells/golem/testhelper"
+)
+
+func TestWeightedEditDistance(t *testing.T) {
+	const epsilon = 0.000001
+	qwerty := strdist.KeyboardEditCosts(strdist.QWERTY, 0.5)
+	cheapDelete := strdist.UnitEditCosts()
+	cheapDelete.Delete = 0.25
+
+	testCases := []struct {
+		name    string
+		a, b    string
+		costs   strdist.EditCosts
+		expDist float64
+	}{
+		{
+			name:  "zero char same",
+			costs: strdist.UnitEditCosts(),
+		},
+		{
+			name:    "unit costs, Kitten/Sitting",
+			a:       "Kitten",
+			b:       "Sitting",
+			costs:   strdist.UnitEditCosts(),
+			expDist: 3,
+		},
+		{
+			name:    "unit costs, transposition",
+			a:       "pramas",
+			b:       "parmas",
+			costs:   strdist.UnitEditCosts(),
+			expDist: 1,
+		},
+		{
+			name:    "keyboard, adjacent key",
+			a:       "nane",
+			b:       "name",
+			costs:   qwerty,
+			expDist: 0.5,
+		},
+		{
+			name:    "keyboard, distant key",
+			a:       "nale",
+			b:       "name",
+			costs:   qwerty,
+			expDist: 1,
+		},
+		{
+			name:    "keyboard, shifted",
+			a:       "Name",
+			b:       "name",
+			costs:   qwerty,
+			expDist: 0.5,
+		},
+		{
+			name:    "keyboard, insertion",
+			a:       "nam",
+			b:       "name",
+			costs:   qwerty,
+			expDist: 1,
+		},
+		{
+			name:    "cheap delete",
+			a:       "names",
+			b:       "name",
+			costs:   cheapDelete,
+			expDist: 0.25,
+		},
+		{
+			name:    "cheap delete - reversed",
+			a:       "name",
+			b:       "names",
+			costs:   cheapDelete,
+			expDist: 1,
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		dist := strdist.WeightedEditDistance(tc.a, tc.b, tc.costs)
+		if math.Abs(dist-tc.expDist) > epsilon {
+			t.Log(tcID)
+			t.Errorf("\t: WeightedEditDistance(%q, %q) expected: %.3f got: %.3f",
+				tc.a, tc.b, tc.expDist, dist)
+		}
+	}
+}
+
+func TestWeightedEditUnitCosts(t *testing.T) {
+	pairs := [][2]string{
+		{"ab", "ba"},
+		{"ca", "abc"},
+		{"receive", "recieve"},
+		{"Saturday", "Sunday"},
+		{"größe", "grßöe"},
+	}
+	for i, p := range pairs {
+		tcID := fmt.Sprintf("test %d: %q/%q", i, p[0], p[1])
+		dist := strdist.WeightedEditDistance(p[0], p[1],
+			strdist.UnitEditCosts())
+		osaDist := strdist.OSADistance(p[0], p[1])
+		if dist != float64(osaDist) {
+			t.Log(tcID)
+			t.Errorf("\t: with unit costs the distance (%.3f)"+
+				" should equal the OSADistance (%d)", dist, osaDist)
+		}
+	}
+}
+
+func TestNewWeightedEditFinder(t *testing.T) {
+	testCases := []struct {
+		name      string
+		costs     strdist.EditCosts
+		expErr    bool
+		expErrStr string
+	}{
+		{
+			name:  "good",
+			costs: strdist.UnitEditCosts(),
+		},
+		{
+			name:      "bad insertion cost",
+			costs:     strdist.EditCosts{Delete: 1, Transpose: 1},
+			expErr:    true,
+			expErrStr: "bad insertion cost (0.000000) - it should be > 0.0",
+		},
+		{
+			name:      "bad deletion cost",
+			costs:     strdist.EditCosts{Insert: 1, Delete: -1, Transpose: 1},
+			expErr:    true,
+			expErrStr: "bad deletion cost (-1.000000) - it should be > 0.0",
+		},
+		{
+			name:      "bad transposition cost",
+			costs:     strdist.EditCosts{Insert: 1, Delete: 1},
+			expErr:    true,
+			expErrStr: "bad transposition cost (0.000000) - it should be > 0.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		_, err := strdist.NewWeightedEditFinder(
+			tc.costs, 4, 1.0, strdist.NoCaseChange)
+		if tc.expErr {
+			if err == nil {
+				t.Log(tcID)
+				t.Errorf("\t: an error was expected but none was returned")
+			} else if err.Error() != tc.expErrStr {
+				t.Log(tcID)
+				t.Logf("\t: expected: %s", tc.expErrStr)
+				t.Logf("\t:      got: %s", err)
+				t.Errorf("\t: unexpected error")
+			}
+		} else if err != nil {
+			t.Log(tcID)
+			t.Errorf("\t: unexpected error: %s", err)
+		}
+	}
+}
+
+func TestKeyboardFinder(t *testing.T) {
+	pop := []string{"name", "Name", "game", "nose", "names", "value"}
+	testCases := []struct {
+		name   string
+		finder *strdist.Finder
+		target string
+		expect []string
+	}{
+		{
+			name:   "dflt",
+			finder: strdist.DfltKeyboardFinder,
+			target: "nane",
+			expect: []string{"name", "Name", "game", "names", "nose"},
+		},
+		{
+			name:   "case-blind",
+			finder: strdist.CaseBlindKeyboardFinder,
+			target: "nane",
+			expect: []string{"Name", "name", "game", "names", "nose"},
+		},
+		{
+			name:   "dflt, adjacent key typo",
+			finder: strdist.DfltKeyboardFinder,
+			target: "vakue",
+			expect: []string{"value", "game"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s", i, tc.name)
+		results := tc.finder.FindStrLike(tc.target, pop...)
+		if testhelper.StringSliceDiff(tc.expect, results) {
+			t.Log(tcID)
+			t.Logf("\t: expected: %v", tc.expect)
+			t.Logf("\t:      got: %v", results)
+			t.Errorf("\t: results are unexpected\n")
+		}
+	}
+}
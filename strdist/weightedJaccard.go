@@ -2,7 +2,6 @@ package strdist
 
 import (
 	"fmt"
-	"strings"
 )
 
 const DfltWeightedJaccardThreshold = 0.33
@@ -36,7 +35,13 @@ func init() {
 // WeightedJaccardAlgo encapsulates the details needed to provide the
 // WeightedJaccard distance.
 type WeightedJaccardAlgo struct {
-	N         int
+	N int
+}
+
+// weightedJaccardQuery is the Query returned by a WeightedJaccardAlgo
+type weightedJaccardQuery struct {
+	n         int
+	cm        CaseMod
 	ngsTarget NGramSet
 }
 
@@ -56,28 +61,18 @@ func NewWeightedJaccardFinder(ngLen, minStrLen int, threshold float64, cm CaseMo
 	return NewFinder(minStrLen, threshold, cm, algo)
 }
 
-// Prep for a WeightedJaccardAlgo will pre-calculate the n-gram set for the
-// target string
-func (a *WeightedJaccardAlgo) Prep(s string, cm CaseMod) {
-	switch cm {
-	case ForceToLower:
-		a.ngsTarget, _ = NGrams(strings.ToLower(s), a.N)
-	default:
-		a.ngsTarget, _ = NGrams(s, a.N)
-	}
+// PrepQuery for a WeightedJaccardAlgo will pre-calculate the n-gram set for
+// the target string
+func (a *WeightedJaccardAlgo) PrepQuery(s string, cm CaseMod) Query {
+	ngs, _ := NGrams(cm.Apply(s), a.N)
+	return weightedJaccardQuery{n: a.N, cm: cm, ngsTarget: ngs}
 }
 
-// Dist for a WeightedJaccardAlgo will calculate the distance from the target
-// string
-func (a *WeightedJaccardAlgo) Dist(_, s string, cm CaseMod) float64 {
-	var ngs NGramSet
-	switch cm {
-	case ForceToLower:
-		ngs, _ = NGrams(strings.ToLower(s), a.N)
-	default:
-		ngs, _ = NGrams(s, a.N)
-	}
-	return 1.0 - WeightedJaccardIndex(a.ngsTarget, ngs)
+// Dist for a weightedJaccardQuery will calculate the distance from the
+// target string
+func (q weightedJaccardQuery) Dist(s string) float64 {
+	ngs, _ := NGrams(q.cm.Apply(s), q.n)
+	return 1.0 - WeightedJaccardIndex(q.ngsTarget, ngs)
 }
 
 // WeightedJaccardIndex returns the Weighted Jaccard index of the two n-gram
@@ -0,0 +1,44 @@
+package testhelper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/golem/strdist"
+)
+
+// diffContext is the number of matching lines shown either side of a
+// difference
+const diffContext = 3
+
+// DiffLines checks that the string got is the same as the string expected
+// and reports an error if it is not. The error shows a minimal line-by-line
+// difference between the two values: lines missing from got are prefixed
+// with "- " and extra lines with "+ ". The desc parameter is used to
+// describe the string being checked. It returns true if a problem was
+// found, false otherwise.
+func DiffLines(t *testing.T, testID, desc, got, expected string) bool {
+	t.Helper()
+
+	if got == expected {
+		return false
+	}
+
+	t.Log(testID)
+	t.Errorf("\t: an unexpected %s value was seen (- expected, + got):\n",
+		desc)
+	for _, l := range lineDiff(expected, got) {
+		t.Log("\t\t", l)
+	}
+	return true
+}
+
+// lineDiff returns the formatted line-by-line difference between the
+// expected and got strings
+func lineDiff(expected, got string) []string {
+	return strdist.FormatLineAlign(
+		strdist.AlignLines(
+			strings.Split(expected, "\n"),
+			strings.Split(got, "\n")),
+		diffContext)
+}
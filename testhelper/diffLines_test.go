@@ -0,0 +1,54 @@
+package testhelper
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		got      string
+		diff     []string
+	}{
+		{
+			name:     "same",
+			expected: "a\nb",
+			got:      "a\nb",
+			diff:     []string{"... 2 matching lines ..."},
+		},
+		{
+			name:     "changed line",
+			expected: "a\nb\nc",
+			got:      "a\nB\nc",
+			diff:     []string{"  a", "- b", "+ B", "  c"},
+		},
+		{
+			name:     "missing and extra lines",
+			expected: "a\nb\nc",
+			got:      "b\nc\nd",
+			diff:     []string{"- a", "  b", "  c", "+ d"},
+		},
+		{
+			name:     "long matching runs are skipped",
+			expected: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10",
+			got:      "1\n2\n3\n4\n5\n6\n7\n8\n9\nten",
+			diff: []string{
+				"... 6 matching lines ...",
+				"  7", "  8", "  9", "- 10", "+ ten",
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		tcID := fmt.Sprintf("test %d: %s :\n", i, tc.name)
+		diff := lineDiff(tc.expected, tc.got)
+		if StringSliceDiff(diff, tc.diff) {
+			t.Log(tcID)
+			t.Logf("\t: expected: %q", tc.diff)
+			t.Logf("\t:      got: %q", diff)
+			t.Errorf("\t: lineDiff did not return the expected results\n")
+		}
+	}
+}